@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"strings"
 
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -54,16 +55,23 @@ type EndpointPickerConfig struct {
 	// +optional
 	// Data configures the DataLayer. It is required if the new DataLayer is enabled.
 	Data *DataLayerConfig `json:"data"`
+
+	// +optional
+	// FlowControl when present specifies the configuration of the experimental
+	// Flow Control layer. It is only used if the flowControl feature gate is enabled.
+	// If not present, default values are used.
+	FlowControl *FlowControlConfig `json:"flowControl,omitempty"`
 }
 
 func (cfg EndpointPickerConfig) String() string {
 	return fmt.Sprintf(
-		"{FeatureGates: %v, Plugins: %v, SchedulingProfiles: %v, Data: %v, SaturationDetector: %v}",
+		"{FeatureGates: %v, Plugins: %v, SchedulingProfiles: %v, Data: %v, SaturationDetector: %v, FlowControl: %v}",
 		cfg.FeatureGates,
 		cfg.Plugins,
 		cfg.SchedulingProfiles,
 		cfg.Data,
 		cfg.SaturationDetector,
+		cfg.FlowControl,
 	)
 }
 
@@ -245,3 +253,159 @@ type DataLayerExtractor struct {
 func (dle DataLayerExtractor) String() string {
 	return "{PluginRef: " + dle.PluginRef + "}"
 }
+
+// FlowControlConfig contains the configuration of the experimental Flow Control layer
+type FlowControlConfig struct {
+	// +optional
+	// MaxBytes defines an optional global limit on the total byte size of requests
+	// buffered across all priority bands. If omitted or zero, only the per-band
+	// limits apply.
+	MaxBytes *resource.Quantity `json:"maxBytes,omitempty"`
+
+	// +optional
+	// InitialShardCount specifies the number of parallel shards to create when
+	// the flow registry is initialized. If omitted, the system default is used.
+	InitialShardCount int `json:"initialShardCount,omitempty"`
+
+	// +optional
+	// FlowGCTimeout defines the duration of inactivity after which an idle flow
+	// is garbage collected. If omitted, the system default is used.
+	FlowGCTimeout metav1.Duration `json:"flowGCTimeout,omitempty"`
+
+	// +optional
+	// PriorityBandGCTimeout defines the duration of inactivity after which a
+	// dynamically provisioned priority band is garbage collected. It must not be
+	// smaller than FlowGCTimeout. If omitted, twice the FlowGCTimeout is used.
+	PriorityBandGCTimeout metav1.Duration `json:"priorityBandGCTimeout,omitempty"`
+
+	// +optional
+	// DefaultRequestTTL is the Time-To-Live applied to queued requests that do
+	// not specify their own TTL. If omitted, requests are only bounded by their
+	// context.
+	DefaultRequestTTL metav1.Duration `json:"defaultRequestTTL,omitempty"`
+
+	// +optional
+	// MinRequestTTL is the lower bound of the TTLs requested by InferenceObjectives
+	// and clients. If omitted, the requested TTLs are not bounded from below.
+	MinRequestTTL metav1.Duration `json:"minRequestTTL,omitempty"`
+
+	// +optional
+	// MaxRequestTTL is the upper bound of the TTLs requested by InferenceObjectives
+	// and clients. If omitted, the requested TTLs are not bounded from above.
+	MaxRequestTTL metav1.Duration `json:"maxRequestTTL,omitempty"`
+
+	// +optional
+	// EnqueueChannelBufferSize is the size of the buffer that accepts incoming
+	// requests for each shard processor. If omitted, the system default is used.
+	EnqueueChannelBufferSize int `json:"enqueueChannelBufferSize,omitempty"`
+
+	// +optional
+	// EnablePreemption enables the eviction of queued requests of lower priority
+	// to make room for requests of higher priority when the global capacity is
+	// exhausted. If omitted, requests that do not fit are rejected.
+	EnablePreemption bool `json:"enablePreemption,omitempty"`
+
+	// +optional
+	// PriorityBands is the list of statically configured priority bands.
+	PriorityBands []PriorityBand `json:"priorityBands,omitempty"`
+
+	// +optional
+	// DefaultPriorityBand is the template used to provision priority bands for
+	// priority levels that are not explicitly configured. Its Priority field is
+	// ignored. If omitted, system defaults are used.
+	DefaultPriorityBand *PriorityBand `json:"defaultPriorityBand,omitempty"`
+}
+
+func (fcc *FlowControlConfig) String() string {
+	if fcc == nil {
+		return "{}"
+	}
+	result := fmt.Sprintf("PriorityBands: %v", fcc.PriorityBands)
+	if fcc.DefaultPriorityBand != nil {
+		result += fmt.Sprintf(", DefaultPriorityBand: %v", *fcc.DefaultPriorityBand)
+	}
+	if fcc.MaxBytes != nil {
+		result += ", MaxBytes: " + fcc.MaxBytes.String()
+	}
+	if fcc.InitialShardCount != 0 {
+		result += fmt.Sprintf(", InitialShardCount: %d", fcc.InitialShardCount)
+	}
+	if fcc.FlowGCTimeout.Duration != 0 {
+		result += fmt.Sprintf(", FlowGCTimeout: %s", fcc.FlowGCTimeout)
+	}
+	if fcc.PriorityBandGCTimeout.Duration != 0 {
+		result += fmt.Sprintf(", PriorityBandGCTimeout: %s", fcc.PriorityBandGCTimeout)
+	}
+	if fcc.DefaultRequestTTL.Duration != 0 {
+		result += fmt.Sprintf(", DefaultRequestTTL: %s", fcc.DefaultRequestTTL)
+	}
+	if fcc.MinRequestTTL.Duration != 0 {
+		result += fmt.Sprintf(", MinRequestTTL: %s", fcc.MinRequestTTL)
+	}
+	if fcc.MaxRequestTTL.Duration != 0 {
+		result += fmt.Sprintf(", MaxRequestTTL: %s", fcc.MaxRequestTTL)
+	}
+	if fcc.EnqueueChannelBufferSize != 0 {
+		result += fmt.Sprintf(", EnqueueChannelBufferSize: %d", fcc.EnqueueChannelBufferSize)
+	}
+	if fcc.EnablePreemption {
+		result += ", EnablePreemption: true"
+	}
+	return "{" + result + "}"
+}
+
+// PriorityBand contains the configuration of a single Flow Control priority band
+type PriorityBand struct {
+	// +required
+	// +kubebuilder:validation:Required
+	// Priority is the numerical priority level of this band. Higher values
+	// are dispatched first.
+	Priority int `json:"priority"`
+
+	// +optional
+	// Name is a human-readable name for this band. It must be unique across
+	// all priority bands. If omitted, a name is derived from the Priority.
+	Name string `json:"name,omitempty"`
+
+	// +optional
+	// OrderingPolicyRef specifies the name of the OrderingPolicy plugin instance,
+	// defined in the configuration's Plugins section, that selects the next
+	// request within a flow's queue.
+	OrderingPolicyRef string `json:"orderingPolicyRef,omitempty"`
+
+	// +optional
+	// FairnessPolicyRef specifies the name of the FairnessPolicy plugin instance,
+	// defined in the configuration's Plugins section, that selects the next
+	// flow within this band.
+	FairnessPolicyRef string `json:"fairnessPolicyRef,omitempty"`
+
+	// +optional
+	// Queue specifies the name of the queue implementation used by flows in
+	// this band (e.g. ListQueue, MaxMinHeap).
+	Queue string `json:"queue,omitempty"`
+
+	// +optional
+	// MaxBytes defines the capacity of this band, aggregated across all shards.
+	// If specified, it must be positive. If omitted, the system default is used.
+	MaxBytes *resource.Quantity `json:"maxBytes,omitempty"`
+}
+
+func (pb PriorityBand) String() string {
+	result := fmt.Sprintf("{Priority: %d", pb.Priority)
+	if pb.Name != "" {
+		result += ", Name: " + pb.Name
+	}
+	if pb.OrderingPolicyRef != "" {
+		result += ", OrderingPolicyRef: " + pb.OrderingPolicyRef
+	}
+	if pb.FairnessPolicyRef != "" {
+		result += ", FairnessPolicyRef: " + pb.FairnessPolicyRef
+	}
+	if pb.Queue != "" {
+		result += ", Queue: " + pb.Queue
+	}
+	if pb.MaxBytes != nil {
+		result += ", MaxBytes: " + pb.MaxBytes.String()
+	}
+	return result + "}"
+}
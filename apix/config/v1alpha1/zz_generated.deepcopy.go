@@ -115,6 +115,11 @@ func (in *EndpointPickerConfig) DeepCopyInto(out *EndpointPickerConfig) {
 		*out = new(DataLayerConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.FlowControl != nil {
+		in, out := &in.FlowControl, &out.FlowControl
+		*out = new(FlowControlConfig)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EndpointPickerConfig.
@@ -154,6 +159,43 @@ func (in FeatureGates) DeepCopy() FeatureGates {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlowControlConfig) DeepCopyInto(out *FlowControlConfig) {
+	*out = *in
+	if in.MaxBytes != nil {
+		in, out := &in.MaxBytes, &out.MaxBytes
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	out.FlowGCTimeout = in.FlowGCTimeout
+	out.PriorityBandGCTimeout = in.PriorityBandGCTimeout
+	out.DefaultRequestTTL = in.DefaultRequestTTL
+	out.MinRequestTTL = in.MinRequestTTL
+	out.MaxRequestTTL = in.MaxRequestTTL
+	if in.PriorityBands != nil {
+		in, out := &in.PriorityBands, &out.PriorityBands
+		*out = make([]PriorityBand, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DefaultPriorityBand != nil {
+		in, out := &in.DefaultPriorityBand, &out.DefaultPriorityBand
+		*out = new(PriorityBand)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowControlConfig.
+func (in *FlowControlConfig) DeepCopy() *FlowControlConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(FlowControlConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PluginSpec) DeepCopyInto(out *PluginSpec) {
 	*out = *in
@@ -174,6 +216,26 @@ func (in *PluginSpec) DeepCopy() *PluginSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PriorityBand) DeepCopyInto(out *PriorityBand) {
+	*out = *in
+	if in.MaxBytes != nil {
+		in, out := &in.MaxBytes, &out.MaxBytes
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PriorityBand.
+func (in *PriorityBand) DeepCopy() *PriorityBand {
+	if in == nil {
+		return nil
+	}
+	out := new(PriorityBand)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SaturationDetector) DeepCopyInto(out *SaturationDetector) {
 	*out = *in
@@ -72,6 +72,15 @@ type InferenceObjectiveSpec struct {
 	// +optional
 	Priority *int `json:"priority,omitempty"`
 
+	// RequestTTL defines how long requests of this objective may wait in the flow control queues before being
+	// dispatched, e.g. seconds for interactive objectives and minutes for batch ones. Requests still waiting once their
+	// TTL has expired are rejected.
+	// A client may shorten the TTL of its request with the x-gateway-inference-request-ttl header.
+	// The TTL is bounded by the limits configured in the Endpoint Picker. If unset, the default TTL of the Endpoint
+	// Picker is used.
+	// +optional
+	RequestTTL *metav1.Duration `json:"requestTTL,omitempty"`
+
 	// PoolRef is a reference to the inference pool, the pool must exist in the same namespace.
 	//
 	// +kubebuilder:validation:Required
@@ -262,6 +262,11 @@ func (in *InferenceObjectiveSpec) DeepCopyInto(out *InferenceObjectiveSpec) {
 		*out = new(int)
 		**out = **in
 	}
+	if in.RequestTTL != nil {
+		in, out := &in.RequestTTL, &out.RequestTTL
+		*out = new(v1.Duration)
+		**out = **in
+	}
 	out.PoolRef = in.PoolRef
 }
 
@@ -18,11 +18,16 @@ limitations under the License.
 
 package v1alpha2
 
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
 // InferenceObjectiveSpecApplyConfiguration represents a declarative configuration of the InferenceObjectiveSpec type for use
 // with apply.
 type InferenceObjectiveSpecApplyConfiguration struct {
-	Priority *int                                   `json:"priority,omitempty"`
-	PoolRef  *PoolObjectReferenceApplyConfiguration `json:"poolRef,omitempty"`
+	Priority   *int                                   `json:"priority,omitempty"`
+	RequestTTL *metav1.Duration                       `json:"requestTTL,omitempty"`
+	PoolRef    *PoolObjectReferenceApplyConfiguration `json:"poolRef,omitempty"`
 }
 
 // InferenceObjectiveSpecApplyConfiguration constructs a declarative configuration of the InferenceObjectiveSpec type for use with
@@ -39,6 +44,14 @@ func (b *InferenceObjectiveSpecApplyConfiguration) WithPriority(value int) *Infe
 	return b
 }
 
+// WithRequestTTL sets the RequestTTL field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the RequestTTL field is set to the value of the last call.
+func (b *InferenceObjectiveSpecApplyConfiguration) WithRequestTTL(value metav1.Duration) *InferenceObjectiveSpecApplyConfiguration {
+	b.RequestTTL = &value
+	return b
+}
+
 // WithPoolRef sets the PoolRef field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the PoolRef field is set to the value of the last call.
@@ -54,6 +54,7 @@ import (
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/config"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/config/loader"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datalayer"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datalayer/kvevents"
 	dlmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datalayer/metrics"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datastore"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/flowcontrol"
@@ -65,9 +66,12 @@ import (
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metrics"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metrics/collectors"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/requestcontrol"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/requestcontrol/plugins/outlierdetection"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/requestcontrol/plugins/ratelimit"
 	testresponsereceived "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/requestcontrol/plugins/test/responsereceived"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/saturationdetector/framework/plugins/utilizationdetector"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/filter"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/multi/predicted_latency"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/multi/prefix"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/picker"
@@ -114,6 +118,7 @@ type Runner struct {
 	featureGates         map[string]bool
 	requestControlConfig *requestcontrol.Config
 	schedulerConfig      *scheduling.SchedulerConfig
+	endpointEjectors     []requestcontrol.EndpointEjector
 	customCollectors     []prometheus.Collector
 }
 
@@ -318,6 +323,12 @@ func (r *Runner) Run(ctx context.Context) error {
 	locator = requestcontrol.NewDatastorePodLocator(ds, requestcontrol.WithDisableEndpointSubsetFilter(opts.DisableEndpointSubsetFilter))
 	if r.featureGates[flowcontrol.FeatureGate] {
 		locator = requestcontrol.NewCachedPodLocator(ctx, locator, time.Millisecond*50)
+	}
+	// The ejections are applied on top of the cache, so that they take effect immediately.
+	for _, ejector := range r.endpointEjectors {
+		locator = requestcontrol.NewOutlierEjectingPodLocator(locator, ejector)
+	}
+	if r.featureGates[flowcontrol.FeatureGate] {
 		setupLog.Info("Initializing experimental Flow Control layer")
 		registry, err := fcregistry.NewFlowRegistry(eppConfig.FlowControlConfig.Registry, setupLog)
 		if err != nil {
@@ -334,6 +345,7 @@ func (r *Runner) Run(ctx context.Context) error {
 		}
 		go registry.Run(ctx)
 		admissionController = requestcontrol.NewFlowControlAdmissionController(fc, opts.PoolName)
+		r.requestControlConfig.WithDefaultRequestTTL(eppConfig.FlowControlConfig.Controller.DefaultRequestTTL)
 	} else {
 		setupLog.Info("Experimental Flow Control layer is disabled, using legacy admission control")
 		admissionController = requestcontrol.NewLegacyAdmissionController(saturationDetector, locator)
@@ -409,13 +421,25 @@ func (r *Runner) registerInTreePlugins() {
 	fwkplugin.Register(picker.MaxScorePickerType, picker.MaxScorePickerFactory)
 	fwkplugin.Register(picker.RandomPickerType, picker.RandomPickerFactory)
 	fwkplugin.Register(picker.WeightedRandomPickerType, picker.WeightedRandomPickerFactory)
+	fwkplugin.Register(picker.P2CPickerType, picker.P2CPickerFactory)
+	fwkplugin.Register(picker.TopKOrderedPickerType, picker.TopKOrderedPickerFactory)
 	fwkplugin.Register(profile.SingleProfileHandlerType, profile.SingleProfileHandlerFactory)
+	fwkplugin.Register(profile.PDProfileHandlerType, profile.PDProfileHandlerFactory)
 	fwkplugin.Register(scorer.KvCacheUtilizationScorerType, scorer.KvCacheUtilizationScorerFactory)
 	fwkplugin.Register(scorer.QueueScorerType, scorer.QueueScorerFactory)
 	fwkplugin.Register(scorer.RunningRequestsSizeScorerType, scorer.RunningRequestsSizeScorerFactory)
 	fwkplugin.Register(scorer.LoraAffinityScorerType, scorer.LoraAffinityScorerFactory)
+	fwkplugin.Register(scorer.SessionAffinityScorerType, scorer.SessionAffinityScorerFactory)
+	fwkplugin.Register(filter.LoraAffinityFilterType, filter.LoraAffinityFilterFactory)
+	fwkplugin.Register(filter.LowQueueFilterType, filter.LowQueueFilterFactory)
+	fwkplugin.Register(filter.KVCacheThresholdFilterType, filter.KVCacheThresholdFilterFactory)
+	fwkplugin.Register(filter.LabelSelectorFilterType, filter.LabelSelectorFilterFactory)
 	// Latency predictor plugins
 	fwkplugin.Register(predicted_latency.PredictedLatencyPluginType, predicted_latency.PredictedLatencyFactory)
+	// Outlier detection plugins
+	fwkplugin.Register(outlierdetection.OutlierDetectorType, outlierdetection.OutlierDetectorFactory)
+	// Rate limiting plugins
+	fwkplugin.Register(ratelimit.TokenBucketRateLimiterType, ratelimit.TokenBucketRateLimiterFactory)
 	// register filter for test purpose only (used in conformance tests)
 	fwkplugin.Register(testfilter.HeaderBasedTestingFilterType, testfilter.HeaderBasedTestingFilterFactory)
 	// register response received plugin for test purpose only (used in conformance tests)
@@ -423,6 +447,8 @@ func (r *Runner) registerInTreePlugins() {
 	// register datalayer metrics collection plugins
 	fwkplugin.Register(dlmetrics.MetricsDataSourceType, dlmetrics.MetricsDataSourceFactory)
 	fwkplugin.Register(dlmetrics.MetricsExtractorType, dlmetrics.ModelServerExtractorFactory)
+	// register datalayer KV events data source, providing an exact index to the prefix-cache-scorer
+	fwkplugin.Register(kvevents.KVEventsDataSourceType, kvevents.DataSourceFactory)
 }
 
 func (r *Runner) parseConfigurationPhaseOne(ctx context.Context, opts *runserver.Options) (*configapi.EndpointPickerConfig, error) {
@@ -485,6 +511,11 @@ func (r *Runner) parseConfigurationPhaseTwo(ctx context.Context, rawConfig *conf
 
 	// Add requestControl plugins
 	r.requestControlConfig.AddPlugins(handle.GetAllPlugins()...)
+	for _, plugin := range handle.GetAllPlugins() {
+		if ejector, ok := plugin.(requestcontrol.EndpointEjector); ok {
+			r.endpointEjectors = append(r.endpointEjectors, ejector)
+		}
+	}
 
 	// Sort prepare data plugins in DAG order (topological sort). Also check prepare data plugins for cycles.
 	if r.requestControlConfig.PrepareDataPluginGraph() != nil {
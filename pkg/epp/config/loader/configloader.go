@@ -31,6 +31,7 @@ import (
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datalayer"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/flowcontrol"
 	fccontroller "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/flowcontrol/controller"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/flowcontrol/framework/plugins/queue"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/flowcontrol/registry"
 	fwkplugin "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/plugin"
 	framework "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/scheduling"
@@ -107,15 +108,7 @@ func InstantiateAndConfigure(
 
 	var flowControlConfig *flowcontrol.Config
 	if featureGates[flowcontrol.FeatureGate] {
-		registryConfig, err := registry.NewConfig(handle)
-		if err != nil {
-			return nil, fmt.Errorf("flow registgry config build failed: %w", err)
-		}
-		cfg := &flowcontrol.Config{
-			Controller: fccontroller.Config{},
-			Registry:   registryConfig,
-		}
-		flowControlConfig, err = cfg.ValidateAndApplyDefaults()
+		flowControlConfig, err = buildFlowControlConfig(rawConfig.FlowControl, handle)
 		if err != nil {
 			return nil, fmt.Errorf("flow control config build failed: %w", err)
 		}
@@ -286,3 +279,89 @@ func buildDataLayerConfig(rawDataConfig *configapi.DataLayerConfig, dataLayerEna
 	}
 	return &cfg, nil
 }
+
+func buildFlowControlConfig(rawFlowControlConfig *configapi.FlowControlConfig, handle fwkplugin.Handle) (*flowcontrol.Config, error) {
+	if rawFlowControlConfig == nil {
+		rawFlowControlConfig = &configapi.FlowControlConfig{}
+	}
+
+	opts := []registry.ConfigOption{}
+	if rawFlowControlConfig.MaxBytes != nil {
+		opts = append(opts, registry.WithMaxBytes(uint64(rawFlowControlConfig.MaxBytes.Value())))
+	}
+	if rawFlowControlConfig.InitialShardCount != 0 {
+		opts = append(opts, registry.WithInitialShardCount(rawFlowControlConfig.InitialShardCount))
+	}
+	if rawFlowControlConfig.FlowGCTimeout.Duration != 0 {
+		opts = append(opts, registry.WithFlowGCTimeout(rawFlowControlConfig.FlowGCTimeout.Duration))
+	}
+	if rawFlowControlConfig.PriorityBandGCTimeout.Duration != 0 {
+		opts = append(opts, registry.WithPriorityBandGCTimeout(rawFlowControlConfig.PriorityBandGCTimeout.Duration))
+	} else if rawFlowControlConfig.FlowGCTimeout.Duration != 0 {
+		// Keep the registry's rule of collecting idle bands only after their flows have been collected.
+		opts = append(opts, registry.WithPriorityBandGCTimeout(2*rawFlowControlConfig.FlowGCTimeout.Duration))
+	}
+	for _, rawBand := range rawFlowControlConfig.PriorityBands {
+		band, err := buildPriorityBandConfig(rawBand, handle)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, registry.WithPriorityBand(band))
+	}
+	if rawFlowControlConfig.DefaultPriorityBand != nil {
+		rawBand := *rawFlowControlConfig.DefaultPriorityBand
+		rawBand.Priority = 0
+		if rawBand.Name == "" {
+			rawBand.Name = "Dynamic-Default"
+		}
+		band, err := buildPriorityBandConfig(rawBand, handle)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, registry.WithDefaultPriorityBand(band))
+	}
+
+	registryConfig, err := registry.NewConfig(handle, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("flow registry config build failed: %w", err)
+	}
+
+	cfg := &flowcontrol.Config{
+		Controller: fccontroller.Config{
+			DefaultRequestTTL:        rawFlowControlConfig.DefaultRequestTTL.Duration,
+			MinRequestTTL:            rawFlowControlConfig.MinRequestTTL.Duration,
+			MaxRequestTTL:            rawFlowControlConfig.MaxRequestTTL.Duration,
+			EnqueueChannelBufferSize: rawFlowControlConfig.EnqueueChannelBufferSize,
+			EnablePreemption:         rawFlowControlConfig.EnablePreemption,
+		},
+		Registry: registryConfig,
+	}
+	return cfg.ValidateAndApplyDefaults()
+}
+
+func buildPriorityBandConfig(rawBand configapi.PriorityBand, handle fwkplugin.Handle) (*registry.PriorityBandConfig, error) {
+	name := rawBand.Name
+	if name == "" {
+		name = fmt.Sprintf("priority-%d", rawBand.Priority)
+	}
+
+	opts := []registry.PriorityBandConfigOption{}
+	if rawBand.OrderingPolicyRef != "" {
+		opts = append(opts, registry.WithOrderingPolicy(rawBand.OrderingPolicyRef, handle))
+	}
+	if rawBand.FairnessPolicyRef != "" {
+		opts = append(opts, registry.WithFairnessPolicy(rawBand.FairnessPolicyRef, handle))
+	}
+	if rawBand.Queue != "" {
+		opts = append(opts, registry.WithQueue(queue.RegisteredQueueName(rawBand.Queue)))
+	}
+	if rawBand.MaxBytes != nil {
+		opts = append(opts, registry.WithBandMaxBytes(uint64(rawBand.MaxBytes.Value())))
+	}
+
+	band, err := registry.NewPriorityBandConfig(handle, rawBand.Priority, name, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build priority band '%s': %w", name, err)
+	}
+	return band, nil
+}
@@ -33,6 +33,9 @@ import (
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/common/util/logging"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datalayer"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/flowcontrol"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/flowcontrol/framework/plugins/interflow"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/flowcontrol/framework/plugins/intraflow"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/flowcontrol/framework/plugins/queue"
 	fwkplugin "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/plugin"
 	framework "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/scheduling"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/saturationdetector/framework/plugins/utilizationdetector"
@@ -161,6 +164,7 @@ func TestInstantiateAndConfigure(t *testing.T) {
 		name       string
 		configText string
 		wantErr    bool
+		wantErrMsg string
 		validate   func(t *testing.T, handle fwkplugin.Handle, cfg *configapi.EndpointPickerConfig)
 	}{
 		// --- Success Scenarios ---
@@ -304,6 +308,42 @@ func TestInstantiateAndConfigure(t *testing.T) {
 			configText: errorBadExtractorReferenceText,
 			wantErr:    true,
 		},
+		{
+			name:       "Error - Flow Control Undefined Policy",
+			configText: errorFlowControlUndefinedPolicyText,
+			wantErr:    true,
+			wantErrMsg: `references undefined fairness policy 'round-robin-fairness-policy'`,
+		},
+		{
+			name:       "Error - Flow Control Wrong Policy Type",
+			configText: errorFlowControlWrongPolicyTypeText,
+			wantErr:    true,
+			wantErrMsg: `plugin "test-scorer" is not a framework.OrderingPolicy`,
+		},
+		{
+			name:       "Error - Flow Control Duplicate Priority",
+			configText: errorFlowControlDuplicatePriorityText,
+			wantErr:    true,
+			wantErrMsg: `has duplicate priority 100`,
+		},
+		{
+			name:       "Error - Flow Control Incompatible Queue",
+			configText: errorFlowControlIncompatibleQueueText,
+			wantErr:    true,
+			wantErrMsg: `is not compatible with queue "ListQueue"`,
+		},
+		{
+			name:       "Error - Flow Control Bad GC Timeouts",
+			configText: errorFlowControlBadGCTimeoutsText,
+			wantErr:    true,
+			wantErrMsg: `priorityBandGCTimeout must be >= flowGCTimeout`,
+		},
+		{
+			name:       "Error - Flow Control Zero Band MaxBytes",
+			configText: errorFlowControlZeroBandMaxBytesText,
+			wantErr:    true,
+			wantErrMsg: `priorityBands[0] maxBytes must be positive`,
+		},
 	}
 
 	for _, tc := range tests {
@@ -315,6 +355,9 @@ func TestInstantiateAndConfigure(t *testing.T) {
 			if err != nil {
 				// If we expected failure (and it failed early in Phase 1), success.
 				if tc.wantErr {
+					if tc.wantErrMsg != "" {
+						require.ErrorContains(t, err, tc.wantErrMsg)
+					}
 					return
 				}
 				require.NoError(t, err, "Setup: LoadRawConfig failed")
@@ -326,6 +369,9 @@ func TestInstantiateAndConfigure(t *testing.T) {
 
 			if tc.wantErr {
 				require.Error(t, err, "Expected InstantiateAndConfigure to fail")
+				if tc.wantErrMsg != "" {
+					require.ErrorContains(t, err, tc.wantErrMsg)
+				}
 				return
 			}
 			require.NoError(t, err, "Expected InstantiateAndConfigure to succeed")
@@ -337,6 +383,74 @@ func TestInstantiateAndConfigure(t *testing.T) {
 	}
 }
 
+// Verify the flowControl section is mapped onto the flow control configuration.
+func TestInstantiateAndConfigureFlowControl(t *testing.T) {
+	// Not parallel because it modifies global plugin registry.
+	registerTestPlugins(t)
+	RegisterFeatureGate(flowcontrol.FeatureGate)
+
+	logger := logging.NewTestLogger()
+	rawConfig, _, err := LoadRawConfig([]byte(successFlowControlText), logger)
+	require.NoError(t, err, "Setup: LoadRawConfig failed")
+
+	handle := utils.NewTestHandle(context.Background())
+	cfg, err := InstantiateAndConfigure(rawConfig, handle, logger)
+	require.NoError(t, err, "Expected InstantiateAndConfigure to succeed")
+	require.NotNil(t, cfg.FlowControlConfig, "FlowControlConfig should be built when the feature gate is enabled")
+
+	controllerCfg := cfg.FlowControlConfig.Controller
+	require.Equal(t, 30*time.Second, controllerCfg.DefaultRequestTTL)
+	require.Equal(t, 5*time.Second, controllerCfg.MinRequestTTL)
+	require.Equal(t, 10*time.Minute, controllerCfg.MaxRequestTTL)
+	require.Equal(t, 50, controllerCfg.EnqueueChannelBufferSize)
+	require.True(t, controllerCfg.EnablePreemption)
+
+	registryCfg := cfg.FlowControlConfig.Registry
+	require.Equal(t, uint64(4<<30), registryCfg.MaxBytes)
+	require.Equal(t, 2, registryCfg.InitialShardCount)
+	require.Equal(t, time.Minute, registryCfg.FlowGCTimeout)
+	require.Equal(t, 3*time.Minute, registryCfg.PriorityBandGCTimeout)
+	require.Len(t, registryCfg.PriorityBands, 2)
+
+	critical := registryCfg.PriorityBands[100]
+	require.NotNil(t, critical, "priority band 100 should be configured")
+	require.Equal(t, "Critical", critical.PriorityName)
+	require.Equal(t, intraflow.FCFSOrderingPolicyType, critical.OrderingPolicy.TypedName().Type)
+	require.Equal(t, interflow.RoundRobinFairnessPolicyType, critical.FairnessPolicy.TypedName().Type)
+	require.Equal(t, queue.RegisteredQueueName(queue.ListQueueName), critical.Queue)
+	require.Equal(t, uint64(1<<30), critical.MaxBytes)
+
+	unnamed := registryCfg.PriorityBands[-10]
+	require.NotNil(t, unnamed, "priority band -10 should be configured")
+	require.Equal(t, "priority--10", unnamed.PriorityName, "band name should be derived from its priority")
+
+	defaultBand := registryCfg.DefaultPriorityBand
+	require.NotNil(t, defaultBand)
+	require.Equal(t, interflow.RoundRobinFairnessPolicyType, defaultBand.FairnessPolicy.TypedName().Type)
+	require.Equal(t, uint64(100<<20), defaultBand.MaxBytes)
+}
+
+// Verify that the band GC timeout follows a flow GC timeout set on its own.
+func TestInstantiateAndConfigureFlowControlGCTimeoutDefault(t *testing.T) {
+	// Not parallel because it modifies global plugin registry.
+	registerTestPlugins(t)
+	RegisterFeatureGate(flowcontrol.FeatureGate)
+
+	logger := logging.NewTestLogger()
+	rawConfig, _, err := LoadRawConfig([]byte(successFlowControlFlowGCTimeoutOnlyText), logger)
+	require.NoError(t, err, "Setup: LoadRawConfig failed")
+
+	handle := utils.NewTestHandle(context.Background())
+	cfg, err := InstantiateAndConfigure(rawConfig, handle, logger)
+	require.NoError(t, err, "Expected InstantiateAndConfigure to succeed")
+	require.NotNil(t, cfg.FlowControlConfig, "FlowControlConfig should be built when the feature gate is enabled")
+
+	registryCfg := cfg.FlowControlConfig.Registry
+	require.Equal(t, 15*time.Minute, registryCfg.FlowGCTimeout)
+	require.Equal(t, 30*time.Minute, registryCfg.PriorityBandGCTimeout,
+		"priorityBandGCTimeout should default to twice the flowGCTimeout")
+}
+
 // Verify the SaturationConfig builder specifically.
 func TestBuildSaturationConfig(t *testing.T) {
 	t.Parallel()
@@ -140,15 +140,25 @@ func ensureSchedulingLayer(
 }
 
 // ensureFlowControlLayer guarantees that the flow control subsystem is structurally complete.
+// It injects the policies used by the flow registry defaults unless they are already explicitly configured.
 func ensureFlowControlLayer(
 	cfg *configapi.EndpointPickerConfig,
 	handle fwkplugin.Handle,
-	_ map[string]fwkplugin.Plugin,
+	allPlugins map[string]fwkplugin.Plugin,
 ) error {
-	if err := registerDefaultPlugin(cfg, handle, intraflow.FCFSOrderingPolicyType); err != nil {
-		return err
+	for _, pluginType := range []string{
+		intraflow.FCFSOrderingPolicyType,
+		intraflow.WorkloadAwareOrderingPolicyType,
+		interflow.GlobalStrictFairnessPolicyType,
+	} {
+		if _, ok := allPlugins[pluginType]; ok {
+			continue
+		}
+		if err := registerDefaultPlugin(cfg, handle, pluginType); err != nil {
+			return err
+		}
 	}
-	return registerDefaultPlugin(cfg, handle, interflow.GlobalStrictFairnessPolicyType)
+	return nil
 }
 
 // registerDefaultPlugin instantiates a plugin with empty configuration (defaults) and adds it to both the handle and
@@ -142,6 +142,61 @@ schedulingProfiles:
   - pluginRef: test-scorer
 `
 
+// successFlowControlText tests that the flowControl section is mapped onto the flow control configuration.
+const successFlowControlText = `
+apiVersion: inference.networking.x-k8s.io/v1alpha1
+kind: EndpointPickerConfig
+plugins:
+- type: single-profile-handler
+- type: test-scorer
+- type: fcfs-ordering-policy
+- type: round-robin-fairness-policy
+schedulingProfiles:
+- name: default
+  plugins:
+  - pluginRef: test-scorer
+featureGates:
+- flowControl
+flowControl:
+  maxBytes: 4Gi
+  initialShardCount: 2
+  flowGCTimeout: 1m
+  priorityBandGCTimeout: 3m
+  defaultRequestTTL: 30s
+  minRequestTTL: 5s
+  maxRequestTTL: 10m
+  enqueueChannelBufferSize: 50
+  enablePreemption: true
+  priorityBands:
+  - priority: 100
+    name: Critical
+    orderingPolicyRef: fcfs-ordering-policy
+    fairnessPolicyRef: round-robin-fairness-policy
+    queue: ListQueue
+    maxBytes: 1Gi
+  - priority: -10
+  defaultPriorityBand:
+    fairnessPolicyRef: round-robin-fairness-policy
+    maxBytes: 100Mi
+`
+
+// successFlowControlFlowGCTimeoutOnlyText sets a flow GC timeout above the default band GC timeout
+const successFlowControlFlowGCTimeoutOnlyText = `
+apiVersion: inference.networking.x-k8s.io/v1alpha1
+kind: EndpointPickerConfig
+plugins:
+- type: single-profile-handler
+- type: test-scorer
+schedulingProfiles:
+- name: default
+  plugins:
+  - pluginRef: test-scorer
+featureGates:
+- flowControl
+flowControl:
+  flowGCTimeout: 15m
+`
+
 // --- Invalid Configurations (Syntax/Structure) ---
 
 // errorBadYamlText contains invalid YAML syntax.
@@ -422,3 +477,120 @@ featureGates:
 - dataLayer
 - flowControl
 `
+
+// errorFlowControlUndefinedPolicyText has a priority band that references an undefined policy plugin
+const errorFlowControlUndefinedPolicyText = `
+apiVersion: inference.networking.x-k8s.io/v1alpha1
+kind: EndpointPickerConfig
+plugins:
+- type: single-profile-handler
+- type: test-scorer
+schedulingProfiles:
+- name: default
+  plugins:
+  - pluginRef: test-scorer
+featureGates:
+- flowControl
+flowControl:
+  priorityBands:
+  - priority: 100
+    fairnessPolicyRef: round-robin-fairness-policy
+`
+
+// errorFlowControlWrongPolicyTypeText has a priority band whose ordering policy reference is not an OrderingPolicy
+const errorFlowControlWrongPolicyTypeText = `
+apiVersion: inference.networking.x-k8s.io/v1alpha1
+kind: EndpointPickerConfig
+plugins:
+- type: single-profile-handler
+- type: test-scorer
+schedulingProfiles:
+- name: default
+  plugins:
+  - pluginRef: test-scorer
+featureGates:
+- flowControl
+flowControl:
+  priorityBands:
+  - priority: 100
+    orderingPolicyRef: test-scorer
+`
+
+// errorFlowControlDuplicatePriorityText has two priority bands with the same priority level
+const errorFlowControlDuplicatePriorityText = `
+apiVersion: inference.networking.x-k8s.io/v1alpha1
+kind: EndpointPickerConfig
+plugins:
+- type: single-profile-handler
+- type: test-scorer
+schedulingProfiles:
+- name: default
+  plugins:
+  - pluginRef: test-scorer
+featureGates:
+- flowControl
+flowControl:
+  priorityBands:
+  - priority: 100
+    name: first
+  - priority: 100
+    name: second
+`
+
+// errorFlowControlIncompatibleQueueText has a priority band whose ordering policy is incompatible with its queue
+const errorFlowControlIncompatibleQueueText = `
+apiVersion: inference.networking.x-k8s.io/v1alpha1
+kind: EndpointPickerConfig
+plugins:
+- type: single-profile-handler
+- type: test-scorer
+- type: workload-aware-ordering-policy
+schedulingProfiles:
+- name: default
+  plugins:
+  - pluginRef: test-scorer
+featureGates:
+- flowControl
+flowControl:
+  priorityBands:
+  - priority: 100
+    orderingPolicyRef: workload-aware-ordering-policy
+    queue: ListQueue
+`
+
+// errorFlowControlBadGCTimeoutsText has a band GC timeout smaller than the flow GC timeout
+const errorFlowControlBadGCTimeoutsText = `
+apiVersion: inference.networking.x-k8s.io/v1alpha1
+kind: EndpointPickerConfig
+plugins:
+- type: single-profile-handler
+- type: test-scorer
+schedulingProfiles:
+- name: default
+  plugins:
+  - pluginRef: test-scorer
+featureGates:
+- flowControl
+flowControl:
+  flowGCTimeout: 10m
+  priorityBandGCTimeout: 1m
+`
+
+// errorFlowControlZeroBandMaxBytesText has a priority band with a zero capacity
+const errorFlowControlZeroBandMaxBytesText = `
+apiVersion: inference.networking.x-k8s.io/v1alpha1
+kind: EndpointPickerConfig
+plugins:
+- type: single-profile-handler
+- type: test-scorer
+schedulingProfiles:
+- name: default
+  plugins:
+  - pluginRef: test-scorer
+featureGates:
+- flowControl
+flowControl:
+  priorityBands:
+  - priority: 100
+    maxBytes: 0
+`
@@ -32,6 +32,9 @@ func validateConfig(cfg *configapi.EndpointPickerConfig) error {
 	if err := validateSchedulingProfiles(cfg); err != nil {
 		return fmt.Errorf("scheduling profile validation failed: %w", err)
 	}
+	if err := validateFlowControl(cfg); err != nil {
+		return fmt.Errorf("flow control validation failed: %w", err)
+	}
 	return nil
 }
 
@@ -65,6 +68,56 @@ func validateSchedulingProfiles(cfg *configapi.EndpointPickerConfig) error {
 	return nil
 }
 
+func validateFlowControl(cfg *configapi.EndpointPickerConfig) error {
+	if cfg.FlowControl == nil {
+		return nil
+	}
+
+	definedPlugins := sets.New[string]()
+	for _, p := range cfg.Plugins {
+		definedPlugins.Insert(p.Name)
+	}
+	validateBand := func(path string, band configapi.PriorityBand) error {
+		if band.OrderingPolicyRef != "" && !definedPlugins.Has(band.OrderingPolicyRef) {
+			return fmt.Errorf("%s references undefined ordering policy '%s'", path, band.OrderingPolicyRef)
+		}
+		if band.FairnessPolicyRef != "" && !definedPlugins.Has(band.FairnessPolicyRef) {
+			return fmt.Errorf("%s references undefined fairness policy '%s'", path, band.FairnessPolicyRef)
+		}
+		if band.MaxBytes != nil && band.MaxBytes.Sign() <= 0 {
+			return fmt.Errorf("%s maxBytes must be positive, got '%s'", path, band.MaxBytes.String())
+		}
+		return nil
+	}
+
+	seenPriorities := sets.New[int]()
+	seenNames := sets.New[string]()
+	for i, band := range cfg.FlowControl.PriorityBands {
+		if seenPriorities.Has(band.Priority) {
+			return fmt.Errorf("priorityBands[%d] has duplicate priority %d", i, band.Priority)
+		}
+		seenPriorities.Insert(band.Priority)
+		if band.Name != "" {
+			if seenNames.Has(band.Name) {
+				return fmt.Errorf("priorityBands[%d] has duplicate name '%s'", i, band.Name)
+			}
+			seenNames.Insert(band.Name)
+		}
+		if err := validateBand(fmt.Sprintf("priorityBands[%d]", i), band); err != nil {
+			return err
+		}
+	}
+	if cfg.FlowControl.DefaultPriorityBand != nil {
+		if err := validateBand("defaultPriorityBand", *cfg.FlowControl.DefaultPriorityBand); err != nil {
+			return err
+		}
+	}
+	if cfg.FlowControl.MaxBytes != nil && cfg.FlowControl.MaxBytes.Sign() < 0 {
+		return fmt.Errorf("negative maxBytes '%s'", cfg.FlowControl.MaxBytes.String())
+	}
+	return nil
+}
+
 func validateFeatureGates(gates configapi.FeatureGates) error {
 	if gates == nil {
 		return nil
@@ -0,0 +1,273 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kvevents
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/common/util/logging"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datalayer"
+	fwkplugin "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/plugin"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/multi/prefix"
+)
+
+const (
+	KVEventsDataSourceType = "kv-events-data-source"
+
+	// DefaultEventsPort is the default port of the model server KV events publisher, the vLLM default.
+	DefaultEventsPort = 5557
+
+	// maxEventBatchSize is the maximal size of a single event batch.
+	maxEventBatchSize = 16 * 1024 * 1024
+	// minReconnectBackoff and maxReconnectBackoff bound the delay between reconnection attempts.
+	minReconnectBackoff = time.Second
+	maxReconnectBackoff = 30 * time.Second
+)
+
+// compile-time type assertion
+var (
+	_ datalayer.DataSource   = &DataSource{}
+	_ prefix.IndexerProvider = &DataSource{}
+)
+
+// dataSourceParameters defines the parameters of the KV events data source.
+type dataSourceParameters struct {
+	// Port is the port of the ZMQ publisher of the KV events on each model server.
+	Port int `json:"port"`
+	// Topic is the topic prefix of the subscribed events, empty to subscribe to all the events.
+	Topic string `json:"topic"`
+	// ModelName is the name of the model served by the model servers, which the first block of every prompt
+	// is chained to. It must match the target model of the requests for the hashes to match.
+	ModelName string `json:"modelName"`
+}
+
+// DataSourceFactory defines the factory function for the KV events DataSource.
+func DataSourceFactory(name string, rawParameters json.RawMessage, handle fwkplugin.Handle) (fwkplugin.Plugin, error) {
+	parameters := dataSourceParameters{Port: DefaultEventsPort}
+	if rawParameters != nil {
+		if err := json.Unmarshal(rawParameters, &parameters); err != nil {
+			return nil, fmt.Errorf("failed to parse the parameters of the '%s' data source - %w", KVEventsDataSourceType, err)
+		}
+	}
+	if parameters.ModelName == "" {
+		return nil, fmt.Errorf("'%s' data source requires a modelName", KVEventsDataSourceType)
+	}
+	if parameters.Port <= 0 || parameters.Port > 65535 {
+		return nil, fmt.Errorf("invalid port %d of the '%s' data source", parameters.Port, KVEventsDataSourceType)
+	}
+
+	return NewDataSource(handle.Context(), parameters.Port, parameters.Topic, parameters.ModelName).WithName(name), nil
+}
+
+// NewDataSource initializes a new KV events DataSource and returns its pointer.
+// Subscriptions to the model servers' events publishers are bound to the given context.
+func NewDataSource(ctx context.Context, port int, topic string, modelName string) *DataSource {
+	ds := &DataSource{
+		typedName:     fwkplugin.TypedName{Type: KVEventsDataSourceType, Name: KVEventsDataSourceType},
+		ctx:           ctx,
+		port:          strconv.Itoa(port),
+		topic:         topic,
+		indexer:       NewIndexer(modelName),
+		subscriptions: make(map[prefix.ServerID]*subscription),
+		minBackoff:    minReconnectBackoff,
+	}
+	ds.indexer.onRemovePod = ds.unsubscribe
+	return ds
+}
+
+// DataSource subscribes to the KV cache block stored and removed events published by each model server, and
+// maintains an exact prefix.Indexer from them. Configured as the indexerSource of the prefix-cache-scorer, it
+// replaces the scorer's approximate LRU index with the real content of the model servers' caches.
+//
+// The events are received from the ZMQ PUB socket of each model server, as published by vLLM: multipart messages
+// of a topic, a sequence number and a msgpack encoded KVEventBatch. A subscription is started the first time an
+// endpoint is collected, and the endpoint's blocks are dropped whenever the subscription disconnects or a gap in
+// the sequence numbers shows that events were missed.
+type DataSource struct {
+	typedName  fwkplugin.TypedName
+	ctx        context.Context
+	port       string
+	topic      string
+	indexer    *Indexer
+	extractors sync.Map // key: name, value: extractor
+
+	mu            sync.Mutex
+	subscriptions map[prefix.ServerID]*subscription
+	minBackoff    time.Duration
+}
+
+// subscription is the events subscription of a single endpoint.
+type subscription struct {
+	address string
+	cancel  context.CancelFunc
+}
+
+// TypedName returns the data source type and name.
+func (ds *DataSource) TypedName() fwkplugin.TypedName {
+	return ds.typedName
+}
+
+// WithName sets the name of the data source.
+func (ds *DataSource) WithName(name string) *DataSource {
+	ds.typedName.Name = name
+	return ds
+}
+
+// Indexer returns the exact prefix indexer maintained by the data source.
+func (ds *DataSource) Indexer() prefix.Indexer {
+	return ds.indexer
+}
+
+// Extractors returns a list of registered Extractor names.
+func (ds *DataSource) Extractors() []string {
+	extractors := []string{}
+	ds.extractors.Range(func(_, val any) bool {
+		if ex, ok := val.(datalayer.Extractor); ok {
+			extractors = append(extractors, ex.TypedName().String())
+		}
+		return true // continue iteration
+	})
+	return extractors
+}
+
+// AddExtractor adds an extractor to the data source. Extractors are called with every *EventBatch received.
+func (ds *DataSource) AddExtractor(extractor datalayer.Extractor) error {
+	if err := datalayer.ValidateExtractorType(EventBatchType, extractor.ExpectedInputType()); err != nil {
+		return err
+	}
+	if _, loaded := ds.extractors.LoadOrStore(extractor.TypedName().Name, extractor); loaded {
+		return fmt.Errorf("attempt to add duplicate extractor %s to %s", extractor.TypedName(), ds.TypedName())
+	}
+	return nil
+}
+
+// Collect makes sure the endpoint's events are subscribed to. The events themselves are received
+// asynchronously.
+func (ds *DataSource) Collect(_ context.Context, ep datalayer.Endpoint) error {
+	pod := prefix.ServerID(ep.GetMetadata().NamespacedName)
+	address := net.JoinHostPort(ep.GetMetadata().GetIPAddress(), ds.port)
+
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	if sub, ok := ds.subscriptions[pod]; ok {
+		if sub.address == address {
+			return nil
+		}
+		sub.cancel() // the endpoint address changed, subscribe to the new address
+	}
+
+	ctx, cancel := context.WithCancel(ds.ctx)
+	ds.subscriptions[pod] = &subscription{address: address, cancel: cancel}
+	go ds.subscribe(ctx, ep, pod, address)
+	return nil
+}
+
+// unsubscribe stops the events stream subscription of the pod.
+func (ds *DataSource) unsubscribe(pod prefix.ServerID) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	if sub, ok := ds.subscriptions[pod]; ok {
+		sub.cancel()
+		delete(ds.subscriptions, pod)
+	}
+}
+
+// subscribe receives the events of the pod until the context is cancelled, reconnecting on failures.
+func (ds *DataSource) subscribe(ctx context.Context, ep datalayer.Endpoint, pod prefix.ServerID, address string) {
+	logger := log.FromContext(ctx).WithValues("pod", pod, "address", address)
+	backoff := ds.minBackoff
+	for {
+		err := ds.receive(ctx, ep, pod, address)
+		if ctx.Err() != nil {
+			return
+		}
+		// Events may be missed until the subscription is reconnected, don't keep blocks that may have been removed.
+		ds.indexer.clearPod(pod)
+		logger.V(logutil.DEFAULT).Info("KV events subscription disconnected, reconnecting", "error", err, "backoff", backoff)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, maxReconnectBackoff)
+	}
+}
+
+// receive receives the event batches of a single connection to the events publisher.
+func (ds *DataSource) receive(ctx context.Context, ep datalayer.Endpoint, pod prefix.ServerID, address string) error {
+	subscriber, err := dialZMQ(ctx, address, ds.topic, maxEventBatchSize)
+	if err != nil {
+		return err
+	}
+	defer subscriber.Close()
+
+	logger := log.FromContext(ctx).WithValues("pod", pod)
+	logger.V(logutil.VERBOSE).Info("Subscribed to KV events", "address", address)
+	ds.indexer.clearPod(pod) // start from a clean state, the subscription only carries new events
+	var lastSeq uint64
+	received := false
+	for {
+		frames, err := subscriber.receive()
+		if err != nil {
+			return err
+		}
+		// vLLM publishes [topic, sequence number, payload] messages.
+		if len(frames) != 3 || len(frames[1]) != 8 {
+			logger.V(logutil.DEFAULT).Info("Ignoring malformed KV events message", "frames", len(frames))
+			continue
+		}
+		seq := binary.BigEndian.Uint64(frames[1])
+		if received && seq != lastSeq+1 {
+			// Messages were dropped by the publisher, don't keep blocks that may have been removed.
+			logger.V(logutil.DEFAULT).Info("KV events were missed", "last", lastSeq, "received", seq)
+			ds.indexer.clearPod(pod)
+		}
+		lastSeq, received = seq, true
+
+		batch, err := decodeEventBatch(frames[2])
+		if err != nil {
+			logger.V(logutil.DEFAULT).Error(err, "Failed to decode KV event batch")
+			continue
+		}
+		if err := ds.indexer.Apply(pod, batch); err != nil {
+			logger.V(logutil.DEBUG).Info("KV event batch partially applied", "error", err)
+		}
+		ds.extract(ctx, batch, ep)
+	}
+}
+
+// extract calls the registered extractors with the event batch.
+func (ds *DataSource) extract(ctx context.Context, batch *EventBatch, ep datalayer.Endpoint) {
+	ds.extractors.Range(func(_, val any) bool {
+		if ex, ok := val.(datalayer.Extractor); ok {
+			if err := ex.Extract(ctx, batch, ep); err != nil {
+				log.FromContext(ctx).V(logutil.DEBUG).Info("Failed to extract KV event batch", "extractor", ex.TypedName(), "error", err)
+			}
+		}
+		return true // continue iteration
+	})
+}
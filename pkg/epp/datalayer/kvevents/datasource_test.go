@@ -0,0 +1,269 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kvevents
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"math"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datalayer"
+	fwkplugin "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/plugin"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/multi/prefix"
+)
+
+// encodeMsgpack encodes the values used by the vLLM KV events in msgpack.
+func encodeMsgpack(v any) []byte {
+	switch v := v.(type) {
+	case nil:
+		return []byte{0xc0}
+	case int:
+		if v < 0 {
+			return binary.BigEndian.AppendUint64([]byte{0xd3}, uint64(v))
+		}
+		return binary.BigEndian.AppendUint64([]byte{0xcf}, uint64(v))
+	case uint32:
+		return binary.BigEndian.AppendUint32([]byte{0xce}, v)
+	case float64:
+		return binary.BigEndian.AppendUint64([]byte{0xcb}, math.Float64bits(v))
+	case string:
+		return append(binary.BigEndian.AppendUint32([]byte{0xdb}, uint32(len(v))), v...)
+	case []byte:
+		return append(binary.BigEndian.AppendUint32([]byte{0xc6}, uint32(len(v))), v...)
+	case []uint32:
+		res := binary.BigEndian.AppendUint32([]byte{0xdd}, uint32(len(v)))
+		for _, item := range v {
+			res = append(res, encodeMsgpack(item)...)
+		}
+		return res
+	case []any:
+		res := binary.BigEndian.AppendUint32([]byte{0xdd}, uint32(len(v)))
+		for _, item := range v {
+			res = append(res, encodeMsgpack(item)...)
+		}
+		return res
+	}
+	panic("unsupported msgpack value")
+}
+
+// fakePublisher is a local model server publishing the KV event batches written to its channel on a ZMQ PUB socket.
+type fakePublisher struct {
+	listener net.Listener
+	// batches are msgpack encoded event batches, published with the next sequence number.
+	batches chan []byte
+	// skip skips sequence numbers when written to.
+	skip chan uint64
+	// disconnect closes the current connection when written to.
+	disconnect chan struct{}
+	// topics receives the topics subscribed to.
+	topics chan string
+}
+
+func newFakePublisher(t *testing.T) *fakePublisher {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+	p := &fakePublisher{listener: listener, batches: make(chan []byte), skip: make(chan uint64, 1),
+		disconnect: make(chan struct{}), topics: make(chan string, 10)}
+	go func() {
+		var seq uint64
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			seq = p.serve(conn, seq)
+		}
+	}()
+	return p
+}
+
+// serve publishes to a single subscriber until it disconnects or a disconnection is requested.
+func (p *fakePublisher) serve(conn net.Conn, seq uint64) uint64 {
+	defer conn.Close()
+	sub := &zmqSubscriber{conn: conn, reader: bufio.NewReader(conn), maxFrameSize: maxEventBatchSize}
+	greeting := make([]byte, 64)
+	greeting[0], greeting[9], greeting[10], greeting[11] = 0xff, 0x7f, 3, 1
+	copy(greeting[12:], "NULL")
+	if _, err := conn.Write(greeting); err != nil {
+		return seq
+	}
+	if _, err := io.ReadFull(sub.reader, make([]byte, 64)); err != nil {
+		return seq
+	}
+	ready := append([]byte{5}, "READY"...)
+	ready = append(ready, byte(len("Socket-Type")))
+	ready = append(ready, "Socket-Type"...)
+	ready = binary.BigEndian.AppendUint32(ready, 3)
+	ready = append(ready, "PUB"...)
+	if err := sub.writeFrame(zmtpFlagCommand, ready); err != nil {
+		return seq
+	}
+	frames, err := sub.receive() // skips the READY command of the subscriber
+	if err != nil || len(frames) != 1 || len(frames[0]) == 0 || frames[0][0] != 1 {
+		return seq
+	}
+	p.topics <- string(frames[0][1:])
+
+	for {
+		select {
+		case <-p.disconnect:
+			return seq
+		case n := <-p.skip:
+			seq += n
+		case batch := <-p.batches:
+			if sub.writeFrame(zmtpFlagMore, []byte("kv@pod")) != nil ||
+				sub.writeFrame(zmtpFlagMore, binary.BigEndian.AppendUint64(nil, seq)) != nil ||
+				sub.writeFrame(0, batch) != nil {
+				return seq
+			}
+			seq++
+		}
+	}
+}
+
+func (p *fakePublisher) port() int {
+	return p.listener.Addr().(*net.TCPAddr).Port
+}
+
+// storedEvent returns a msgpack encodable BlockStored event, with integer engine hashes.
+func storedEvent(parent any, tokens []uint32, blockSize int, engineHashes ...int) []any {
+	hashes := make([]any, len(engineHashes))
+	for i, hash := range engineHashes {
+		hashes[i] = hash
+	}
+	return []any{BlockStoredEventType, hashes, parent, tokens, blockSize, nil, "GPU"}
+}
+
+// eventBatch returns a msgpack encoded KVEventBatch.
+func eventBatch(events ...[]any) []byte {
+	list := make([]any, len(events))
+	for i, event := range events {
+		list[i] = event
+	}
+	return encodeMsgpack([]any{1.5, list, nil})
+}
+
+func TestDecodeEventBatch(t *testing.T) {
+	batch, err := decodeEventBatch(eventBatch(
+		storedEvent(nil, []uint32{1, 2, 3, 4}, 2, 11, -12),
+		[]any{BlockStoredEventType, []any{[]byte("b3")}, -12, []uint32{5, 6}, 2, nil, nil, "adapter"},
+		[]any{BlockRemovedEventType, []any{11}},
+		[]any{AllBlocksClearedEventType},
+	))
+	require.NoError(t, err)
+
+	lora := "adapter"
+	assert.Equal(t, &EventBatch{Timestamp: 1.5, Events: []Event{
+		{Type: BlockStoredEventType, BlockHashes: []EngineBlockHash{"11", "-12"}, TokenIDs: []uint32{1, 2, 3, 4}, BlockSize: 2},
+		{Type: BlockStoredEventType, BlockHashes: []EngineBlockHash{"b3"}, ParentBlockHash: "-12", TokenIDs: []uint32{5, 6},
+			BlockSize: 2, LoraName: &lora},
+		{Type: BlockRemovedEventType, BlockHashes: []EngineBlockHash{"11"}},
+		{Type: AllBlocksClearedEventType},
+	}}, batch)
+
+	// Compact encodings, as produced by msgspec, are decoded too: [1, [["AllBlocksCleared"]]].
+	compact := append([]byte{0x92, 0x01, 0x91, 0x91, 0xb0}, AllBlocksClearedEventType...)
+	batch, err = decodeEventBatch(compact)
+	require.NoError(t, err)
+	assert.Equal(t, &EventBatch{Timestamp: 1, Events: []Event{{Type: AllBlocksClearedEventType}}}, batch)
+
+	_, err = decodeEventBatch(encodeMsgpack([]any{1.5, []any{storedEvent(nil, []uint32{1}, 2, 11)[:3]}}))
+	assert.Error(t, err, "a stored event without token IDs is invalid")
+	_, err = decodeEventBatch([]byte{0x92, 0xcb})
+	assert.Error(t, err, "truncated data is invalid")
+	_, err = decodeEventBatch(encodeMsgpack("not a batch"))
+	assert.Error(t, err)
+}
+
+func TestDataSourceSubscription(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	publisher := newFakePublisher(t)
+	ds := NewDataSource(ctx, publisher.port(), "kv@", "model")
+	ds.minBackoff = 10 * time.Millisecond
+
+	pod := prefix.ServerID{Namespace: "default", Name: "pod1"}
+	endpoint := datalayer.NewEndpoint(&datalayer.EndpointMetadata{
+		NamespacedName: k8stypes.NamespacedName(pod),
+		Address:        "127.0.0.1",
+	}, nil)
+	require.NoError(t, ds.Collect(ctx, endpoint))
+	require.NoError(t, ds.Collect(ctx, endpoint), "collecting again should keep the existing subscription")
+	assert.Equal(t, "kv@", <-publisher.topics)
+
+	hashes := promptHashes("model", []uint32{1, 2, 3, 4}, 2)
+	publisher.batches <- eventBatch(storedEvent(nil, []uint32{1, 2, 3, 4}, 2, 1, 2))
+	require.Eventually(t, func() bool { return len(ds.Indexer().Get(hashes[1])) == 1 }, time.Second, 5*time.Millisecond)
+
+	publisher.batches <- eventBatch([]any{BlockRemovedEventType, []any{2}})
+	require.Eventually(t, func() bool { return len(ds.Indexer().Get(hashes[1])) == 0 }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, prefix.PodSet{pod: {}}, ds.Indexer().Get(hashes[0]))
+
+	// A gap in the sequence numbers shows that events were missed, so the blocks are dropped.
+	publisher.skip <- 2
+	publisher.batches <- eventBatch(storedEvent(nil, []uint32{7, 8}, 2, 3))
+	require.Eventually(t, func() bool { return len(ds.Indexer().Get(hashes[0])) == 0 }, time.Second, 5*time.Millisecond)
+
+	// Events may be missed while disconnected, so the blocks are dropped and the subscription is reconnected.
+	publisher.batches <- eventBatch(storedEvent(nil, []uint32{1, 2}, 2, 1))
+	require.Eventually(t, func() bool { return len(ds.Indexer().Get(hashes[0])) == 1 }, time.Second, 5*time.Millisecond)
+	publisher.disconnect <- struct{}{}
+	require.Eventually(t, func() bool { return len(ds.Indexer().Get(hashes[0])) == 0 }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, "kv@", <-publisher.topics)
+	publisher.batches <- eventBatch(storedEvent(nil, []uint32{1, 2}, 2, 1))
+	require.Eventually(t, func() bool { return len(ds.Indexer().Get(hashes[0])) == 1 }, time.Second, 5*time.Millisecond)
+
+	// Removing the pod from the indexer stops the subscription.
+	ds.Indexer().RemovePod(pod)
+	ds.mu.Lock()
+	assert.Empty(t, ds.subscriptions)
+	ds.mu.Unlock()
+	assert.Empty(t, ds.Indexer().Get(hashes[0]))
+}
+
+func TestDataSourceFactory(t *testing.T) {
+	handle := fwkplugin.NewEppHandle(context.Background(), func() []k8stypes.NamespacedName { return nil })
+
+	plugin, err := DataSourceFactory("kv-events", json.RawMessage(`{"modelName": "model"}`), handle)
+	require.NoError(t, err)
+	assert.Equal(t, strconv.Itoa(DefaultEventsPort), plugin.(*DataSource).port)
+
+	_, err = DataSourceFactory("kv-events", json.RawMessage(`{"modelName": "model", "port": 70000}`), handle)
+	assert.Error(t, err)
+	_, err = DataSourceFactory("kv-events", json.RawMessage(`{}`), handle)
+	assert.Error(t, err)
+}
+
+func TestDataSourceExtractors(t *testing.T) {
+	ds := NewDataSource(context.Background(), DefaultEventsPort, "", "model")
+	assert.Empty(t, ds.Extractors())
+	assert.Equal(t, KVEventsDataSourceType, ds.WithName("kv-events").TypedName().Type)
+	assert.Equal(t, "kv-events", ds.TypedName().Name)
+}
@@ -0,0 +1,209 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kvevents
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// Event types, as published by vLLM.
+const (
+	BlockStoredEventType      = "BlockStored"
+	BlockRemovedEventType     = "BlockRemoved"
+	AllBlocksClearedEventType = "AllBlocksCleared"
+)
+
+// EventBatchType is the output type of the KV events data source, passed to its extractors.
+var EventBatchType = reflect.TypeOf(&EventBatch{})
+
+// EventBatch is a batch of KV cache events published by a model server. It follows the vLLM KVEventBatch schema.
+type EventBatch struct {
+	// Timestamp is the time the batch was published, in seconds since the epoch.
+	Timestamp float64
+	Events    []Event
+}
+
+// Event is a single KV cache event. The fields in use depend on the event type.
+type Event struct {
+	Type string
+	// BlockHashes are the engine hashes of the stored or removed blocks. The engine hashes are opaque: they are
+	// only used to relate events with each other, since the prefix-cache-scorer computes its own block hashes.
+	BlockHashes []EngineBlockHash
+	// ParentBlockHash is the engine hash of the block preceding the first stored block, empty for the first block
+	// of a prompt.
+	ParentBlockHash EngineBlockHash
+	// TokenIDs are the tokens of all the stored blocks, BlockSize tokens per block.
+	TokenIDs  []uint32
+	BlockSize int
+	// LoraName is the name of the LoRA adapter the blocks were computed with, if any.
+	LoraName *string
+}
+
+// EngineBlockHash is an opaque block hash of the model server engine. vLLM publishes either integers or bytes,
+// both are kept in their string form.
+type EngineBlockHash string
+
+// decodeEventBatch decodes a vLLM KVEventBatch, encoded in msgpack as an array of its fields:
+// [ts, [event, ...], data_parallel_rank]. Every event is an array starting with its type, followed by its fields:
+// [BlockStored, block_hashes, parent_block_hash, token_ids, block_size, lora_id, medium, lora_name],
+// [BlockRemoved, block_hashes, medium] and [AllBlocksCleared]. Trailing fields may be omitted.
+func decodeEventBatch(data []byte) (*EventBatch, error) {
+	value, err := decodeMsgpack(data)
+	if err != nil {
+		return nil, err
+	}
+	fields, ok := value.([]any)
+	if !ok || len(fields) < 2 {
+		return nil, errors.New("event batch is not an array of at least 2 fields")
+	}
+	batch := &EventBatch{}
+	if batch.Timestamp, ok = toFloat(fields[0]); !ok {
+		return nil, fmt.Errorf("invalid event batch timestamp %v", fields[0])
+	}
+	events, ok := fields[1].([]any)
+	if !ok {
+		return nil, fmt.Errorf("invalid event batch events %v", fields[1])
+	}
+	for n, raw := range events {
+		event, err := decodeEvent(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid event %d - %w", n, err)
+		}
+		batch.Events = append(batch.Events, event)
+	}
+	return batch, nil
+}
+
+// decodeEvent decodes a single event of a KVEventBatch.
+func decodeEvent(raw any) (Event, error) {
+	fields, ok := raw.([]any)
+	if !ok || len(fields) == 0 {
+		return Event{}, errors.New("event is not an array")
+	}
+	event := Event{}
+	if event.Type, ok = fields[0].(string); !ok {
+		return Event{}, fmt.Errorf("invalid event type %v", fields[0])
+	}
+	field := func(n int) any {
+		if n < len(fields) {
+			return fields[n]
+		}
+		return nil
+	}
+
+	var err error
+	switch event.Type {
+	case BlockStoredEventType:
+		if event.BlockHashes, err = toEngineHashes(field(1)); err != nil {
+			return Event{}, err
+		}
+		if parent := field(2); parent != nil {
+			if event.ParentBlockHash, err = toEngineHash(parent); err != nil {
+				return Event{}, err
+			}
+		}
+		if event.TokenIDs, err = toTokenIDs(field(3)); err != nil {
+			return Event{}, err
+		}
+		blockSize, ok := toInt(field(4))
+		if !ok {
+			return Event{}, fmt.Errorf("invalid block size %v", field(4))
+		}
+		event.BlockSize = int(blockSize)
+		if loraName, ok := field(7).(string); ok {
+			event.LoraName = &loraName
+		}
+	case BlockRemovedEventType:
+		if event.BlockHashes, err = toEngineHashes(field(1)); err != nil {
+			return Event{}, err
+		}
+	}
+	return event, nil
+}
+
+func toEngineHashes(raw any) ([]EngineBlockHash, error) {
+	list, ok := raw.([]any)
+	if !ok {
+		return nil, fmt.Errorf("invalid block hashes %v", raw)
+	}
+	hashes := make([]EngineBlockHash, len(list))
+	for n, hash := range list {
+		var err error
+		if hashes[n], err = toEngineHash(hash); err != nil {
+			return nil, err
+		}
+	}
+	return hashes, nil
+}
+
+func toEngineHash(raw any) (EngineBlockHash, error) {
+	switch hash := raw.(type) {
+	case int64:
+		return EngineBlockHash(strconv.FormatInt(hash, 10)), nil
+	case uint64:
+		return EngineBlockHash(strconv.FormatUint(hash, 10)), nil
+	case []byte:
+		return EngineBlockHash(hash), nil
+	case string:
+		return EngineBlockHash(hash), nil
+	}
+	return "", fmt.Errorf("invalid block hash %v", raw)
+}
+
+func toTokenIDs(raw any) ([]uint32, error) {
+	list, ok := raw.([]any)
+	if !ok {
+		return nil, fmt.Errorf("invalid token IDs %v", raw)
+	}
+	tokens := make([]uint32, len(list))
+	for n, token := range list {
+		id, ok := toInt(token)
+		if !ok || id < 0 || id > int64(^uint32(0)) {
+			return nil, fmt.Errorf("invalid token ID %v", token)
+		}
+		tokens[n] = uint32(id)
+	}
+	return tokens, nil
+}
+
+func toInt(raw any) (int64, bool) {
+	switch v := raw.(type) {
+	case int64:
+		return v, true
+	case uint64:
+		if v > uint64(1<<63-1) {
+			return 0, false
+		}
+		return int64(v), true
+	}
+	return 0, false
+}
+
+func toFloat(raw any) (float64, bool) {
+	switch v := raw.(type) {
+	case float64:
+		return v, true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	}
+	return 0, false
+}
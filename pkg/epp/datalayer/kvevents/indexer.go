@@ -0,0 +1,216 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kvevents
+
+import (
+	"fmt"
+	"sync"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/multi/prefix"
+)
+
+// compile-time type assertion
+var _ prefix.Indexer = &Indexer{}
+
+// Indexer is an exact prefix.Indexer, built from the KV cache events of the model servers.
+// Blocks are hashed with the prefix-cache-scorer token block hashing, so that the prompts tokenized by the scorer
+// match the blocks stored by the model servers.
+type Indexer struct {
+	mu         sync.RWMutex
+	modelName  string // the model name the first block of a prompt is chained to
+	hashToPods map[prefix.BlockHash]prefix.PodSet
+	pods       map[prefix.ServerID]*podBlocks
+	// onRemovePod is called when a pod is removed from the indexer. It is set once, before the indexer is used.
+	onRemovePod func(pod prefix.ServerID)
+}
+
+// podBlocks holds the blocks cached by a single pod.
+type podBlocks struct {
+	// engineToHash maps the engine hash of a block to its prefix.BlockHash.
+	engineToHash map[EngineBlockHash]prefix.BlockHash
+	// refs counts the engine blocks of each prefix.BlockHash, since the engine may store the same content more than once.
+	refs map[prefix.BlockHash]int
+}
+
+// NewIndexer returns a new, empty, Indexer. The first block of each prompt is chained to the given model name,
+// unless the event carries a LoRA adapter name.
+func NewIndexer(modelName string) *Indexer {
+	return &Indexer{
+		modelName:  modelName,
+		hashToPods: make(map[prefix.BlockHash]prefix.PodSet),
+		pods:       make(map[prefix.ServerID]*podBlocks),
+	}
+}
+
+// Get returns the set of pods that have the given block cached.
+func (i *Indexer) Get(hash prefix.BlockHash) prefix.PodSet {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	pods := i.hashToPods[hash]
+	res := make(prefix.PodSet, len(pods))
+	for pod := range pods {
+		res[pod] = struct{}{}
+	}
+	return res
+}
+
+// Add is a no-op: the index reflects only the blocks the model servers report as stored.
+func (i *Indexer) Add(_ []prefix.BlockHash, _ prefix.Server) {}
+
+// RemovePod removes a pod and all of its blocks from the indexer.
+func (i *Indexer) RemovePod(pod prefix.ServerID) {
+	i.clearPod(pod)
+	if i.onRemovePod != nil {
+		i.onRemovePod(pod)
+	}
+}
+
+// Pods returns the list of all pods currently tracked in the indexer.
+func (i *Indexer) Pods() []prefix.ServerID {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	pods := make([]prefix.ServerID, 0, len(i.pods))
+	for pod := range i.pods {
+		pods = append(pods, pod)
+	}
+	return pods
+}
+
+// Apply updates the index with a batch of events published by the given pod.
+// Events that cannot be applied are skipped, and reported in the returned error.
+func (i *Indexer) Apply(pod prefix.ServerID, batch *EventBatch) error {
+	var errs []error
+	for _, event := range batch.Events {
+		var err error
+		switch event.Type {
+		case BlockStoredEventType:
+			err = i.storeBlocks(pod, event)
+		case BlockRemovedEventType:
+			i.removeBlocks(pod, event)
+		case AllBlocksClearedEventType:
+			i.clearPod(pod)
+		default:
+			err = fmt.Errorf("unknown event type '%s'", event.Type)
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to apply %d of %d events from pod %s: %v", len(errs), len(batch.Events), pod, errs)
+	}
+	return nil
+}
+
+func (i *Indexer) storeBlocks(pod prefix.ServerID, event Event) error {
+	if event.BlockSize <= 0 || len(event.TokenIDs) != event.BlockSize*len(event.BlockHashes) {
+		return fmt.Errorf("stored event has %d tokens for %d blocks of size %d", len(event.TokenIDs),
+			len(event.BlockHashes), event.BlockSize)
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	blocks := i.pods[pod]
+	if blocks == nil {
+		blocks = &podBlocks{engineToHash: make(map[EngineBlockHash]prefix.BlockHash), refs: make(map[prefix.BlockHash]int)}
+		i.pods[pod] = blocks
+	}
+
+	var prevHash prefix.BlockHash
+	if event.ParentBlockHash == "" {
+		model := i.modelName
+		if event.LoraName != nil && *event.LoraName != "" {
+			model = *event.LoraName
+		}
+		prevHash = prefix.InitialBlockHash(model, "")
+	} else {
+		parentHash, ok := blocks.engineToHash[event.ParentBlockHash]
+		if !ok { // the parent was removed or stored before the subscription started, the chain can't be computed
+			return fmt.Errorf("stored event has an unknown parent block %q", event.ParentBlockHash)
+		}
+		prevHash = parentHash
+	}
+
+	for n, engineHash := range event.BlockHashes {
+		hash := prefix.HashTokenBlock(prevHash, event.TokenIDs[n*event.BlockSize:(n+1)*event.BlockSize])
+		if _, exists := blocks.engineToHash[engineHash]; !exists {
+			blocks.engineToHash[engineHash] = hash
+			blocks.refs[hash]++
+			podIDs := i.hashToPods[hash]
+			if podIDs == nil {
+				podIDs = make(prefix.PodSet)
+				i.hashToPods[hash] = podIDs
+			}
+			podIDs[pod] = struct{}{}
+		}
+		prevHash = hash
+	}
+	metrics.RecordPrefixCacheSize(int64(len(i.hashToPods)))
+	return nil
+}
+
+func (i *Indexer) removeBlocks(pod prefix.ServerID, event Event) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	blocks := i.pods[pod]
+	if blocks == nil {
+		return
+	}
+	for _, engineHash := range event.BlockHashes {
+		hash, ok := blocks.engineToHash[engineHash]
+		if !ok {
+			continue
+		}
+		delete(blocks.engineToHash, engineHash)
+		if blocks.refs[hash]--; blocks.refs[hash] <= 0 {
+			delete(blocks.refs, hash)
+			i.removeFromHash(hash, pod)
+		}
+	}
+	metrics.RecordPrefixCacheSize(int64(len(i.hashToPods)))
+}
+
+// clearPod removes all the blocks of a pod.
+func (i *Indexer) clearPod(pod prefix.ServerID) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	blocks := i.pods[pod]
+	if blocks == nil {
+		return
+	}
+	for hash := range blocks.refs {
+		i.removeFromHash(hash, pod)
+	}
+	delete(i.pods, pod)
+	metrics.RecordPrefixCacheSize(int64(len(i.hashToPods)))
+}
+
+// removeFromHash removes the pod from the set of pods caching the hash. Must be called with the lock held.
+func (i *Indexer) removeFromHash(hash prefix.BlockHash, pod prefix.ServerID) {
+	if podIDs, ok := i.hashToPods[hash]; ok {
+		delete(podIDs, pod)
+		if len(podIDs) == 0 {
+			delete(i.hashToPods, hash)
+		}
+	}
+}
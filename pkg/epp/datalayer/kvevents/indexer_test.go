@@ -0,0 +1,152 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kvevents
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datalayer"
+	types "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/scheduling"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/multi/prefix"
+)
+
+func engineHashes(hashes ...EngineBlockHash) []EngineBlockHash {
+	return hashes
+}
+
+// promptHashes returns the hashes the prefix-cache-scorer computes for a tokenized prompt.
+func promptHashes(model string, tokens []uint32, blockSize int) []prefix.BlockHash {
+	var res []prefix.BlockHash
+	prev := prefix.InitialBlockHash(model, "")
+	for i := 0; i+blockSize <= len(tokens); i += blockSize {
+		prev = prefix.HashTokenBlock(prev, tokens[i:i+blockSize])
+		res = append(res, prev)
+	}
+	return res
+}
+
+func TestIndexerApply(t *testing.T) {
+	pod1 := prefix.ServerID{Namespace: "default", Name: "pod1"}
+	pod2 := prefix.ServerID{Namespace: "default", Name: "pod2"}
+	indexer := NewIndexer("model")
+	hashes := promptHashes("model", []uint32{1, 2, 3, 4, 5, 6}, 2)
+
+	// pod1 stores the first two blocks, then the third block chained to the second one.
+	require.NoError(t, indexer.Apply(pod1, &EventBatch{Events: []Event{
+		{Type: BlockStoredEventType, BlockHashes: engineHashes("11", "12"), TokenIDs: []uint32{1, 2, 3, 4}, BlockSize: 2},
+		{Type: BlockStoredEventType, BlockHashes: engineHashes("13"), ParentBlockHash: "12", TokenIDs: []uint32{5, 6}, BlockSize: 2},
+	}}))
+	// pod2 stores the first block only.
+	require.NoError(t, indexer.Apply(pod2, &EventBatch{Events: []Event{
+		{Type: BlockStoredEventType, BlockHashes: engineHashes("a1"), TokenIDs: []uint32{1, 2}, BlockSize: 2},
+	}}))
+
+	assert.Equal(t, prefix.PodSet{pod1: {}, pod2: {}}, indexer.Get(hashes[0]))
+	assert.Equal(t, prefix.PodSet{pod1: {}}, indexer.Get(hashes[1]))
+	assert.Equal(t, prefix.PodSet{pod1: {}}, indexer.Get(hashes[2]))
+	assert.ElementsMatch(t, []prefix.ServerID{pod1, pod2}, indexer.Pods())
+
+	// Approximations of the scheduled requests are ignored.
+	indexer.Add(promptHashes("model", []uint32{7, 8}, 2), prefix.Server{ServerID: pod2})
+	assert.Empty(t, indexer.Get(promptHashes("model", []uint32{7, 8}, 2)[0]))
+
+	// Evicted blocks are removed.
+	require.NoError(t, indexer.Apply(pod1, &EventBatch{Events: []Event{
+		{Type: BlockRemovedEventType, BlockHashes: engineHashes("13", "99")},
+	}}))
+	assert.Empty(t, indexer.Get(hashes[2]))
+	assert.Equal(t, prefix.PodSet{pod1: {}}, indexer.Get(hashes[1]))
+
+	// A block whose parent is unknown can't be chained.
+	assert.Error(t, indexer.Apply(pod1, &EventBatch{Events: []Event{
+		{Type: BlockStoredEventType, BlockHashes: engineHashes("14"), ParentBlockHash: "13", TokenIDs: []uint32{5, 6}, BlockSize: 2},
+	}}))
+	// Malformed events are reported.
+	assert.Error(t, indexer.Apply(pod1, &EventBatch{Events: []Event{
+		{Type: BlockStoredEventType, BlockHashes: engineHashes("14"), TokenIDs: []uint32{5}, BlockSize: 2},
+		{Type: "Unknown"},
+	}}))
+
+	// LoRA blocks are chained to the adapter name.
+	lora := "adapter"
+	require.NoError(t, indexer.Apply(pod2, &EventBatch{Events: []Event{
+		{Type: BlockStoredEventType, BlockHashes: engineHashes("21"), TokenIDs: []uint32{1, 2}, BlockSize: 2, LoraName: &lora},
+	}}))
+	assert.Equal(t, prefix.PodSet{pod2: {}}, indexer.Get(promptHashes(lora, []uint32{1, 2}, 2)[0]))
+
+	// Clearing all blocks of pod1 leaves pod2 untouched.
+	require.NoError(t, indexer.Apply(pod1, &EventBatch{Events: []Event{{Type: AllBlocksClearedEventType}}}))
+	assert.Equal(t, prefix.PodSet{pod2: {}}, indexer.Get(hashes[0]))
+	assert.Empty(t, indexer.Get(hashes[1]))
+
+	indexer.RemovePod(pod2)
+	assert.Empty(t, indexer.Get(hashes[0]))
+	assert.Empty(t, indexer.Pods())
+}
+
+func TestIndexerSharedBlockRefs(t *testing.T) {
+	pod := prefix.ServerID{Namespace: "default", Name: "pod1"}
+	indexer := NewIndexer("model")
+	hash := promptHashes("model", []uint32{1, 2}, 2)[0]
+
+	// The engine stored the same content twice, under different engine hashes.
+	require.NoError(t, indexer.Apply(pod, &EventBatch{Events: []Event{
+		{Type: BlockStoredEventType, BlockHashes: engineHashes("1"), TokenIDs: []uint32{1, 2}, BlockSize: 2},
+		{Type: BlockStoredEventType, BlockHashes: engineHashes("2"), TokenIDs: []uint32{1, 2}, BlockSize: 2},
+		{Type: BlockRemovedEventType, BlockHashes: engineHashes("1")},
+	}}))
+	assert.Equal(t, prefix.PodSet{pod: {}}, indexer.Get(hash), "block is still cached under the second engine hash")
+
+	require.NoError(t, indexer.Apply(pod, &EventBatch{Events: []Event{
+		{Type: BlockRemovedEventType, BlockHashes: engineHashes("2")},
+	}}))
+	assert.Empty(t, indexer.Get(hash))
+}
+
+func TestIndexerSaltedLookup(t *testing.T) {
+	pod := prefix.ServerID{Namespace: "default", Name: "pod1"}
+	indexer := NewIndexer("model")
+	require.NoError(t, indexer.Apply(pod, &EventBatch{Events: []Event{
+		{Type: BlockStoredEventType, BlockHashes: engineHashes("1", "2"), TokenIDs: []uint32{1, 2, 3, 4}, BlockSize: 2},
+	}}))
+
+	config := prefix.DefaultConfig
+	config.IndexerSource = "kv-events"
+	config.TokenBlockSize = 2
+	scorer := prefix.New(context.Background(), config).WithIndexer(indexer)
+	endpoint := &types.PodMetrics{
+		EndpointMetadata: &datalayer.EndpointMetadata{NamespacedName: k8stypes.NamespacedName(pod)},
+		Metrics:          datalayer.NewMetrics(),
+	}
+
+	// The events carry no cache_salt, so a salted request still matches the blocks stored by the pod.
+	request := &types.LLMRequest{
+		RequestId:   "salted",
+		TargetModel: "model",
+		Body: &types.LLMRequestBody{Completions: &types.CompletionsRequest{
+			Prompt:    types.Prompt{Tokens: [][]uint32{{1, 2, 3, 4}}},
+			CacheSalt: "tenant-a",
+		}},
+	}
+	scores := scorer.Score(context.Background(), types.NewCycleState(), request, []types.Endpoint{endpoint})
+	assert.Equal(t, 1.0, scores[endpoint])
+}
@@ -0,0 +1,224 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kvevents
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// maxMsgpackDepth bounds the nesting of decoded msgpack containers.
+const maxMsgpackDepth = 32
+
+var errMsgpackTruncated = errors.New("truncated msgpack data")
+
+// decodeMsgpack decodes a msgpack value into nil, bool, int64, uint64, float64, string, []byte, []any or
+// map[any]any values. Extension values are decoded as their raw data. Only what the vLLM KV events use is
+// needed, but the whole format is supported so that unknown fields can be skipped.
+func decodeMsgpack(data []byte) (any, error) {
+	d := &msgpackDecoder{data: data}
+	value, err := d.decode(0)
+	if err != nil {
+		return nil, err
+	}
+	if d.pos != len(d.data) {
+		return nil, fmt.Errorf("%d trailing bytes after msgpack value", len(d.data)-d.pos)
+	}
+	return value, nil
+}
+
+type msgpackDecoder struct {
+	data []byte
+	pos  int
+}
+
+func (d *msgpackDecoder) read(n int) ([]byte, error) {
+	if n < 0 || n > len(d.data)-d.pos {
+		return nil, errMsgpackTruncated
+	}
+	b := d.data[d.pos : d.pos+n]
+	d.pos += n
+	return b, nil
+}
+
+// readUint reads a big-endian unsigned integer of the given size in bytes.
+func (d *msgpackDecoder) readUint(size int) (uint64, error) {
+	b, err := d.read(size)
+	if err != nil {
+		return 0, err
+	}
+	switch size {
+	case 1:
+		return uint64(b[0]), nil
+	case 2:
+		return uint64(binary.BigEndian.Uint16(b)), nil
+	case 4:
+		return uint64(binary.BigEndian.Uint32(b)), nil
+	default:
+		return binary.BigEndian.Uint64(b), nil
+	}
+}
+
+// readLength reads a length of the given size in bytes.
+func (d *msgpackDecoder) readLength(size int) (int, error) {
+	n, err := d.readUint(size)
+	if err != nil {
+		return 0, err
+	}
+	if n > uint64(len(d.data)-d.pos) { // every element takes at least a byte
+		return 0, errMsgpackTruncated
+	}
+	return int(n), nil
+}
+
+func (d *msgpackDecoder) decode(depth int) (any, error) {
+	if depth > maxMsgpackDepth {
+		return nil, errors.New("msgpack data is nested too deeply")
+	}
+	b, err := d.read(1)
+	if err != nil {
+		return nil, err
+	}
+	switch c := b[0]; {
+	case c <= 0x7f: // positive fixint
+		return int64(c), nil
+	case c >= 0xe0: // negative fixint
+		return int64(int8(c)), nil
+	case c >= 0x80 && c <= 0x8f:
+		return d.decodeMap(int(c&0x0f), depth)
+	case c >= 0x90 && c <= 0x9f:
+		return d.decodeArray(int(c&0x0f), depth)
+	case c >= 0xa0 && c <= 0xbf:
+		return d.decodeString(int(c & 0x1f))
+	}
+
+	switch c := b[0]; c {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xc4, 0xc5, 0xc6: // bin 8, 16, 32
+		n, err := d.readLength(1 << (c - 0xc4))
+		if err != nil {
+			return nil, err
+		}
+		return d.read(n)
+	case 0xc7, 0xc8, 0xc9: // ext 8, 16, 32
+		n, err := d.readLength(1 << (c - 0xc7))
+		if err != nil {
+			return nil, err
+		}
+		return d.read(n + 1) // type and data
+	case 0xca:
+		v, err := d.readUint(4)
+		return float64(math.Float32frombits(uint32(v))), err
+	case 0xcb:
+		v, err := d.readUint(8)
+		return math.Float64frombits(v), err
+	case 0xcc, 0xcd, 0xce, 0xcf: // uint 8, 16, 32, 64
+		v, err := d.readUint(1 << (c - 0xcc))
+		if err != nil {
+			return nil, err
+		}
+		if v <= math.MaxInt64 {
+			return int64(v), nil
+		}
+		return v, nil
+	case 0xd0:
+		v, err := d.readUint(1)
+		return int64(int8(v)), err
+	case 0xd1:
+		v, err := d.readUint(2)
+		return int64(int16(v)), err
+	case 0xd2:
+		v, err := d.readUint(4)
+		return int64(int32(v)), err
+	case 0xd3:
+		v, err := d.readUint(8)
+		return int64(v), err
+	case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8: // fixext 1, 2, 4, 8, 16
+		return d.read(1 + 1<<(c-0xd4))
+	case 0xd9, 0xda, 0xdb: // str 8, 16, 32
+		n, err := d.readLength(1 << (c - 0xd9))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeString(n)
+	case 0xdc, 0xdd: // array 16, 32
+		n, err := d.readLength(2 << (c - 0xdc))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeArray(n, depth)
+	case 0xde, 0xdf: // map 16, 32
+		n, err := d.readLength(2 << (c - 0xde))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeMap(n, depth)
+	}
+	return nil, fmt.Errorf("invalid msgpack type 0x%02x", b[0])
+}
+
+func (d *msgpackDecoder) decodeString(n int) (any, error) {
+	b, err := d.read(n)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (d *msgpackDecoder) decodeArray(n int, depth int) (any, error) {
+	res := make([]any, n)
+	for i := range res {
+		var err error
+		if res[i], err = d.decode(depth + 1); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func (d *msgpackDecoder) decodeMap(n int, depth int) (any, error) {
+	res := make(map[any]any, n)
+	for range n {
+		key, err := d.decode(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		value, err := d.decode(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if key, ok := key.([]byte); ok { // slices are not comparable
+			res[string(key)] = value
+			continue
+		}
+		if _, ok := key.([]any); ok {
+			return nil, errors.New("unsupported msgpack map key")
+		}
+		if _, ok := key.(map[any]any); ok {
+			return nil, errors.New("unsupported msgpack map key")
+		}
+		res[key] = value
+	}
+	return res, nil
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kvevents
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+)
+
+// ZMTP frame flags.
+const (
+	zmtpFlagMore    = 0x01
+	zmtpFlagLong    = 0x02
+	zmtpFlagCommand = 0x04
+)
+
+// zmqSubscriber is a minimal ZeroMQ SUB socket connected to a single PUB socket, such as the KV events publisher
+// of vLLM. It speaks ZMTP 3.0 over TCP with the NULL security mechanism, which is what the model servers use.
+// See https://rfc.zeromq.org/spec/23/.
+type zmqSubscriber struct {
+	conn         net.Conn
+	reader       *bufio.Reader
+	maxFrameSize int
+	stop         func() bool // stops closing the connection on context cancellation
+}
+
+// dialZMQ connects to the PUB socket at the given TCP address and subscribes to the messages with the given topic
+// prefix. An empty topic subscribes to all messages.
+func dialZMQ(ctx context.Context, address string, topic string, maxFrameSize int) (*zmqSubscriber, error) {
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	// Unblock the handshake and the reads when the context is cancelled.
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	s := &zmqSubscriber{conn: conn, reader: bufio.NewReader(conn), maxFrameSize: maxFrameSize, stop: stop}
+	if err := s.handshake(topic); err != nil {
+		_ = s.Close()
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, err
+	}
+	return s, nil
+}
+
+// handshake exchanges the greetings and the READY commands, and sends the subscription.
+func (s *zmqSubscriber) handshake(topic string) error {
+	greeting := make([]byte, 64)
+	greeting[0], greeting[9] = 0xff, 0x7f // signature
+	greeting[10], greeting[11] = 3, 0     // version 3.0
+	copy(greeting[12:32], "NULL")         // security mechanism, as-server and filler are zero
+	if _, err := s.conn.Write(greeting); err != nil {
+		return err
+	}
+	peer := make([]byte, 64)
+	if _, err := io.ReadFull(s.reader, peer); err != nil {
+		return fmt.Errorf("failed to read the ZMTP greeting - %w", err)
+	}
+	if peer[0] != 0xff || peer[9] != 0x7f || peer[10] < 3 {
+		return errors.New("peer is not a ZMTP 3 socket")
+	}
+	if mechanism := string(bytes.TrimRight(peer[12:32], "\x00")); mechanism != "NULL" {
+		return fmt.Errorf("unsupported ZMTP security mechanism '%s'", mechanism)
+	}
+
+	// READY command with the Socket-Type metadata property.
+	ready := []byte{5}
+	ready = append(ready, "READY"...)
+	ready = append(ready, byte(len("Socket-Type")))
+	ready = append(ready, "Socket-Type"...)
+	ready = binary.BigEndian.AppendUint32(ready, uint32(len("SUB")))
+	ready = append(ready, "SUB"...)
+	if err := s.writeFrame(zmtpFlagCommand, ready); err != nil {
+		return err
+	}
+	flags, body, err := s.readFrame()
+	if err != nil {
+		return err
+	}
+	if flags&zmtpFlagCommand == 0 || len(body) < 6 || string(body[1:6]) != "READY" {
+		return errors.New("peer did not send a ZMTP READY command")
+	}
+
+	// In ZMTP 3.0, subscriptions are messages starting with 1.
+	return s.writeFrame(0, append([]byte{1}, topic...))
+}
+
+func (s *zmqSubscriber) writeFrame(flags byte, body []byte) error {
+	header := []byte{flags}
+	if len(body) > 255 {
+		header[0] |= zmtpFlagLong
+		header = binary.BigEndian.AppendUint64(header, uint64(len(body)))
+	} else {
+		header = append(header, byte(len(body)))
+	}
+	_, err := s.conn.Write(append(header, body...))
+	return err
+}
+
+func (s *zmqSubscriber) readFrame() (byte, []byte, error) {
+	flags, err := s.reader.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	var size uint64
+	if flags&zmtpFlagLong != 0 {
+		var long [8]byte
+		if _, err := io.ReadFull(s.reader, long[:]); err != nil {
+			return 0, nil, err
+		}
+		size = binary.BigEndian.Uint64(long[:])
+	} else {
+		short, err := s.reader.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		size = uint64(short)
+	}
+	if size > uint64(s.maxFrameSize) {
+		return 0, nil, fmt.Errorf("ZMTP frame of %d bytes exceeds the maximal size of %d bytes", size, s.maxFrameSize)
+	}
+	body := make([]byte, size)
+	if _, err := io.ReadFull(s.reader, body); err != nil {
+		return 0, nil, err
+	}
+	return flags, body, nil
+}
+
+// receive returns the frames of the next message. Commands sent by the peer are skipped.
+func (s *zmqSubscriber) receive() ([][]byte, error) {
+	var frames [][]byte
+	for {
+		flags, body, err := s.readFrame()
+		if err != nil {
+			return nil, err
+		}
+		if flags&zmtpFlagCommand != 0 {
+			continue
+		}
+		frames = append(frames, body)
+		if flags&zmtpFlagMore == 0 {
+			return frames, nil
+		}
+	}
+}
+
+// Close closes the connection to the publisher.
+func (s *zmqSubscriber) Close() error {
+	s.stop()
+	return s.conn.Close()
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package outlierdetection
+
+import (
+	"time"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datalayer"
+)
+
+const (
+	OutlierStatusKey = "OutlierStatusKey"
+)
+
+// OutlierStatus is the outlier detection status of an endpoint.
+type OutlierStatus struct {
+	// ConsecutiveFailures is the number of requests that failed in a row on the endpoint since its last success or
+	// ejection.
+	ConsecutiveFailures int
+	// Ejections is the number of times the endpoint was ejected in a row, which the ejection time grows with.
+	Ejections int
+	// EjectedUntil is the time until which the endpoint is ejected, zero if it was never ejected.
+	EjectedUntil time.Time
+	// Ejected is true if the endpoint was ejected when the status was taken.
+	Ejected bool
+}
+
+func (s *OutlierStatus) Clone() datalayer.Cloneable {
+	clone := *s
+	return &clone
+}
@@ -307,7 +307,16 @@ func (m *MockManagedQueue) PeekHead() types.QueueItemAccessor {
 	return nil // Queue is empty
 }
 
-// PeekTail is not implemented for this mock.
+// PeekTail returns the most recently enqueued item in the mock queue.
 func (m *MockManagedQueue) PeekTail() types.QueueItemAccessor {
-	return nil
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.init()
+	var tail types.QueueItemAccessor
+	for _, item := range m.items {
+		if tail == nil || item.EnqueueTime().After(tail.EnqueueTime()) {
+			tail = item
+		}
+	}
+	return tail
 }
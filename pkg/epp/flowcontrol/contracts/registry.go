@@ -155,7 +155,8 @@ type ManagedQueue interface {
 	// Returns ErrShardDraining if the parent shard is no longer Active.
 	Add(item types.QueueItemAccessor) error
 
-	// Remove atomically finds and removes an item from the underlying queue using its handle.
+	// Remove atomically finds and removes an item from the underlying queue using its handle, to dispatch it.
+	// The removal is counted in the DispatchRate of the band.
 	Remove(handle types.QueueItemHandle) (types.QueueItemAccessor, error)
 
 	// Cleanup removes all items from the underlying queue that satisfy the predicate.
@@ -225,4 +226,7 @@ type PriorityBandStats struct {
 	ByteSize uint64
 	// Len is the total number of items currently queued in this priority band.
 	Len uint64
+	// DispatchRate is the number of items dispatched from this priority band per second, measured over the recent past.
+	// Together with Len, it estimates how long a new item would wait in this band.
+	DispatchRate float64
 }
@@ -37,6 +37,14 @@ type Config struct {
 	// Optional: If zero, no TTL is applied by default and we rely solely on request context cancellation.
 	DefaultRequestTTL time.Duration
 
+	// MinRequestTTL is the lower bound applied to the TTL hints of requests (`FlowControlRequest.InitialEffectiveTTL`).
+	// Optional: If zero, the hints are not bounded from below.
+	MinRequestTTL time.Duration
+
+	// MaxRequestTTL is the upper bound applied to the TTL hints of requests (`FlowControlRequest.InitialEffectiveTTL`).
+	// Optional: If zero, the hints are not bounded from above.
+	MaxRequestTTL time.Duration
+
 	// ExpiryCleanupInterval is the interval at which each shard processor scans its queues for expired items.
 	// Optional: Defaults to `defaultExpiryCleanupInterval` (1 second).
 	ExpiryCleanupInterval time.Duration
@@ -51,6 +59,12 @@ type Config struct {
 	// serial execution loop and allowing the system to handle short bursts of traffic without blocking.
 	// Optional: Defaults to `defaultEnqueueChannelBufferSize` (100).
 	EnqueueChannelBufferSize int
+
+	// EnablePreemption enables the eviction of queued requests of lower priority to make room for requests of higher
+	// priority when the global capacity of a shard is exhausted. The evicted requests finalize with
+	// `types.QueueOutcomeEvictedPreempted`.
+	// Optional: Defaults to false, rejecting the requests that do not fit.
+	EnablePreemption bool
 }
 
 // ValidateAndApplyDefaults checks the global configuration for validity and then creates a new `Config` object,
@@ -63,6 +77,16 @@ func (c *Config) ValidateAndApplyDefaults() (*Config, error) {
 	if cfg.DefaultRequestTTL < 0 {
 		return nil, fmt.Errorf("DefaultRequestTTL cannot be negative, but got %v", cfg.DefaultRequestTTL)
 	}
+	if cfg.MinRequestTTL < 0 {
+		return nil, fmt.Errorf("MinRequestTTL cannot be negative, but got %v", cfg.MinRequestTTL)
+	}
+	if cfg.MaxRequestTTL < 0 {
+		return nil, fmt.Errorf("MaxRequestTTL cannot be negative, but got %v", cfg.MaxRequestTTL)
+	}
+	if cfg.MaxRequestTTL > 0 && cfg.MaxRequestTTL < cfg.MinRequestTTL {
+		return nil, fmt.Errorf("MaxRequestTTL (%v) cannot be smaller than MinRequestTTL (%v)",
+			cfg.MaxRequestTTL, cfg.MinRequestTTL)
+	}
 	if cfg.ExpiryCleanupInterval < 0 {
 		return nil, fmt.Errorf("ExpiryCleanupInterval cannot be negative, but got %v", cfg.ExpiryCleanupInterval)
 	}
@@ -94,9 +118,12 @@ func (c *Config) deepCopy() *Config {
 	}
 	newCfg := &Config{
 		DefaultRequestTTL:               c.DefaultRequestTTL,
+		MinRequestTTL:                   c.MinRequestTTL,
+		MaxRequestTTL:                   c.MaxRequestTTL,
 		ExpiryCleanupInterval:           c.ExpiryCleanupInterval,
 		ProcessorReconciliationInterval: c.ProcessorReconciliationInterval,
 		EnqueueChannelBufferSize:        c.EnqueueChannelBufferSize,
+		EnablePreemption:                c.EnablePreemption,
 	}
 	return newCfg
 }
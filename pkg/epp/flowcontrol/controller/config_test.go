@@ -67,6 +67,35 @@ func TestConfig_ValidateAndApplyDefaults(t *testing.T) {
 			input:     Config{DefaultRequestTTL: -1},
 			expectErr: true,
 		},
+		{
+			name: "RequestTTLBounds_Valid",
+			input: Config{
+				MinRequestTTL: time.Second,
+				MaxRequestTTL: time.Minute,
+			},
+			expectedCfg: Config{
+				MinRequestTTL:                   time.Second,
+				MaxRequestTTL:                   time.Minute,
+				ExpiryCleanupInterval:           defaultExpiryCleanupInterval,
+				ProcessorReconciliationInterval: defaultProcessorReconciliationInterval,
+				EnqueueChannelBufferSize:        defaultEnqueueChannelBufferSize,
+			},
+		},
+		{
+			name:      "NegativeMinRequestTTL_Invalid",
+			input:     Config{MinRequestTTL: -1},
+			expectErr: true,
+		},
+		{
+			name:      "NegativeMaxRequestTTL_Invalid",
+			input:     Config{MaxRequestTTL: -1},
+			expectErr: true,
+		},
+		{
+			name:      "MaxRequestTTLBelowMin_Invalid",
+			input:     Config{MinRequestTTL: time.Minute, MaxRequestTTL: time.Second},
+			expectErr: true,
+		},
 		{
 			name:      "NegativeExpiryCleanupInterval_Invalid",
 			input:     Config{ExpiryCleanupInterval: -1},
@@ -118,9 +147,12 @@ func TestConfig_DeepCopy(t *testing.T) {
 		t.Parallel()
 		original := &Config{
 			DefaultRequestTTL:               1 * time.Second,
+			MinRequestTTL:                   500 * time.Millisecond,
+			MaxRequestTTL:                   time.Minute,
 			ExpiryCleanupInterval:           2 * time.Second,
 			ProcessorReconciliationInterval: 3 * time.Second,
 			EnqueueChannelBufferSize:        4,
+			EnablePreemption:                true,
 		}
 		clone := original.deepCopy()
 
@@ -66,6 +66,7 @@ type shardProcessorFactory func(
 	clock clock.WithTicker,
 	cleanupSweepInterval time.Duration,
 	enqueueChannelBufferSize int,
+	preemption bool,
 	logger logr.Logger,
 ) shardProcessor
 
@@ -151,6 +152,7 @@ func NewFlowController(
 		clock clock.WithTicker,
 		cleanupSweepInterval time.Duration,
 		enqueueChannelBufferSize int,
+		preemption bool,
 		logger logr.Logger,
 	) shardProcessor {
 		return internal.NewShardProcessor(
@@ -161,6 +163,7 @@ func NewFlowController(
 			clock,
 			cleanupSweepInterval,
 			enqueueChannelBufferSize,
+			preemption,
 			logger)
 	}
 
@@ -291,9 +294,50 @@ func (fc *FlowController) EnqueueAndWait(
 		return types.QueueOutcomeRejectedOther, fmt.Errorf("%w: %w", types.ErrRejected, err)
 	}
 
+	// Requests rejected or evicted because the band is congested are told how long it is expected to stay congested, so
+	// that callers can back off instead of retrying immediately.
+	if finalOutcome == types.QueueOutcomeRejectedCapacity || finalOutcome == types.QueueOutcomeEvictedTTL ||
+		finalOutcome == types.QueueOutcomeEvictedPreempted {
+		err = &types.QueueStateError{Err: err, State: fc.queueState(flowKey.Priority)}
+	}
 	return finalOutcome, err
 }
 
+const (
+	// minEstimatedWait is the lower bound of the estimated wait of a priority band.
+	minEstimatedWait = time.Second
+	// maxEstimatedWait is the upper bound of the estimated wait of a priority band, also used when the band has not
+	// dispatched any items recently.
+	maxEstimatedWait = time.Minute
+)
+
+// queueState returns the state of the priority band across all shards, estimating how long its queued items take to
+// be dispatched from its recent dispatch rate.
+func (fc *FlowController) queueState(priority int) types.QueueState {
+	state := types.QueueState{Priority: priority}
+	var dispatchRate float64
+	for _, shardStats := range fc.registry.ShardStats() {
+		bandStats, ok := shardStats.PerPriorityBandStats[priority]
+		if !ok {
+			continue
+		}
+		state.PriorityName = bandStats.PriorityName
+		state.QueueDepth += bandStats.Len
+		dispatchRate += bandStats.DispatchRate
+	}
+
+	switch {
+	case state.QueueDepth == 0:
+		state.EstimatedWait = minEstimatedWait
+	case dispatchRate == 0:
+		state.EstimatedWait = maxEstimatedWait
+	default:
+		wait := time.Duration(float64(state.QueueDepth) / dispatchRate * float64(time.Second))
+		state.EstimatedWait = min(max(wait, minEstimatedWait), maxEstimatedWait)
+	}
+	return state
+}
+
 var errNoShards = errors.New("no viable active shards available")
 
 // tryDistribution handles a single attempt to select a shard and submit a request.
@@ -376,7 +420,7 @@ func (fc *FlowController) createRequestContext(
 	req types.FlowControlRequest,
 ) (context.Context, context.CancelFunc, time.Time) {
 	enqueueTime := fc.clock.Now()
-	effectiveTTL := req.InitialEffectiveTTL()
+	effectiveTTL := fc.boundedTTL(req.InitialEffectiveTTL())
 	if effectiveTTL <= 0 {
 		effectiveTTL = fc.config.DefaultRequestTTL
 	}
@@ -389,6 +433,21 @@ func (fc *FlowController) createRequestContext(
 	return reqCtx, cancel, enqueueTime
 }
 
+// boundedTTL clamps the TTL hint of a request to the configured bounds. A zero hint, which asks for the default TTL,
+// is returned as is.
+func (fc *FlowController) boundedTTL(ttl time.Duration) time.Duration {
+	if ttl <= 0 {
+		return 0
+	}
+	if ttl < fc.config.MinRequestTTL {
+		return fc.config.MinRequestTTL
+	}
+	if fc.config.MaxRequestTTL > 0 && ttl > fc.config.MaxRequestTTL {
+		return fc.config.MaxRequestTTL
+	}
+	return ttl
+}
+
 // candidate holds the information needed to evaluate a shard as a potential target for a request.
 type candidate struct {
 	processor shardProcessor
@@ -487,6 +546,7 @@ func (fc *FlowController) getOrStartWorker(shard contracts.RegistryShard) *manag
 		fc.clock,
 		fc.config.ExpiryCleanupInterval,
 		fc.config.EnqueueChannelBufferSize,
+		fc.config.EnablePreemption,
 		fc.logger.WithValues("shardID", shard.ID()),
 	)
 	newWorker := &managedWorker{
@@ -258,6 +258,7 @@ func (f *mockShardProcessorFactory) new(
 	_ clock.WithTicker,
 	_ time.Duration,
 	_ int,
+	_ bool,
 	_ logr.Logger,
 ) shardProcessor {
 	f.mu.Lock()
@@ -395,6 +396,36 @@ func TestFlowController_EnqueueAndWait(t *testing.T) {
 				"outcome should be QueueOutcomeRejectedCapacity when no shards exist for the flow")
 		})
 
+		t.Run("AttachesQueueState_OnCapacityRejection", func(t *testing.T) {
+			t.Parallel()
+			mockRegistry := &mockRegistryClient{
+				ShardStatsFunc: func() []contracts.ShardStats {
+					bandStats := contracts.PriorityBandStats{
+						Priority: defaultFlowKey.Priority, PriorityName: "Standard", Len: 30, DispatchRate: 5,
+					}
+					return []contracts.ShardStats{
+						{ID: "shard-A", PerPriorityBandStats: map[int]contracts.PriorityBandStats{defaultFlowKey.Priority: bandStats}},
+						{ID: "shard-B", PerPriorityBandStats: map[int]contracts.PriorityBandStats{defaultFlowKey.Priority: bandStats}},
+					}
+				},
+			}
+			h := newUnitHarness(t, t.Context(), Config{}, mockRegistry)
+
+			req := newTestRequest(defaultFlowKey)
+			outcome, err := h.fc.EnqueueAndWait(context.Background(), req)
+			require.Error(t, err, "EnqueueAndWait must reject requests if no shards are available")
+			assert.Equal(t, types.QueueOutcomeRejectedCapacity, outcome)
+			assert.ErrorIs(t, err, types.ErrRejected, "error should still wrap ErrRejected")
+			var queueStateErr *types.QueueStateError
+			require.ErrorAs(t, err, &queueStateErr, "error should carry the state of the band")
+			assert.Equal(t, types.QueueState{
+				Priority:      defaultFlowKey.Priority,
+				PriorityName:  "Standard",
+				QueueDepth:    60,
+				EstimatedWait: 6 * time.Second,
+			}, queueStateErr.State, "the wait should be estimated from the depth and the dispatch rate of all shards")
+		})
+
 		t.Run("OnRegistryConnectionError", func(t *testing.T) {
 			t.Parallel()
 			mockRegistry := &mockRegistryClient{}
@@ -644,6 +675,75 @@ func TestFlowController_EnqueueAndWait(t *testing.T) {
 		}
 	})
 
+	t.Run("RequestTTL", func(t *testing.T) {
+		t.Parallel()
+
+		testCases := []struct {
+			name        string
+			config      Config
+			requestTTL  time.Duration
+			expectedTTL time.Duration
+		}{
+			{
+				name:        "UsesDefault_WithoutHint",
+				config:      Config{DefaultRequestTTL: 30 * time.Second},
+				expectedTTL: 30 * time.Second,
+			},
+			{
+				name:        "UsesHint_OverDefault",
+				config:      Config{DefaultRequestTTL: 30 * time.Second},
+				requestTTL:  2 * time.Minute,
+				expectedTTL: 2 * time.Minute,
+			},
+			{
+				name:        "ClampsHint_ToMinimum",
+				config:      Config{DefaultRequestTTL: 30 * time.Second, MinRequestTTL: 5 * time.Second},
+				requestTTL:  time.Second,
+				expectedTTL: 5 * time.Second,
+			},
+			{
+				name:        "ClampsHint_ToMaximum",
+				config:      Config{DefaultRequestTTL: 30 * time.Second, MaxRequestTTL: time.Minute},
+				requestTTL:  time.Hour,
+				expectedTTL: time.Minute,
+			},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+				mockRegistry := &mockRegistryClient{
+					WithConnectionFunc: func(
+						key types.FlowKey,
+						fn func(conn contracts.ActiveFlowConnection) error,
+					) error {
+						return fn(&mockActiveFlowConnection{
+							ActiveShardsV: []contracts.RegistryShard{newMockShard("shard-A").build()},
+							FlowKeyV:      key,
+						})
+					},
+				}
+				h := newUnitHarness(t, t.Context(), tc.config, mockRegistry)
+				var effectiveTTL time.Duration
+				h.mockProcessorFactory.processors["shard-A"] = &mockShardProcessor{
+					SubmitFunc: func(item *internal.FlowItem) error {
+						effectiveTTL = item.EffectiveTTL()
+						go item.FinalizeWithOutcome(types.QueueOutcomeDispatched, nil)
+						return nil
+					},
+				}
+
+				req := newTestRequest(defaultFlowKey)
+				req.InitialEffectiveTTLV = tc.requestTTL
+				outcome, err := h.fc.EnqueueAndWait(context.Background(), req)
+				require.NoError(t, err, "EnqueueAndWait should dispatch the request")
+				assert.Equal(t, types.QueueOutcomeDispatched, outcome)
+				assert.InDelta(t, tc.expectedTTL, effectiveTTL, float64(time.Second),
+					"the item TTL should be the bounded request hint, or the default without one")
+			})
+		}
+	})
+
 	t.Run("Retry", func(t *testing.T) {
 		t.Parallel()
 
@@ -1118,6 +1218,7 @@ func TestFlowController_WorkerManagement(t *testing.T) {
 			_ clock.WithTicker,
 			_ time.Duration,
 			_ int,
+			_ bool,
 			_ logr.Logger,
 		) shardProcessor {
 			// This function is called by getOrStartWorker before the LoadOrStore check.
@@ -70,6 +70,10 @@ type ShardProcessor struct {
 	cleanupSweepInterval time.Duration
 	logger               logr.Logger
 
+	// preemption enables the eviction of queued items of lower priority to make room for new items when the shard is
+	// at capacity.
+	preemption bool
+
 	// lifecycleCtx controls the processor's lifetime. Monitored by Submit* methods for safe shutdown.
 	lifecycleCtx context.Context
 
@@ -91,6 +95,7 @@ func NewShardProcessor(
 	clock clock.WithTicker,
 	cleanupSweepInterval time.Duration,
 	enqueueChannelBufferSize int,
+	preemption bool,
 	logger logr.Logger,
 ) *ShardProcessor {
 	return &ShardProcessor{
@@ -100,6 +105,7 @@ func NewShardProcessor(
 		clock:                clock,
 		cleanupSweepInterval: cleanupSweepInterval,
 		logger:               logger,
+		preemption:           preemption,
 		lifecycleCtx:         ctx,
 		enqueueChan:          make(chan *FlowItem, enqueueChannelBufferSize),
 	}
@@ -240,7 +246,7 @@ func (sp *ShardProcessor) enqueue(item *FlowItem) {
 
 	// --- Capacity Check ---
 	// This check is safe because it is performed by the single-writer Run goroutine.
-	if !sp.hasCapacity(key.Priority, req.ByteSize()) {
+	if !sp.hasCapacity(key.Priority, req.ByteSize()) && !sp.preemptLowerPriorities(key.Priority, req.ByteSize()) {
 		sp.logger.V(logutil.DEBUG).Info("Rejecting request, queue at capacity",
 			"flowKey", key, "reqID", req.ID(), "priorityName", band.PriorityName(), "reqByteSize", req.ByteSize())
 		item.FinalizeWithOutcome(types.QueueOutcomeRejectedCapacity, fmt.Errorf("%w: %w",
@@ -279,6 +285,88 @@ func (sp *ShardProcessor) hasCapacity(priority int, itemByteSize uint64) bool {
 	return bandStats.ByteSize+itemByteSize <= bandStats.CapacityBytes
 }
 
+// preemptLowerPriorities makes room for an item when only the capacity of the shard prevents its admission, by
+// evicting queued items of lower priority bands. It returns true if the item can then be admitted.
+//
+// Victims are taken from the lowest priority band first and, within a band, from the tail of its largest queue: the
+// newest item of FCFS queues, or the item its ordering policy would dispatch last. Nothing is evicted if the lower
+// bands do not hold enough bytes to make room for the item.
+//
+// This is safe because it is performed by the single-writer Run goroutine.
+func (sp *ShardProcessor) preemptLowerPriorities(priority int, itemByteSize uint64) bool {
+	if !sp.preemption {
+		return false
+	}
+	stats := sp.shard.Stats()
+	bandStats, ok := stats.PerPriorityBandStats[priority]
+	if !ok || bandStats.ByteSize+itemByteSize > bandStats.CapacityBytes {
+		return false // The limit of the band itself cannot be relieved by evicting other bands.
+	}
+	needed := stats.TotalByteSize + itemByteSize - stats.TotalCapacityBytes
+
+	levels := sp.shard.AllOrderedPriorityLevels()
+	var evictable uint64
+	for _, level := range levels {
+		if level < priority {
+			evictable += stats.PerPriorityBandStats[level].ByteSize
+		}
+	}
+	if evictable < needed {
+		return false
+	}
+
+	var freed uint64
+	for i := len(levels) - 1; i >= 0 && levels[i] < priority && freed < needed; i-- {
+		band, err := sp.shard.PriorityBandAccessor(levels[i])
+		if err != nil {
+			sp.logger.Error(err, "Failed to get PriorityBandAccessor, skipping band for preemption", "priority", levels[i])
+			continue
+		}
+		for freed < needed {
+			victim := sp.evictTail(band)
+			if victim == nil {
+				break // The band is empty.
+			}
+			freed += victim.OriginalRequest().ByteSize()
+			sp.logger.V(logutil.DEBUG).Info("Preempted item to make room for a higher priority request",
+				"flowKey", victim.OriginalRequest().FlowKey(), "reqID", victim.OriginalRequest().ID(),
+				"priorityName", band.PriorityName(), "preemptingPriority", priority)
+		}
+	}
+	return sp.hasCapacity(priority, itemByteSize)
+}
+
+// evictTail removes the tail item of the largest queue of the band and finalizes it as preempted. It returns nil if
+// the band has no items.
+func (sp *ShardProcessor) evictTail(band framework.PriorityBandAccessor) *FlowItem {
+	var largest framework.FlowQueueAccessor
+	band.IterateQueues(func(queue framework.FlowQueueAccessor) bool {
+		if queue.Len() > 0 && (largest == nil || queue.ByteSize() > largest.ByteSize()) {
+			largest = queue
+		}
+		return true
+	})
+	if largest == nil {
+		return nil
+	}
+	victim, ok := largest.PeekTail().(*FlowItem)
+	if !ok {
+		return nil
+	}
+	managedQ, err := sp.shard.ManagedQueue(largest.FlowKey())
+	if err != nil {
+		sp.logger.Error(err, "Failed to get ManagedQueue for preemption", "flowKey", largest.FlowKey())
+		return nil
+	}
+	if removed := managedQ.Cleanup(func(item types.QueueItemAccessor) bool { return item == victim }); len(removed) == 0 {
+		return nil
+	}
+	// Finalization is idempotent; the victim may already have been finalized externally without being swept.
+	victim.FinalizeWithOutcome(types.QueueOutcomeEvictedPreempted,
+		fmt.Errorf("%w: %w", types.ErrEvicted, types.ErrPreempted))
+	return victim
+}
+
 // dispatchCycle attempts to dispatch a single item by iterating through priority bands from highest to lowest.
 // It applies the configured policies for each band to select an item and then attempts to dispatch it.
 // It returns true if an item was successfully dispatched, and false otherwise.
@@ -127,6 +127,7 @@ func newTestHarness(t *testing.T, expiryCleanupInterval time.Duration) *testHarn
 		h.clock,
 		expiryCleanupInterval,
 		100,
+		false,
 		h.logger)
 	require.NotNil(t, h.processor, "NewShardProcessor should not return nil")
 
@@ -707,6 +708,111 @@ func TestShardProcessor(t *testing.T) {
 			}
 		})
 
+		t.Run("preemptLowerPriorities", func(t *testing.T) {
+			t.Parallel()
+			lowFlow := types.FlowKey{ID: "flow-low", Priority: 0}
+
+			// newPreemptionHarness returns a harness whose shard holds 300 bytes, with stats computed from the contents of
+			// its queues, and whose low priority queue holds the given number of items of 100 bytes, oldest first.
+			newPreemptionHarness := func(t *testing.T, preemption bool, lowItems int) (*testHarness, []*FlowItem) {
+				h := newTestHarness(t, testCleanupTick)
+				h.processor.preemption = preemption
+				h.addQueue(testFlow)
+				lowQueue := h.addQueue(lowFlow)
+				h.StatsFunc = func() contracts.ShardStats {
+					stats := contracts.ShardStats{
+						TotalCapacityBytes:   300,
+						PerPriorityBandStats: map[int]contracts.PriorityBandStats{},
+					}
+					h.mu.Lock()
+					defer h.mu.Unlock()
+					for key, q := range h.queues {
+						band := stats.PerPriorityBandStats[key.Priority]
+						band.CapacityBytes = 1000
+						band.ByteSize += q.ByteSize()
+						stats.PerPriorityBandStats[key.Priority] = band
+						stats.TotalByteSize += q.ByteSize()
+					}
+					return stats
+				}
+				var items []*FlowItem
+				for i := range lowItems {
+					h.clock.Step(time.Millisecond)
+					item := h.newTestItem(fmt.Sprintf("req-low-%d", i), lowFlow, testTTL)
+					require.NoError(t, lowQueue.Add(item), "precondition: Add should not fail")
+					items = append(items, item)
+				}
+				return h, items
+			}
+
+			t.Run("should evict the newest lower priority item to admit a higher priority item", func(t *testing.T) {
+				t.Parallel()
+				h, lowItems := newPreemptionHarness(t, true, 3)
+				item := h.newTestItem("req-high", testFlow, testTTL)
+
+				h.processor.enqueue(item)
+
+				assert.Nil(t, item.FinalState(), "The higher priority item should have been admitted")
+				assert.Equal(t, 1, h.queues[testFlow].Len(), "The higher priority item should be queued")
+				assert.Equal(t, 2, h.queues[lowFlow].Len(), "A single lower priority item should have been evicted")
+				victim := lowItems[2]
+				require.NotNil(t, victim.FinalState(), "The newest lower priority item should have been finalized")
+				assert.Equal(t, types.QueueOutcomeEvictedPreempted, victim.FinalState().Outcome,
+					"The outcome of the victim should be EvictedPreempted")
+				assert.ErrorIs(t, victim.FinalState().Err, types.ErrPreempted, "The error should wrap ErrPreempted")
+				assert.ErrorIs(t, victim.FinalState().Err, types.ErrEvicted, "The error should wrap ErrEvicted")
+				assert.Nil(t, lowItems[0].FinalState(), "Older lower priority items should not be evicted")
+				assert.Nil(t, lowItems[1].FinalState(), "Older lower priority items should not be evicted")
+			})
+
+			t.Run("should reject without evicting when preemption is disabled", func(t *testing.T) {
+				t.Parallel()
+				h, lowItems := newPreemptionHarness(t, false, 3)
+				item := h.newTestItem("req-high", testFlow, testTTL)
+
+				h.processor.enqueue(item)
+
+				require.NotNil(t, item.FinalState(), "The higher priority item should have been rejected")
+				assert.Equal(t, types.QueueOutcomeRejectedCapacity, item.FinalState().Outcome,
+					"The outcome should be RejectedCapacity")
+				for _, lowItem := range lowItems {
+					assert.Nil(t, lowItem.FinalState(), "No lower priority item should be evicted")
+				}
+			})
+
+			t.Run("should not evict items of the same or higher priority", func(t *testing.T) {
+				t.Parallel()
+				h, lowItems := newPreemptionHarness(t, true, 3)
+				item := h.newTestItem("req-low-new", lowFlow, testTTL)
+
+				h.processor.enqueue(item)
+
+				require.NotNil(t, item.FinalState(), "The item should have been rejected")
+				assert.Equal(t, types.QueueOutcomeRejectedCapacity, item.FinalState().Outcome,
+					"The outcome should be RejectedCapacity")
+				for _, lowItem := range lowItems {
+					assert.Nil(t, lowItem.FinalState(), "No item of the same priority should be evicted")
+				}
+			})
+
+			t.Run("should not evict anything if lower priorities cannot make enough room", func(t *testing.T) {
+				t.Parallel()
+				h, lowItems := newPreemptionHarness(t, true, 1)
+				highItems := []*FlowItem{
+					h.newTestItem("req-high-1", testFlow, testTTL),
+					h.newTestItem("req-high-2", testFlow, testTTL),
+				}
+				for _, highItem := range highItems {
+					require.NoError(t, h.queues[testFlow].Add(highItem), "precondition: Add should not fail")
+				}
+
+				// The shard is full (300 bytes) and only 100 of them are held by lower priorities.
+				assert.False(t, h.processor.preemptLowerPriorities(testFlow.Priority, 200),
+					"Preemption should fail when lower priorities hold less than the missing bytes")
+				assert.Nil(t, lowItems[0].FinalState(), "No lower priority item should be evicted")
+			})
+		})
+
 		t.Run("dispatchCycle", func(t *testing.T) {
 			t.Parallel()
 
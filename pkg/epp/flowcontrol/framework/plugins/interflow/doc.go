@@ -52,6 +52,10 @@ limitations under the License.
 //     This guarantees that no single flow can starve others, regardless of its volume.
 //     It is "Work Conserving" (it skips empty queues).
 //
+//   - Weighted Fair Queuing ("weighted-fair-queuing-fairness-policy"): Shares the band between active flows in
+//     proportion to weights configured per FlowKey.ID, accounting for the cost of requests in estimated tokens.
+//     It keeps a virtual finish time per flow in its band state and picks the flow that would finish first.
+//
 //   - Global Strict ("global-strict-fairness-policy"): A greedy strategy that ignores Flow boundaries.
 //     It scans all queues in the band and picks the absolute "best" request (e.g., oldest timestamp) globally.
 //     This maximizes strict adherence to global ordering but offers no isolation; a noisy neighbor can starve other
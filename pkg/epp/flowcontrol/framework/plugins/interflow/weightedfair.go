@@ -0,0 +1,225 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package interflow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/flowcontrol/framework"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/flowcontrol/types"
+	fwkplugin "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/plugin"
+)
+
+// WeightedFairQueuingFairnessPolicyType represents a fairness policy that shares the dispatch capacity of a priority
+// band between its flows in proportion to configured weights, accounting for the cost of requests in estimated tokens.
+const WeightedFairQueuingFairnessPolicyType = "weighted-fair-queuing-fairness-policy"
+
+// bytesPerToken is the number of request bytes per token, used to estimate the cost of requests that do not estimate
+// their own tokens.
+const bytesPerToken = 4
+
+func init() {
+	fwkplugin.Register(WeightedFairQueuingFairnessPolicyType, WeightedFairQueuingFairnessPolicyFactory)
+}
+
+// WeightedFairQueuingParameters are the parameters of the weighted fair queuing fairness policy.
+type WeightedFairQueuingParameters struct {
+	// Weights are the shares of the flows, keyed by FlowKey.ID. A flow with a weight of 3 is dispatched three times as
+	// many tokens as a flow with a weight of 1 when both are backlogged in the same priority band.
+	Weights map[string]float64 `json:"weights,omitempty"`
+	// DefaultWeight is the weight of the flows missing from Weights. Defaults to 1.
+	DefaultWeight float64 `json:"defaultWeight,omitempty"`
+}
+
+func WeightedFairQueuingFairnessPolicyFactory(name string, rawParameters json.RawMessage, _ fwkplugin.Handle) (fwkplugin.Plugin, error) {
+	parameters := WeightedFairQueuingParameters{DefaultWeight: 1}
+	if len(rawParameters) > 0 {
+		if err := json.Unmarshal(rawParameters, &parameters); err != nil {
+			return nil, fmt.Errorf("failed to parse the parameters of the '%s' fairness policy - %w",
+				WeightedFairQueuingFairnessPolicyType, err)
+		}
+	}
+	if err := parameters.validate(); err != nil {
+		return nil, fmt.Errorf("invalid parameters of the '%s' fairness policy - %w",
+			WeightedFairQueuingFairnessPolicyType, err)
+	}
+	return newWeightedFairQueuing(name, parameters), nil
+}
+
+func (p *WeightedFairQueuingParameters) validate() error {
+	var errs []error
+	if p.DefaultWeight <= 0 {
+		errs = append(errs, fmt.Errorf("defaultWeight must be > 0, got %g", p.DefaultWeight))
+	}
+	for id, weight := range p.Weights {
+		if weight <= 0 {
+			errs = append(errs, fmt.Errorf("weight of flow %q must be > 0, got %g", id, weight))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// weightedFairQueuing implements FairnessPolicy.
+//
+// It is a self-clocked weighted fair queuing scheduler. Each flow advances its own virtual finish time by the cost of
+// its dispatched requests divided by its weight, and the flow whose head request would finish first in virtual time
+// is picked. Backlogged flows are therefore dispatched tokens in proportion to their weights, whatever the number and
+// the size of their requests.
+//
+// The virtual time of the band is the virtual finish time of its last dispatched request. A flow becoming active
+// starts at the virtual time of the band, so that it cannot claim the capacity it did not use while it was idle.
+type weightedFairQueuing struct {
+	name       string
+	parameters WeightedFairQueuingParameters
+}
+
+func newWeightedFairQueuing(name string, parameters WeightedFairQueuingParameters) *weightedFairQueuing {
+	if name == "" {
+		name = WeightedFairQueuingFairnessPolicyType
+	}
+	return &weightedFairQueuing{name: name, parameters: parameters}
+}
+
+// TypedName returns the type and name tuple of this plugin instance.
+func (p *weightedFairQueuing) TypedName() fwkplugin.TypedName {
+	return fwkplugin.TypedName{
+		Type: WeightedFairQueuingFairnessPolicyType,
+		Name: p.name,
+	}
+}
+
+// weightedFairQueuingState holds the virtual times of a specific priority band.
+// It is initialized via NewState and stored on the PriorityBandAccessor.
+type weightedFairQueuingState struct {
+	mu sync.Mutex
+	// virtualTime is the virtual finish time of the last dispatched request of the band.
+	virtualTime float64
+	// finishTimes are the virtual finish times of the last dispatched request of each flow, keyed by FlowKey.ID.
+	finishTimes map[string]float64
+	// active holds the IDs of the flows that had requests queued at the last pick.
+	active map[string]struct{}
+	// picked is the head item of the last picked flow. The controller dispatches it after the pick unless the pool is
+	// saturated, in which case the same flow is picked again. It is charged to its flow once it has left the queue.
+	picked     types.QueueItemAccessor
+	pickedFlow string
+}
+
+// NewState initializes the policy state for a specific priority band.
+func (p *weightedFairQueuing) NewState(_ context.Context) any {
+	return &weightedFairQueuingState{finishTimes: map[string]float64{}, active: map[string]struct{}{}}
+}
+
+// Pick selects the flow whose head request has the earliest virtual finish time.
+func (p *weightedFairQueuing) Pick(
+	_ context.Context,
+	flowGroup framework.PriorityBandAccessor,
+) (framework.FlowQueueAccessor, error) {
+	if flowGroup == nil {
+		return nil, nil
+	}
+
+	v := flowGroup.PolicyState()
+	s, ok := v.(*weightedFairQueuingState)
+	if !ok {
+		return nil, fmt.Errorf("invalid state type for WeightedFairQueuing policy: expected *weightedFairQueuingState, got %T", v)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.chargePicked(p)
+
+	var bestQueue framework.FlowQueueAccessor
+	var bestItem types.QueueItemAccessor
+	var bestFinish float64
+	active := make(map[string]struct{}, len(s.active))
+	flowGroup.IterateQueues(func(queue framework.FlowQueueAccessor) (keepIterating bool) {
+		if queue == nil || queue.Len() == 0 {
+			return true
+		}
+		item := queue.PeekHead()
+		if item == nil {
+			return true
+		}
+		key := queue.FlowKey()
+		active[key.ID] = struct{}{}
+		if _, ok := s.active[key.ID]; !ok {
+			s.finishTimes[key.ID] = max(s.finishTimes[key.ID], s.virtualTime)
+		}
+		finish := s.finishTimes[key.ID] + cost(item)/p.weight(key.ID)
+		// Ties are broken on the flow key, as the iteration order is not guaranteed.
+		if bestQueue == nil || finish < bestFinish || (finish == bestFinish && key.Compare(bestQueue.FlowKey()) < 0) {
+			bestQueue, bestItem, bestFinish = queue, item, finish
+		}
+		return true
+	})
+	s.active = active
+
+	// The idle flows that are not ahead of the band start at its virtual time when they become active again.
+	for id, finish := range s.finishTimes {
+		if _, ok := active[id]; !ok && finish <= s.virtualTime {
+			delete(s.finishTimes, id)
+		}
+	}
+
+	if bestQueue == nil {
+		return nil, nil
+	}
+	s.picked, s.pickedFlow = bestItem, bestQueue.FlowKey().ID
+	return bestQueue, nil
+}
+
+// chargePicked advances the virtual time of the last picked flow by the cost of its picked item, if the item has left
+// its queue since, i.e. it was dispatched (or, more rarely, it expired or was cancelled while at the head).
+func (s *weightedFairQueuingState) chargePicked(p *weightedFairQueuing) {
+	if s.picked == nil {
+		return
+	}
+	if handle := s.picked.Handle(); handle != nil && !handle.IsInvalidated() {
+		return
+	}
+	s.finishTimes[s.pickedFlow] += cost(s.picked) / p.weight(s.pickedFlow)
+	s.virtualTime = s.finishTimes[s.pickedFlow]
+	s.picked, s.pickedFlow = nil, ""
+}
+
+// weight returns the weight of the flow.
+func (p *weightedFairQueuing) weight(flowID string) float64 {
+	if weight, ok := p.parameters.Weights[flowID]; ok {
+		return weight
+	}
+	return p.parameters.DefaultWeight
+}
+
+// cost returns the cost of the item in estimated tokens, at least 1 so that requests of unknown size still count.
+func cost(item types.QueueItemAccessor) float64 {
+	req := item.OriginalRequest()
+	if req == nil {
+		return 1
+	}
+	var tokens uint64
+	if estimating, ok := req.(types.TokenEstimatingRequest); ok {
+		tokens = estimating.EstimatedTokens()
+	} else {
+		tokens = req.ByteSize() / bytesPerToken
+	}
+	return float64(max(tokens, 1))
+}
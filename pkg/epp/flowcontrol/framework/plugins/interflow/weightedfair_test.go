@@ -0,0 +1,260 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package interflow
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/flowcontrol/framework"
+	frameworkmocks "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/flowcontrol/framework/mocks"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/flowcontrol/types"
+	typesmocks "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/flowcontrol/types/mocks"
+)
+
+// tokenRequest is a request estimating its own tokens.
+type tokenRequest struct {
+	*typesmocks.MockFlowControlRequest
+	tokens uint64
+}
+
+func (r *tokenRequest) EstimatedTokens() uint64 { return r.tokens }
+
+// backloggedQueue returns a queue that always has a request of the given number of tokens at its head.
+func backloggedQueue(key types.FlowKey, tokens uint64) *frameworkmocks.MockFlowQueueAccessor {
+	queue := &frameworkmocks.MockFlowQueueAccessor{LenV: 1, FlowKeyV: key}
+	refill(queue, tokens)
+	return queue
+}
+
+// refill puts a new request of the given number of tokens at the head of the queue.
+func refill(queue *frameworkmocks.MockFlowQueueAccessor, tokens uint64) {
+	queue.PeekHeadV = &typesmocks.MockQueueItemAccessor{
+		OriginalRequestV: &tokenRequest{MockFlowControlRequest: &typesmocks.MockFlowControlRequest{FlowKeyV: queue.FlowKeyV}, tokens: tokens},
+		HandleV:          &typesmocks.MockQueueItemHandle{},
+	}
+}
+
+// dispatch removes the head of the queue, as the controller does after a pick.
+func dispatch(queue *frameworkmocks.MockFlowQueueAccessor, tokens uint64) {
+	queue.PeekHeadV.Handle().Invalidate()
+	refill(queue, tokens)
+}
+
+func newMockBand(state any, queues ...*frameworkmocks.MockFlowQueueAccessor) *frameworkmocks.MockPriorityBandAccessor {
+	return &frameworkmocks.MockPriorityBandAccessor{
+		PolicyStateV: state,
+		FlowKeysFunc: func() []types.FlowKey {
+			keys := make([]types.FlowKey, 0, len(queues))
+			for _, queue := range queues {
+				keys = append(keys, queue.FlowKeyV)
+			}
+			return keys
+		},
+		IterateQueuesFunc: func(callback func(flow framework.FlowQueueAccessor) bool) {
+			for _, queue := range queues {
+				if !callback(queue) {
+					return
+				}
+			}
+		},
+	}
+}
+
+func TestWeightedFairQueuing_Name(t *testing.T) {
+	t.Parallel()
+	policy := newWeightedFairQueuing("test-wfq", WeightedFairQueuingParameters{DefaultWeight: 1})
+	assert.Equal(t, "test-wfq", policy.TypedName().Name)
+	assert.Equal(t, WeightedFairQueuingFairnessPolicyType, policy.TypedName().Type)
+}
+
+func TestWeightedFairQueuing_Pick_WeightedShares(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name       string
+		paidTokens uint64
+		freeTokens uint64
+	}{
+		{name: "Equal request sizes", paidTokens: 100, freeTokens: 100},
+		{name: "Free flow sends larger requests", paidTokens: 100, freeTokens: 300},
+		{name: "Paid flow sends larger requests", paidTokens: 500, freeTokens: 50},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			policy := newWeightedFairQueuing("", WeightedFairQueuingParameters{
+				Weights:       map[string]float64{"paid": 3},
+				DefaultWeight: 1,
+			})
+			ctx := context.Background()
+			paid := backloggedQueue(types.FlowKey{ID: "paid"}, tc.paidTokens)
+			free := backloggedQueue(types.FlowKey{ID: "free"}, tc.freeTokens)
+			band := newMockBand(policy.NewState(ctx), paid, free)
+
+			dispatchedTokens := map[string]uint64{}
+			for range 1000 {
+				selected, err := policy.Pick(ctx, band)
+				require.NoError(t, err)
+				require.NotNil(t, selected)
+				id := selected.FlowKey().ID
+				if id == "paid" {
+					dispatchedTokens[id] += tc.paidTokens
+					dispatch(paid, tc.paidTokens)
+				} else {
+					dispatchedTokens[id] += tc.freeTokens
+					dispatch(free, tc.freeTokens)
+				}
+			}
+
+			ratio := float64(dispatchedTokens["paid"]) / float64(dispatchedTokens["free"])
+			assert.InDelta(t, 3.0, ratio, 0.1, "paid flow should be dispatched 3 times the tokens of the free flow")
+		})
+	}
+}
+
+func TestWeightedFairQueuing_Pick_NotDispatched(t *testing.T) {
+	t.Parallel()
+	policy := newWeightedFairQueuing("", WeightedFairQueuingParameters{DefaultWeight: 1})
+	ctx := context.Background()
+	queue1 := backloggedQueue(flow1Key, 100)
+	queue2 := backloggedQueue(flow2Key, 100)
+	band := newMockBand(policy.NewState(ctx), queue1, queue2)
+
+	// The picked request is only charged once it left its queue: while the pool is saturated and it stays at the head,
+	// the same flow is picked again.
+	for range 3 {
+		selected, err := policy.Pick(ctx, band)
+		require.NoError(t, err)
+		assert.Equal(t, flow1Key, selected.FlowKey())
+	}
+
+	dispatch(queue1, 100)
+	selected, err := policy.Pick(ctx, band)
+	require.NoError(t, err)
+	assert.Equal(t, flow2Key, selected.FlowKey())
+}
+
+func TestWeightedFairQueuing_Pick_NewFlowStartsAtVirtualTime(t *testing.T) {
+	t.Parallel()
+	policy := newWeightedFairQueuing("", WeightedFairQueuingParameters{DefaultWeight: 1})
+	ctx := context.Background()
+	state := policy.NewState(ctx)
+	queue1 := backloggedQueue(flow1Key, 100)
+
+	// flow1 is served alone for a while.
+	for range 10 {
+		selected, err := policy.Pick(ctx, newMockBand(state, queue1))
+		require.NoError(t, err)
+		require.Equal(t, flow1Key, selected.FlowKey())
+		dispatch(queue1, 100)
+	}
+
+	// A flow becoming active does not get to monopolize the band to catch up on the time it was idle.
+	queue2 := backloggedQueue(flow2Key, 100)
+	band := newMockBand(state, queue1, queue2)
+	picks := map[string]int{}
+	for range 10 {
+		selected, err := policy.Pick(ctx, band)
+		require.NoError(t, err)
+		picks[selected.FlowKey().ID]++
+		dispatch(selected.(*frameworkmocks.MockFlowQueueAccessor), 100)
+	}
+	assert.Equal(t, 5, picks[flow1Key.ID])
+	assert.Equal(t, 5, picks[flow2Key.ID])
+}
+
+func TestWeightedFairQueuing_Pick_ByteSizeCost(t *testing.T) {
+	t.Parallel()
+	policy := newWeightedFairQueuing("", WeightedFairQueuingParameters{DefaultWeight: 1})
+	ctx := context.Background()
+
+	// Requests that do not estimate their tokens are charged from their byte size.
+	small := &frameworkmocks.MockFlowQueueAccessor{LenV: 1, FlowKeyV: flow1Key}
+	large := &frameworkmocks.MockFlowQueueAccessor{LenV: 1, FlowKeyV: flow2Key}
+	fill := func(queue *frameworkmocks.MockFlowQueueAccessor, byteSize uint64) {
+		queue.PeekHeadV = &typesmocks.MockQueueItemAccessor{
+			OriginalRequestV: typesmocks.NewMockFlowControlRequest(byteSize, "", queue.FlowKeyV),
+			HandleV:          &typesmocks.MockQueueItemHandle{},
+		}
+	}
+	fill(small, 400)
+	fill(large, 4000)
+	band := newMockBand(policy.NewState(ctx), small, large)
+
+	picks := map[string]int{}
+	for range 110 {
+		selected, err := policy.Pick(ctx, band)
+		require.NoError(t, err)
+		queue := selected.(*frameworkmocks.MockFlowQueueAccessor)
+		picks[queue.FlowKeyV.ID]++
+		queue.PeekHeadV.Handle().Invalidate()
+		fill(queue, queue.PeekHeadV.OriginalRequest().ByteSize())
+	}
+	assert.Equal(t, 100, picks[flow1Key.ID])
+	assert.Equal(t, 10, picks[flow2Key.ID])
+}
+
+func TestWeightedFairQueuing_Pick_InvalidState(t *testing.T) {
+	t.Parallel()
+	policy := newWeightedFairQueuing("", WeightedFairQueuingParameters{DefaultWeight: 1})
+	band := newMockBand("invalid", backloggedQueue(flow1Key, 1))
+
+	_, err := policy.Pick(context.Background(), band)
+	assert.Error(t, err)
+}
+
+func TestWeightedFairQueuingFairnessPolicyFactory(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name       string
+		jsonParams string
+		expected   WeightedFairQueuingParameters
+		expectErr  bool
+	}{
+		{name: "Defaults", expected: WeightedFairQueuingParameters{DefaultWeight: 1}},
+		{
+			name:       "Weights",
+			jsonParams: `{"weights": {"paid": 3, "free": 1}, "defaultWeight": 0.5}`,
+			expected:   WeightedFairQueuingParameters{Weights: map[string]float64{"paid": 3, "free": 1}, DefaultWeight: 0.5},
+		},
+		{name: "Zero weight", jsonParams: `{"weights": {"paid": 0}}`, expectErr: true},
+		{name: "Negative default weight", jsonParams: `{"defaultWeight": -1}`, expectErr: true},
+		{name: "Invalid JSON", jsonParams: `{"weights": []}`, expectErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			plugin, err := WeightedFairQueuingFairnessPolicyFactory("wfq", json.RawMessage(tc.jsonParams), nil)
+			if tc.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			policy := plugin.(*weightedFairQueuing)
+			assert.Equal(t, "wfq", policy.TypedName().Name)
+			assert.Equal(t, tc.expected, policy.parameters)
+		})
+	}
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"sync"
+	"time"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/window"
+)
+
+const (
+	// dispatchRateWindow is the duration of the sliding window over which the dispatch rate of a priority band is
+	// measured.
+	dispatchRateWindow = 30 * time.Second
+	// dispatchRateBuckets is the number of buckets the dispatch rate window is divided into.
+	dispatchRateBuckets = 10
+)
+
+// dispatchRate measures the recent rate at which items are dispatched from a priority band, over a sliding window.
+type dispatchRate struct {
+	mu         sync.Mutex
+	created    time.Time
+	dispatches *window.Counter
+}
+
+func newDispatchRate(now time.Time) *dispatchRate {
+	return &dispatchRate{created: now, dispatches: window.NewCounter(dispatchRateWindow, dispatchRateBuckets)}
+}
+
+// record counts a dispatch at the given time.
+func (r *dispatchRate) record(now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.dispatches.Add(now, 1)
+}
+
+// perSecond returns the number of dispatches per second over the window ending at the given time. The rate of a band
+// younger than the window is measured over its lifetime instead, so that it is not underestimated after startup.
+func (r *dispatchRate) perSecond(now time.Time) float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count, _ := r.dispatches.Total(now)
+	if count == 0 {
+		return 0
+	}
+	elapsed := min(max(now.Sub(r.created), r.dispatches.BucketWidth()), dispatchRateWindow)
+	return float64(count) / elapsed.Seconds()
+}
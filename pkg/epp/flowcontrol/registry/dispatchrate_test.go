@@ -0,0 +1,44 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDispatchRate(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	rate := newDispatchRate(start)
+	assert.Zero(t, rate.perSecond(start), "A band without dispatches should have no dispatch rate")
+
+	// 10 dispatches per second for the first 10 seconds, measured over the lifetime of the band.
+	for i := range 100 {
+		rate.record(start.Add(time.Duration(i) * 100 * time.Millisecond))
+	}
+	assert.InDelta(t, 10, rate.perSecond(start.Add(10*time.Second)), 0.01)
+
+	assert.InDelta(t, 5, rate.perSecond(start.Add(20*time.Second)), 0.01)
+
+	// Once the band is older than the window, the rate is measured over the window, which the dispatches of the first
+	// 3 seconds slid out of.
+	assert.InDelta(t, 70.0/30, rate.perSecond(start.Add(31*time.Second)), 0.01)
+	assert.Zero(t, rate.perSecond(start.Add(45*time.Second)))
+}
@@ -63,6 +63,9 @@ type managedQueue struct {
 
 	// onStatsDelta is the callback used to propagate statistics changes up to the parent shard.
 	onStatsDelta propagateStatsDeltaFunc
+	// onDispatch is the callback used to count the items removed from this queue for dispatch in the dispatch rate of
+	// the parent band.
+	onDispatch func()
 	// isDraining is a callback that checks the lifecycle state of the parent shard, allowing this queue to reject new
 	// work when the shard is being decommissioned.
 	isDraining func() bool
@@ -94,6 +97,7 @@ func newManagedQueue(
 	key types.FlowKey,
 	logger logr.Logger,
 	onStatsDelta propagateStatsDeltaFunc,
+	onDispatch func(),
 	isDraining func() bool,
 ) *managedQueue {
 	mqLogger := logger.WithName("managed-queue").WithValues(
@@ -105,6 +109,7 @@ func newManagedQueue(
 		policy:       policy,
 		key:          key,
 		onStatsDelta: onStatsDelta,
+		onDispatch:   onDispatch,
 		logger:       mqLogger,
 		isDraining:   isDraining,
 	}
@@ -131,7 +136,8 @@ func (mq *managedQueue) Add(item types.QueueItemAccessor) error {
 	return nil
 }
 
-// Remove wraps the underlying framework.SafeQueue.Remove and updates statistics.
+// Remove wraps the underlying framework.SafeQueue.Remove and updates statistics. Items are only removed by handle to be
+// dispatched, so the removal is counted in the dispatch rate of the parent band.
 func (mq *managedQueue) Remove(handle types.QueueItemHandle) (types.QueueItemAccessor, error) {
 	mq.mu.Lock()
 	defer mq.mu.Unlock()
@@ -141,6 +147,7 @@ func (mq *managedQueue) Remove(handle types.QueueItemHandle) (types.QueueItemAcc
 		return nil, err
 	}
 	mq.propagateStatsDeltaLocked(-1, -int64(removedItem.OriginalRequest().ByteSize()))
+	mq.onDispatch()
 	mq.logger.V(logging.TRACE).Info("Request removed from queue", "requestID", removedItem.OriginalRequest().ID())
 	return removedItem, nil
 }
@@ -42,6 +42,7 @@ type mqTestHarness struct {
 	mq         *managedQueue
 	propagator *mockStatsPropagator
 	mockPolicy *frameworkmocks.MockOrderingPolicy
+	dispatches atomic.Int64
 }
 
 // newMockedMqHarness creates a harness that uses a mocked underlying queue.
@@ -67,16 +68,16 @@ func newMqHarness(t *testing.T, queue framework.SafeQueue, key types.FlowKey, is
 	propagator := &mockStatsPropagator{}
 	mockPolicy := &frameworkmocks.MockOrderingPolicy{}
 
-	isDrainingFunc := func() bool { return isDraining }
-	mq := newManagedQueue(queue, mockPolicy, key, logr.Discard(), propagator.propagate, isDrainingFunc)
-	require.NotNil(t, mq, "Test setup: newManagedQueue must return a valid instance")
-
-	return &mqTestHarness{
+	h := &mqTestHarness{
 		t:          t,
-		mq:         mq,
 		propagator: propagator,
 		mockPolicy: mockPolicy,
 	}
+	isDrainingFunc := func() bool { return isDraining }
+	onDispatch := func() { h.dispatches.Add(1) }
+	h.mq = newManagedQueue(queue, mockPolicy, key, logr.Discard(), propagator.propagate, onDispatch, isDrainingFunc)
+	require.NotNil(t, h.mq, "Test setup: newManagedQueue must return a valid instance")
+	return h
 }
 
 // setupWithItems pre-populates the queue and resets the mock propagator for focused testing.
@@ -186,6 +187,7 @@ func TestManagedQueue_Remove(t *testing.T) {
 		expectErr             bool
 		expectedLenDelta      int64
 		expectedByteSizeDelta int64
+		expectedDispatches    int64
 	}{
 		{
 			name: "ShouldSucceed_AndDecrementStats",
@@ -197,6 +199,7 @@ func TestManagedQueue_Remove(t *testing.T) {
 			expectErr:             false,
 			expectedLenDelta:      -1,
 			expectedByteSizeDelta: -100,
+			expectedDispatches:    1,
 		},
 		{
 			name: "ShouldFail_AndNotChangeStats_WhenUnderlyingQueueFails",
@@ -232,6 +235,8 @@ func TestManagedQueue_Remove(t *testing.T) {
 				"The propagated length delta must exactly match the change in queue size")
 			assert.Equal(t, tc.expectedByteSizeDelta, h.propagator.byteSizeDelta.Load(),
 				"The propagated byte size delta must exactly match the change in queue size")
+			assert.Equal(t, tc.expectedDispatches, h.dispatches.Load(),
+				"A successful removal must be counted as a dispatch")
 		})
 	}
 }
@@ -527,12 +527,27 @@ func (fr *FlowRegistry) Stats() contracts.AggregateStats {
 			CapacityBytes: bandCfg.MaxBytes,
 			ByteSize:      uint64(bandStats.byteSize.Load()),
 			Len:           uint64(bandStats.len.Load()),
+			DispatchRate:  fr.dispatchRate(priority),
 		}
 		return true
 	})
 	return stats
 }
 
+// dispatchRate returns the recent number of items dispatched per second from the band of the given priority, summed
+// over all shards.
+func (fr *FlowRegistry) dispatchRate(priority int) float64 {
+	fr.mu.RLock()
+	allShards := fr.allShards
+	fr.mu.RUnlock()
+
+	var rate float64
+	for _, s := range allShards {
+		rate += s.dispatchRate(priority)
+	}
+	return rate
+}
+
 // ShardStats returns a slice of statistics, one for each internal shard.
 func (fr *FlowRegistry) ShardStats() []contracts.ShardStats {
 	fr.mu.RLock()
@@ -773,7 +788,7 @@ func (fr *FlowRegistry) executeScaleUpLocked(newTotalActive int) error {
 	for i := range numToAdd {
 		shardID := fmt.Sprintf("shard-%04d", fr.nextShardID+uint64(i))
 		partitionedConfig := fr.config.partition(currentActive+i, newTotalActive)
-		newShards[i] = newShard(shardID, partitionedConfig, fr.logger, fr.propagateStatsDelta)
+		newShards[i] = newShard(shardID, partitionedConfig, fr.clock, fr.logger, fr.propagateStatsDelta)
 	}
 
 	// Prepare All Components for All New Shards (Fallible):
@@ -24,6 +24,7 @@ import (
 	"sync/atomic"
 
 	"github.com/go-logr/logr"
+	"k8s.io/utils/clock"
 
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/common/util/logging"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/flowcontrol/contracts"
@@ -58,6 +59,9 @@ type priorityBand struct {
 	// Band-level statistics, updated via lock-free propagation from child queues.
 	byteSize atomic.Int64
 	len      atomic.Int64
+
+	// dispatches measures the recent rate at which items are dispatched from the queues of this band.
+	dispatches *dispatchRate
 }
 
 // registryShard implements the `contracts.RegistryShard` interface.
@@ -82,6 +86,7 @@ type priorityBand struct {
 type registryShard struct {
 	// --- Immutable Identity & Dependencies (set at construction) ---
 	id           string
+	clock        clock.PassiveClock
 	logger       logr.Logger
 	onStatsDelta propagateStatsDeltaFunc
 
@@ -119,12 +124,14 @@ var _ contracts.RegistryShard = &registryShard{}
 func newShard(
 	id string,
 	config *ShardConfig,
+	clock clock.PassiveClock,
 	logger logr.Logger,
 	onStatsDelta propagateStatsDeltaFunc,
 ) *registryShard {
 	shardLogger := logger.WithName("registry-shard").WithValues("shardID", id)
 	s := &registryShard{
 		id:           id,
+		clock:        clock,
 		logger:       shardLogger,
 		config:       config,
 		onStatsDelta: onStatsDelta,
@@ -149,6 +156,7 @@ func (s *registryShard) initPriorityBand(bandConfig *PriorityBandConfig) {
 		queues:         make(map[string]*managedQueue),
 		fairnessPolicy: bandConfig.FairnessPolicy,
 		policyState:    policyState,
+		dispatches:     newDispatchRate(s.clock.Now()),
 	}
 	s.priorityBands.Store(bandConfig.Priority, band)
 	s.orderedPriorityLevels = append(s.orderedPriorityLevels, bandConfig.Priority)
@@ -285,6 +293,7 @@ func (s *registryShard) Stats() contracts.ShardStats {
 			CapacityBytes: band.config.MaxBytes, // This is the partitioned capacity.
 			ByteSize:      uint64(band.byteSize.Load()),
 			Len:           uint64(band.len.Load()),
+			DispatchRate:  band.dispatches.perSecond(s.clock.Now()),
 		}
 		return true
 	})
@@ -319,7 +328,8 @@ func (s *registryShard) synchronizeFlow(
 		return s.isDraining.Load()
 	}
 
-	mq := newManagedQueue(q, policy, key, s.logger, s.propagateStatsDelta, isDrainingFunc)
+	onDispatch := func() { band.dispatches.record(s.clock.Now()) }
+	mq := newManagedQueue(q, policy, key, s.logger, s.propagateStatsDelta, onDispatch, isDrainingFunc)
 	band.queues[key.ID] = mq
 }
 
@@ -374,6 +384,16 @@ func (s *registryShard) propagateStatsDelta(priority int, lenDelta, byteSizeDelt
 	s.onStatsDelta(priority, lenDelta, byteSizeDelta)
 }
 
+// dispatchRate returns the recent number of items dispatched per second from the band of the given priority, or 0 if
+// the band does not exist.
+func (s *registryShard) dispatchRate(priority int) float64 {
+	val, ok := s.priorityBands.Load(priority)
+	if !ok {
+		return 0
+	}
+	return val.(*priorityBand).dispatches.perSecond(s.clock.Now())
+}
+
 // --- `priorityBandAccessor` ---
 
 // priorityBandAccessor implements `framework.PriorityBandAccessor`. It provides a read-only, concurrent-safe view of a
@@ -24,6 +24,7 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"k8s.io/utils/clock"
 
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/flowcontrol/contracts"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/flowcontrol/framework"
@@ -66,7 +67,7 @@ func newShardTestHarness(t *testing.T) *shardTestHarness {
 
 	statsPropagator := &mockStatsPropagator{}
 	shardConfig := globalConfig.partition(0, 1)
-	shard := newShard("test-shard-1", shardConfig, logr.Discard(), statsPropagator.propagate)
+	shard := newShard("test-shard-1", shardConfig, clock.RealClock{}, logr.Discard(), statsPropagator.propagate)
 
 	h := &shardTestHarness{
 		t:                t,
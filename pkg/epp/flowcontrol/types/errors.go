@@ -18,6 +18,7 @@ package types
 
 import (
 	"errors"
+	"time"
 )
 
 // --- High-Level Outcome Errors ---
@@ -60,6 +61,9 @@ var (
 	// `FlowControlRequest.Context()`) was cancelled. This error typically wraps the underlying `context.Canceled` or
 	// `context.DeadlineExceeded` error.
 	ErrContextCancelled = errors.New("request context cancelled")
+
+	// ErrPreempted indicates a request was evicted to make room for a request of higher priority.
+	ErrPreempted = errors.New("request preempted by a higher priority request")
 )
 
 // --- General `controller.FlowController` Errors ---
@@ -72,3 +76,28 @@ var (
 	// before internal queuing) or `ErrEvicted` (if eviction happens after internal queuing).
 	ErrFlowControllerNotRunning = errors.New("flow controller is not running")
 )
+
+// --- Queue State ---
+
+// QueueState is a snapshot of the priority band of a request that was rejected for capacity or evicted on TTL expiry,
+// telling the caller how long to back off before retrying.
+type QueueState struct {
+	// Priority is the priority level of the band.
+	Priority int
+	// PriorityName is the human-readable name of the band.
+	PriorityName string
+	// QueueDepth is the number of items queued in the band.
+	QueueDepth uint64
+	// EstimatedWait is how long the items queued in the band are estimated to take to be dispatched.
+	EstimatedWait time.Duration
+}
+
+// QueueStateError wraps the error of a request rejected or evicted by the `controller.FlowController` with the state of
+// its priority band. Callers should use `errors.As` to retrieve it; the wrapped error is still matched by `errors.Is`.
+type QueueStateError struct {
+	Err   error
+	State QueueState
+}
+
+func (e *QueueStateError) Error() string { return e.Err.Error() }
+func (e *QueueStateError) Unwrap() error { return e.Err }
@@ -60,6 +60,11 @@ const (
 	// `context.DeadlineExceeded` error) (and `ErrEvicted`).
 	QueueOutcomeEvictedContextCancelled
 
+	// QueueOutcomeEvictedPreempted indicates eviction from a queue to make room for a request of higher priority when
+	// the shard was at capacity and preemption is enabled.
+	// The associated error will wrap `ErrPreempted` (and `ErrEvicted`).
+	QueueOutcomeEvictedPreempted
+
 	// QueueOutcomeEvictedOther indicates eviction from a queue for reasons not covered by more specific eviction
 	// outcomes.
 	// The specific underlying cause can be determined from the associated error (e.g., controller shutdown while the item
@@ -82,6 +87,8 @@ func (o QueueOutcome) String() string {
 		return "EvictedTTL"
 	case QueueOutcomeEvictedContextCancelled:
 		return "EvictedContextCancelled"
+	case QueueOutcomeEvictedPreempted:
+		return "EvictedPreempted"
 	case QueueOutcomeEvictedOther:
 		return "EvictedOther"
 	default:
@@ -84,6 +84,14 @@ type FlowControlRequest interface {
 	TargetModelName() string
 }
 
+// TokenEstimatingRequest is an optional extension of `FlowControlRequest` for requests that can estimate the number of
+// tokens they cost to serve. Policies accounting for the cost of requests in tokens (e.g., the weighted fair queuing
+// fairness policy) fall back to an estimate derived from `ByteSize()` for requests that do not implement it.
+type TokenEstimatingRequest interface {
+	// EstimatedTokens returns the estimated number of tokens of the request (e.g., its prompt tokens).
+	EstimatedTokens() uint64
+}
+
 // QueueItemHandle is an opaque handle to an item that has been successfully added to a `framework.SafeQueue`. It acts
 // as a key, allowing the `controller.FlowController` to perform targeted operations (like removal) on a specific item
 // without needing to know the queue's internal structure.
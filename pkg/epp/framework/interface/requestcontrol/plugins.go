@@ -80,5 +80,20 @@ type AdmissionPlugin interface {
 	plugin.Plugin
 	// AdmitRequest returns the denial reason, wrapped as error if the request is denied.
 	// If the request is allowed, it returns nil.
+	// A denial reason of type errutil.Error is returned to the client with its code, e.g. RateLimited for a 429
+	// response, and its retry delay. Other denial reasons are returned as internal errors.
 	AdmitRequest(ctx context.Context, request *types.LLMRequest, pods []types.Endpoint) error
 }
+
+// RateLimiter is called by the director before the admission controller, so that the requests exceeding their budget
+// are rejected before they wait in the Flow Control queues.
+type RateLimiter interface {
+	plugin.Plugin
+	// LimitRequest charges the request to its budget if it is allowed, and returns the denial reason, wrapped as error,
+	// otherwise. A denial reason of type errutil.Error is returned to the client with its code and its retry delay.
+	// Other denial reasons are returned as internal errors.
+	LimitRequest(ctx context.Context, request *types.LLMRequest) error
+	// RequestRejected is called when a request charged by LimitRequest is rejected before it is sent to a model
+	// server, e.g. by the admission controller or the scheduler, so that its charge is refunded.
+	RequestRejected(ctx context.Context, request *types.LLMRequest)
+}
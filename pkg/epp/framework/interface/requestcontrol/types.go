@@ -17,6 +17,8 @@ limitations under the License.
 package requestcontrol
 
 import (
+	"time"
+
 	"google.golang.org/protobuf/types/known/structpb"
 	handlerstypes "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/handlers/types"
 )
@@ -33,12 +35,47 @@ type Response struct {
 	IsStreaming bool
 	// EndOfStream when true indicates that this invocation contains the last chunk of the response
 	EndOfStream bool
+	// Events are the server-sent events completed by the chunk of a streamed response, in the order of the stream.
+	// An event split across chunks is passed with the chunk completing it.
+	Events []handlerstypes.StreamEvent
+	// Content is the text generated in the chunk of a streamed response, the deltas of its events.
+	Content string
+	// ChunkTokens is the number of tokens generated in the chunk of a streamed response, counting one token per
+	// event carrying generated text.
+	ChunkTokens int
+	// OutputTokens is the number of tokens generated so far in a streamed response, including those of the chunk.
+	// It is the completion tokens of the usage reported by the model server when there are any, e.g. when the request
+	// sets "stream_options": {"include_usage": true}, and is otherwise estimated like ChunkTokens.
+	OutputTokens int
+	// OutputTokensEstimated is true when OutputTokens is estimated rather than reported by the model server.
+	OutputTokensEstimated bool
+	// ReceivedTimestamp is when the EPP received the chunk of a streamed response.
+	ReceivedTimestamp time.Time
+	// FirstTokenTimestamp is when the EPP received the first chunk of a streamed response carrying generated text.
+	// It is zero until then.
+	FirstTokenTimestamp time.Time
+	// InterTokenLatency is the time per token between the chunk of a streamed response and the previous chunk
+	// carrying generated text. It is zero for the chunk carrying the first token and for chunks carrying no generated
+	// text.
+	InterTokenLatency time.Duration
 	// ReqMetadata is a map of metadata that can be passed from Envoy.
 	// It is populated with Envoy's dynamic metadata when ext_proc is processing ProcessingRequest_ResponseHeaders.
 	// Currently, this is only used by conformance test.
 	ReqMetadata map[string]any
 	// Token usage counts parsed from the response body.
 	Usage handlerstypes.Usage
+	// ResponseID is the id of the response parsed from the response body, e.g. the Responses API response id.
+	ResponseID string
+	// ErrorCode is the canonical code of the error the model server responded with, e.g. ContextLengthExceeded or
+	// ModelServerOutOfMemory, empty if it succeeded. It is only set for ResponseComplete plugins.
+	ErrorCode string
+	// Incomplete is true when the request terminated before the response of the model server was fully received, e.g.
+	// because the connection to the model server was reset or the client disconnected. It is only set for
+	// ResponseComplete plugins.
+	Incomplete bool
+	// ClientCancelled is true when the request terminated because the client disconnected, so that an incomplete
+	// response is not the fault of the model server. It is only set for ResponseComplete plugins.
+	ClientCancelled bool
 	// DynamicMetadata is a map of metadata that can be passed to the Envoy. It is populated into the dynamic
 	// metadata when processing ProcessingResponse_RequestHeaders.
 	DynamicMetadata *structpb.Struct
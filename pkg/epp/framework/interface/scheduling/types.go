@@ -39,6 +39,14 @@ type LLMRequest struct {
 	Headers map[string]string
 }
 
+// Kind returns the kind of the request, derived from its body.
+func (r *LLMRequest) Kind() RequestKind {
+	if r == nil {
+		return RequestKindUnknown
+	}
+	return r.Body.Kind()
+}
+
 func (r *LLMRequest) String() string {
 	if r == nil {
 		return nilString
@@ -48,26 +56,190 @@ func (r *LLMRequest) String() string {
 		r.RequestId, r.TargetModel, r.Body, r.Headers)
 }
 
+// RequestKind identifies the API of a request.
+type RequestKind string
+
+const (
+	RequestKindUnknown         RequestKind = ""
+	RequestKindCompletions     RequestKind = "completions"
+	RequestKindChatCompletions RequestKind = "chat_completions"
+	RequestKindResponses       RequestKind = "responses"
+	RequestKindEmbeddings      RequestKind = "embeddings"
+	RequestKindRerank          RequestKind = "rerank"
+	// RequestKindAnthropicMessages is the Anthropic Messages API.
+	RequestKindAnthropicMessages RequestKind = "anthropic_messages"
+	// RequestKindOpenInference is the Open Inference Protocol (KServe v2) infer endpoint and its generate extension.
+	RequestKindOpenInference RequestKind = "open_inference"
+)
+
+// IsPrefillOnly returns true for the kinds of requests that don't generate tokens, i.e. whose cost is the
+// prefill of their input only.
+func (k RequestKind) IsPrefillOnly() bool {
+	return k == RequestKindEmbeddings || k == RequestKindRerank
+}
+
 // LLMRequestBody contains the request-body fields that we parse out as user input,
 // to be used in forming scheduling decisions.
-// An LLMRequestBody must contain exactly one of CompletionsRequest or ChatCompletionsRequest.
+// An LLMRequestBody must contain exactly one of CompletionsRequest, ChatCompletionsRequest, ResponsesRequest,
+// EmbeddingsRequest, RerankRequest, AnthropicMessagesRequest or OpenInferenceRequest.
 type LLMRequestBody struct {
 	// CompletionsRequest is the representation of the OpenAI /v1/completions request body.
 	Completions *CompletionsRequest `json:"completions,omitempty"`
 	// ChatCompletionsRequest is the representation of the OpenAI /v1/chat/completions request body.
 	ChatCompletions *ChatCompletionsRequest `json:"chat_completions,omitempty"`
+	// ResponsesRequest is the representation of the OpenAI /v1/responses request body.
+	Responses *ResponsesRequest `json:"responses,omitempty"`
+	// EmbeddingsRequest is the representation of the OpenAI /v1/embeddings request body.
+	Embeddings *EmbeddingsRequest `json:"embeddings,omitempty"`
+	// RerankRequest is the representation of the /v1/rerank request body.
+	Rerank *RerankRequest `json:"rerank,omitempty"`
+	// AnthropicMessagesRequest is the representation of the Anthropic /v1/messages request body.
+	AnthropicMessages *AnthropicMessagesRequest `json:"anthropic_messages,omitempty"`
+	// OpenInferenceRequest is the representation of the Open Inference Protocol (KServe v2)
+	// /v2/models/{name}/infer and /v2/models/{name}/generate request bodies.
+	OpenInference *OpenInferenceRequest `json:"open_inference,omitempty"`
+}
+
+// Kind returns the kind of the request body.
+func (r *LLMRequestBody) Kind() RequestKind {
+	switch {
+	case r == nil:
+		return RequestKindUnknown
+	case r.Completions != nil:
+		return RequestKindCompletions
+	case r.ChatCompletions != nil:
+		return RequestKindChatCompletions
+	case r.Responses != nil:
+		return RequestKindResponses
+	case r.Embeddings != nil:
+		return RequestKindEmbeddings
+	case r.Rerank != nil:
+		return RequestKindRerank
+	case r.AnthropicMessages != nil:
+		return RequestKindAnthropicMessages
+	case r.OpenInference != nil:
+		return RequestKindOpenInference
+	}
+	return RequestKindUnknown
 }
 
 func (r *LLMRequestBody) CacheSalt() string {
-	if r.ChatCompletions == nil && r.Completions == nil {
+	switch {
+	case r.ChatCompletions != nil:
+		return r.ChatCompletions.CacheSalt
+	case r.Completions != nil:
+		return r.Completions.CacheSalt
+	case r.Responses != nil:
+		return r.Responses.CacheSalt
+	}
+	return ""
+}
+
+// User returns the end-user identifier sent in the OpenAI `user` field, if any.
+func (r *LLMRequestBody) User() string {
+	switch {
+	case r.ChatCompletions != nil:
+		return r.ChatCompletions.User
+	case r.Completions != nil:
+		return r.Completions.User
+	case r.Responses != nil:
+		return r.Responses.User
+	case r.Embeddings != nil:
+		return r.Embeddings.User
+	case r.AnthropicMessages != nil:
+		return r.AnthropicMessages.Metadata.UserID
+	}
+	return ""
+}
+
+// PlainText returns the text of the prompt, the chat messages or the responses input, used to estimate the
+// number of prompt tokens when the request is not tokenized.
+func (r *LLMRequestBody) PlainText() string {
+	switch {
+	case r == nil:
 		return ""
+	case r.Completions != nil:
+		return r.Completions.Prompt.PlainText()
+	case r.ChatCompletions != nil:
+		var sb strings.Builder
+		for _, msg := range r.ChatCompletions.Messages {
+			sb.WriteString(msg.Content.PlainText())
+		}
+		return sb.String()
+	case r.Responses != nil:
+		return r.Responses.PlainText()
+	case r.Embeddings != nil:
+		return r.Embeddings.Input.PlainText()
+	case r.Rerank != nil:
+		return r.Rerank.PlainText()
+	case r.AnthropicMessages != nil:
+		var sb strings.Builder
+		sb.WriteString(r.AnthropicMessages.System.PlainText())
+		for _, msg := range r.AnthropicMessages.Messages {
+			sb.WriteString(msg.Content.PlainText())
+		}
+		return sb.String()
+	case r.OpenInference != nil:
+		return r.OpenInference.PlainText()
 	}
+	return ""
+}
 
-	if r.ChatCompletions != nil {
-		return r.ChatCompletions.CacheSalt
+// TokenCount returns the exact number of prompt tokens of a request sent with token IDs, or 0 if the request is
+// not tokenized.
+func (r *LLMRequestBody) TokenCount() int {
+	switch {
+	case r == nil:
+		return 0
+	case r.Completions != nil:
+		return r.Completions.Prompt.TokenCount()
+	case r.Embeddings != nil:
+		return r.Embeddings.Input.TokenCount()
 	}
+	return 0
+}
 
-	return r.Completions.CacheSalt
+// averageCharactersPerToken is the number of characters of the plain text of a request per token, used to estimate its
+// prompt tokens without a tokenizer.
+const averageCharactersPerToken = 4
+
+// EstimatedPromptTokens returns the number of prompt tokens of the request: exact if it is sent as token IDs, and
+// otherwise estimated from the length of its plain text.
+func (r *LLMRequestBody) EstimatedPromptTokens() int {
+	if tokens := r.TokenCount(); tokens > 0 {
+		return tokens
+	}
+	return len(r.PlainText()) / averageCharactersPerToken
+}
+
+// MaxOutputTokens returns the maximum number of tokens the request asks the model to generate, or 0 if the request
+// does not set one.
+func (r *LLMRequestBody) MaxOutputTokens() int {
+	switch {
+	case r == nil:
+		return 0
+	case r.Completions != nil:
+		return r.Completions.MaxTokens
+	case r.ChatCompletions != nil:
+		// max_tokens is deprecated in favor of max_completion_tokens, which takes precedence when both are set.
+		if r.ChatCompletions.MaxCompletionTokens > 0 {
+			return r.ChatCompletions.MaxCompletionTokens
+		}
+		return r.ChatCompletions.MaxTokens
+	case r.Responses != nil:
+		return r.Responses.MaxOutputTokens
+	case r.AnthropicMessages != nil:
+		return r.AnthropicMessages.MaxTokens
+	}
+	return 0
+}
+
+// PreviousResponseID returns the response that a Responses API request continues, if any.
+func (r *LLMRequestBody) PreviousResponseID() string {
+	if r.Responses != nil {
+		return r.Responses.PreviousResponseID
+	}
+	return ""
 }
 
 // CompletionsRequest is a structured representation of the fields we parse out of the /v1/completions request
@@ -75,10 +247,14 @@ func (r *LLMRequestBody) CacheSalt() string {
 // This struct includes fields usable for plugins and scheduling decisions - and not the entire
 // API spec.
 type CompletionsRequest struct {
-	// Prompt is the prompt that was sent in the request body.
-	Prompt string `json:"prompt,omitempty"`
+	// Prompt is the prompt that was sent in the request body, as text or as token IDs.
+	Prompt Prompt `json:"prompt"`
+	// MaxTokens is the maximum number of tokens to generate.
+	MaxTokens int `json:"max_tokens,omitempty"`
 	// CacheSalt is an optional request parameter to isolate prefix caches for security reasons.
 	CacheSalt string `json:"cache_salt,omitempty"`
+	// User is an optional identifier of the end-user sending the request.
+	User string `json:"user,omitempty"`
 }
 
 func (r *CompletionsRequest) String() string {
@@ -86,7 +262,7 @@ func (r *CompletionsRequest) String() string {
 		return nilString
 	}
 
-	return fmt.Sprintf("{PromptLength: %d}", len(r.Prompt))
+	return fmt.Sprintf("{PromptLength: %d, PromptTokens: %d}", len(r.Prompt.PlainText()), r.Prompt.TokenCount())
 }
 
 // ChatCompletionsRequest is a structured representation of the fields we parse out of the v1/chat/completions
@@ -104,8 +280,14 @@ type ChatCompletionsRequest struct {
 	ContinueFinalMessage      bool                   `json:"continue_final_message,omitempty"`
 	AddGenerationPrompt       bool                   `json:"add_generation_prompt,omitempty"`
 	ChatTemplateKWArgs        map[string]interface{} `json:"chat_template_kwargs,omitempty"`
+	// MaxTokens is the deprecated maximum number of tokens to generate, superseded by MaxCompletionTokens.
+	MaxTokens int `json:"max_tokens,omitempty"`
+	// MaxCompletionTokens is the maximum number of tokens to generate, including reasoning tokens.
+	MaxCompletionTokens int `json:"max_completion_tokens,omitempty"`
 	// CacheSalt is an optional request parameter to isolate prefix caches for security reasons.
 	CacheSalt string `json:"cache_salt,omitempty"`
+	// User is an optional identifier of the end-user sending the request.
+	User string `json:"user,omitempty"`
 }
 
 func (r *ChatCompletionsRequest) String() string {
@@ -120,12 +302,442 @@ func (r *ChatCompletionsRequest) String() string {
 	return fmt.Sprintf("{MessagesLength: %d}", messagesLen)
 }
 
+// AnthropicMessagesRequest is a structured representation of the fields we parse out of the Anthropic /v1/messages
+// request body. For detailed body fields, please refer to https://docs.anthropic.com/en/api/messages.
+// This struct includes fields usable for plugins and scheduling decisions - and not the entire
+// API spec.
+type AnthropicMessagesRequest struct {
+	// System is the system prompt, which is a top-level field rather than a message.
+	System Content `json:"system,omitempty"`
+	// Messages are the conversation messages, whose content blocks are typed text, image, tool_use and tool_result.
+	Messages  []Message     `json:"messages,omitempty"`
+	Tools     []interface{} `json:"tools,omitempty"`
+	MaxTokens int           `json:"max_tokens,omitempty"`
+	// Metadata holds the optional identifier of the end-user sending the request.
+	Metadata AnthropicMetadata `json:"metadata,omitempty"`
+}
+
+// AnthropicMetadata is the metadata of an Anthropic Messages API request.
+type AnthropicMetadata struct {
+	UserID string `json:"user_id,omitempty"`
+}
+
+func (r *AnthropicMessagesRequest) String() string {
+	if r == nil {
+		return nilString
+	}
+
+	messagesLen := len(r.System.PlainText())
+	for _, msg := range r.Messages {
+		messagesLen += len(msg.Content.PlainText())
+	}
+	return fmt.Sprintf("{MessagesLength: %d}", messagesLen)
+}
+
+// ResponsesRequest is a structured representation of the fields we parse out of the /v1/responses request
+// body. For detailed body fields, please refer to https://platform.openai.com/docs/api-reference/responses.
+// This struct includes fields usable for plugins and scheduling decisions - and not the entire
+// API spec.
+type ResponsesRequest struct {
+	// Input is the text or the list of input items sent to the model.
+	Input ResponsesInput `json:"input,omitempty"`
+	// Instructions is the system (or developer) message inserted into the model's context.
+	Instructions string        `json:"instructions,omitempty"`
+	Tools        []interface{} `json:"tools,omitempty"`
+	// PreviousResponseID is the response that this request continues. The conversation state of that response
+	// is held by the model server that generated it.
+	PreviousResponseID string `json:"previous_response_id,omitempty"`
+	// MaxOutputTokens is the maximum number of tokens to generate, including reasoning tokens.
+	MaxOutputTokens int `json:"max_output_tokens,omitempty"`
+	// CacheSalt is an optional request parameter to isolate prefix caches for security reasons.
+	CacheSalt string `json:"cache_salt,omitempty"`
+	// User is an optional identifier of the end-user sending the request.
+	User string `json:"user,omitempty"`
+}
+
+func (r *ResponsesRequest) String() string {
+	if r == nil {
+		return nilString
+	}
+
+	return fmt.Sprintf("{InputLength: %d, PreviousResponseID: %s}", len(r.PlainText()), r.PreviousResponseID)
+}
+
+// PlainText returns the text of the instructions followed by the text of the input.
+func (r *ResponsesRequest) PlainText() string {
+	if r.Instructions == "" {
+		return r.Input.PlainText()
+	}
+	return r.Instructions + " " + r.Input.PlainText()
+}
+
+// ResponsesInput is the input of a Responses API request, either a text or a list of input items.
+type ResponsesInput struct {
+	Raw   string
+	Items []ResponsesInputItem
+}
+
+// ResponsesInputItem is a single input item of a Responses API request, e.g. a message, a function call
+// or the output of a function call.
+type ResponsesInputItem struct {
+	// Type is the item type. Messages may omit it.
+	Type string `json:"type,omitempty"`
+	// Role is the message role, optional values are 'user', 'assistant', 'system' and 'developer'.
+	Role string `json:"role,omitempty"`
+	// Content is the content of a message item.
+	Content ResponsesContent `json:"content,omitempty"`
+	// CallID, Name and Arguments describe a function call item; CallID and Output describe its output.
+	CallID    string `json:"call_id,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Arguments string `json:"arguments,omitempty"`
+	Output    string `json:"output,omitempty"`
+}
+
+// ResponsesContent is the content of a Responses API message, either a text or a list of content parts.
+type ResponsesContent struct {
+	Raw   string
+	Parts []ResponsesContentPart
+}
+
+// ResponsesContentPart is a single content part of a Responses API message.
+type ResponsesContentPart struct {
+	// Type is the part type, e.g. 'input_text', 'output_text', 'input_image' or 'input_file'.
+	Type     string `json:"type"`
+	Text     string `json:"text,omitempty"`
+	ImageURL string `json:"image_url,omitempty"`
+	FileID   string `json:"file_id,omitempty"`
+	FileData string `json:"file_data,omitempty"`
+}
+
+// UnmarshalJSON allow use both format
+func (in *ResponsesInput) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err == nil {
+		in.Raw = str
+		return nil
+	}
+
+	var items []ResponsesInputItem
+	if err := json.Unmarshal(data, &items); err == nil {
+		in.Items = items
+		return nil
+	}
+
+	return errors.New("input format not supported")
+}
+
+func (in ResponsesInput) MarshalJSON() ([]byte, error) {
+	if in.Raw != "" {
+		return json.Marshal(in.Raw)
+	}
+	if in.Items != nil {
+		return json.Marshal(in.Items)
+	}
+	return json.Marshal("")
+}
+
+// IsEmpty returns true if the input has neither text nor items.
+func (in ResponsesInput) IsEmpty() bool {
+	return in.Raw == "" && len(in.Items) == 0
+}
+
+// PlainText returns the text of the input, separating the text of the input items with spaces.
+func (in ResponsesInput) PlainText() string {
+	if in.Raw != "" {
+		return in.Raw
+	}
+	var sb strings.Builder
+	for _, item := range in.Items {
+		for _, text := range []string{item.Content.PlainText(), item.Arguments, item.Output} {
+			if text != "" {
+				sb.WriteString(text)
+				sb.WriteString(" ")
+			}
+		}
+	}
+	return sb.String()
+}
+
+// UnmarshalJSON allow use both format
+func (rc *ResponsesContent) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err == nil {
+		rc.Raw = str
+		return nil
+	}
+
+	var parts []ResponsesContentPart
+	if err := json.Unmarshal(data, &parts); err == nil {
+		rc.Parts = parts
+		return nil
+	}
+
+	return errors.New("content format not supported")
+}
+
+func (rc ResponsesContent) MarshalJSON() ([]byte, error) {
+	if rc.Raw != "" {
+		return json.Marshal(rc.Raw)
+	}
+	if rc.Parts != nil {
+		return json.Marshal(rc.Parts)
+	}
+	return json.Marshal("")
+}
+
+// PlainText returns the text of the content, separating the text parts with spaces.
+func (rc ResponsesContent) PlainText() string {
+	if rc.Raw != "" {
+		return rc.Raw
+	}
+	var sb strings.Builder
+	for _, part := range rc.Parts {
+		if part.Text != "" {
+			sb.WriteString(part.Text)
+			sb.WriteString(" ")
+		}
+	}
+	return sb.String()
+}
+
+// EmbeddingsRequest is a structured representation of the fields we parse out of the /v1/embeddings request
+// body. For detailed body fields, please refer to https://platform.openai.com/docs/api-reference/embeddings.
+// This struct includes fields usable for plugins and scheduling decisions - and not the entire
+// API spec.
+type EmbeddingsRequest struct {
+	// Input is the text or the tokens to embed.
+	Input Prompt `json:"input"`
+	// User is an optional identifier of the end-user sending the request.
+	User string `json:"user,omitempty"`
+}
+
+func (r *EmbeddingsRequest) String() string {
+	if r == nil {
+		return nilString
+	}
+
+	return fmt.Sprintf("{InputLength: %d, InputTokens: %d}", len(r.Input.PlainText()), r.Input.TokenCount())
+}
+
+// RerankRequest is a structured representation of the fields we parse out of the /v1/rerank request body,
+// as served by vLLM and compatible with the Jina and Cohere rerank APIs.
+// This struct includes fields usable for plugins and scheduling decisions - and not the entire
+// API spec.
+type RerankRequest struct {
+	// Query is the query the documents are ranked against.
+	Query string `json:"query"`
+	// Documents are the documents to rank.
+	Documents []RerankDocument `json:"documents"`
+}
+
+func (r *RerankRequest) String() string {
+	if r == nil {
+		return nilString
+	}
+
+	return fmt.Sprintf("{QueryLength: %d, Documents: %d}", len(r.Query), len(r.Documents))
+}
+
+// PlainText returns the text of the query followed by the text of the documents.
+func (r *RerankRequest) PlainText() string {
+	var sb strings.Builder
+	sb.WriteString(r.Query)
+	for _, document := range r.Documents {
+		sb.WriteString(" ")
+		sb.WriteString(document.Text)
+	}
+	return sb.String()
+}
+
+// RerankDocument is a document of a rerank request, sent either as a string or as an object with a text field.
+type RerankDocument struct {
+	Text string `json:"text"`
+}
+
+// UnmarshalJSON allow use both format
+func (d *RerankDocument) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err == nil {
+		d.Text = str
+		return nil
+	}
+
+	var object struct {
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(data, &object); err == nil {
+		d.Text = object.Text
+		return nil
+	}
+
+	return errors.New("document format not supported")
+}
+
+// OpenInferenceRequest is a structured representation of the fields we parse out of the Open Inference Protocol
+// (KServe v2) request bodies: the /v2/models/{name}/infer request, whose inputs are tensors, and the
+// /v2/models/{name}/generate request of the generate extension used by Triton and TensorRT-LLM, whose inputs are
+// top-level fields. For detailed body fields, please refer to
+// https://github.com/kserve/open-inference-protocol/blob/main/specification/protocol/inference_rest.md.
+// The model name is carried by the request path, not by the body.
+// This struct includes fields usable for plugins and scheduling decisions - and not the entire
+// API spec.
+type OpenInferenceRequest struct {
+	// ID is the optional identifier of an infer request.
+	ID string `json:"id,omitempty"`
+	// Inputs are the input tensors of an infer request.
+	Inputs []InferTensor `json:"inputs,omitempty"`
+	// TextInput is the text input of a generate request.
+	TextInput string `json:"text_input,omitempty"`
+}
+
+func (r *OpenInferenceRequest) String() string {
+	if r == nil {
+		return nilString
+	}
+
+	return fmt.Sprintf("{ID: %s, Inputs: %d, TextInputLength: %d}", r.ID, len(r.Inputs), len(r.TextInput))
+}
+
+// IsEmpty returns true if the request has neither input tensors nor a text input.
+func (r *OpenInferenceRequest) IsEmpty() bool {
+	return len(r.Inputs) == 0 && r.TextInput == ""
+}
+
+// PlainText returns the text input of a generate request, followed by the strings of the BYTES input tensors of an
+// infer request.
+func (r *OpenInferenceRequest) PlainText() string {
+	var sb strings.Builder
+	sb.WriteString(r.TextInput)
+	for _, input := range r.Inputs {
+		if input.Datatype != InferDatatypeBytes {
+			continue
+		}
+		for _, text := range input.Strings() {
+			sb.WriteString(text)
+		}
+	}
+	return sb.String()
+}
+
+// InferDatatypeBytes is the datatype of the Open Inference Protocol tensors holding strings.
+const InferDatatypeBytes = "BYTES"
+
+// InferTensor is an input tensor of an Open Inference Protocol infer request.
+type InferTensor struct {
+	// Name is the name of the model input the tensor is bound to, e.g. text_input.
+	Name string `json:"name"`
+	// Shape is the shape of the tensor.
+	Shape []int64 `json:"shape"`
+	// Datatype is the type of the tensor elements, e.g. BYTES, INT32 or FP32.
+	Datatype string `json:"datatype"`
+	// Data holds the elements of the tensor, either flattened or nested according to the shape.
+	Data any `json:"data"`
+}
+
+// Strings returns the string elements of the tensor data, in row-major order.
+func (t *InferTensor) Strings() []string {
+	var texts []string
+	var collect func(data any)
+	collect = func(data any) {
+		switch value := data.(type) {
+		case string:
+			texts = append(texts, value)
+		case []any:
+			for _, element := range value {
+				collect(element)
+			}
+		}
+	}
+	collect(t.Data)
+	return texts
+}
+
+// Prompt is a text or tokenized input, sent as a string, an array of strings, an array of token IDs or an
+// array of token ID arrays.
+type Prompt struct {
+	// Raw is the input sent as a string.
+	Raw string
+	// Texts is the input sent as an array of strings.
+	Texts []string
+	// Tokens is the input sent as token IDs. An array of token IDs is stored as a single element.
+	Tokens [][]uint32
+}
+
+// UnmarshalJSON allow use all the formats
+func (p *Prompt) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err == nil {
+		p.Raw = str
+		return nil
+	}
+
+	var texts []string
+	if err := json.Unmarshal(data, &texts); err == nil {
+		p.Texts = texts
+		return nil
+	}
+
+	var tokens []uint32
+	if err := json.Unmarshal(data, &tokens); err == nil {
+		p.Tokens = [][]uint32{tokens}
+		return nil
+	}
+
+	var batch [][]uint32
+	if err := json.Unmarshal(data, &batch); err == nil {
+		p.Tokens = batch
+		return nil
+	}
+
+	return errors.New("prompt format not supported")
+}
+
+func (p Prompt) MarshalJSON() ([]byte, error) {
+	switch {
+	case p.Raw != "":
+		return json.Marshal(p.Raw)
+	case p.Texts != nil:
+		return json.Marshal(p.Texts)
+	case len(p.Tokens) == 1:
+		return json.Marshal(p.Tokens[0])
+	case p.Tokens != nil:
+		return json.Marshal(p.Tokens)
+	}
+	return json.Marshal("")
+}
+
+// IsEmpty returns true if the prompt has neither text nor tokens.
+func (p Prompt) IsEmpty() bool {
+	return p.Raw == "" && len(p.Texts) == 0 && len(p.Tokens) == 0
+}
+
+// PlainText returns the text of the prompt, separating multiple texts with spaces. Tokenized prompts have no text.
+func (p Prompt) PlainText() string {
+	if p.Raw != "" {
+		return p.Raw
+	}
+	return strings.Join(p.Texts, " ")
+}
+
+// TokenCount returns the number of token IDs of a tokenized prompt, or 0 if the prompt is not tokenized.
+func (p Prompt) TokenCount() int {
+	count := 0
+	for _, tokens := range p.Tokens {
+		count += len(tokens)
+	}
+	return count
+}
+
 // Message represents a single message in a chat-completions request.
 type Message struct {
 	// Role is the message Role, optional values are 'user', 'assistant', ...
 	Role string `json:"role,omitempty"`
 	// Content defines text of this message
 	Content Content `json:"content,omitempty"`
+	// ToolCalls are the tool calls generated by the model, in assistant messages.
+	ToolCalls []interface{} `json:"tool_calls,omitempty"`
+	// ToolCallID is the tool call this message responds to, in tool messages.
+	ToolCallID string `json:"tool_call_id,omitempty"`
 }
 
 type Content struct {
@@ -133,12 +745,31 @@ type Content struct {
 	Structured []ContentBlock
 }
 
+// ContentBlock is a block of structured content, in the OpenAI or in the Anthropic format.
 type ContentBlock struct {
 	Type       string     `json:"type"`
 	Text       string     `json:"text,omitempty"`
 	ImageURL   ImageBlock `json:"image_url,omitempty"`
 	InputAudio AudioBlock `json:"input_audio,omitempty"`
 	VideoURL   VideoBlock `json:"video_url,omitempty"`
+	/* fields of the Anthropic content blocks */
+	// Source is the source of an image or document block.
+	Source *MediaSource `json:"source,omitempty"`
+	// ID, Name and Input describe a tool_use block.
+	ID    string      `json:"id,omitempty"`
+	Name  string      `json:"name,omitempty"`
+	Input interface{} `json:"input,omitempty"`
+	// ToolUseID and Content describe a tool_result block.
+	ToolUseID string   `json:"tool_use_id,omitempty"`
+	Content   *Content `json:"content,omitempty"`
+}
+
+// MediaSource is the source of an Anthropic image or document block, either inline base64 data or a URL.
+type MediaSource struct {
+	Type      string `json:"type,omitempty"`
+	MediaType string `json:"media_type,omitempty"`
+	Data      string `json:"data,omitempty"`
+	Url       string `json:"url,omitempty"`
 }
 
 type ImageBlock struct {
@@ -189,9 +820,12 @@ func (mc Content) PlainText() string {
 	}
 	var sb strings.Builder
 	for _, block := range mc.Structured {
-		if block.Type == "text" {
+		switch {
+		case block.Type == "text":
 			sb.WriteString(block.Text)
 			sb.WriteString(" ")
+		case block.Content != nil: // the result of an Anthropic tool_result block
+			sb.WriteString(block.Content.PlainText())
 		}
 	}
 	return sb.String()
@@ -20,6 +20,7 @@ import (
 	"context"
 	"encoding/json"
 	"maps"
+	"math"
 	"strconv"
 	"time"
 
@@ -38,11 +39,6 @@ import (
 )
 
 const (
-	// defaultFairnessID is the default fairness ID used when no ID is provided in the request.
-	// This ensures that requests without explicit fairness identifiers are still grouped and managed by the Flow Control
-	// system.
-	defaultFairnessID = "default-flow"
-
 	// workloadContextHeaderKey is the header name for workload context information
 	workloadContextHeaderKey = "x-workload-context"
 
@@ -77,6 +73,7 @@ func (s *StreamingServer) HandleRequestHeaders(ctx context.Context, reqCtx *Requ
 
 	for _, header := range req.RequestHeaders.Headers.Headers {
 		reqCtx.Request.Headers[header.Key] = request.GetHeaderValue(header)
+		reqCtx.clientHeaders = append(reqCtx.clientHeaders, header.Key)
 		switch header.Key {
 		case metadata.FlowFairnessIDKey:
 			reqCtx.FairnessID = reqCtx.Request.Headers[header.Key]
@@ -84,11 +81,15 @@ func (s *StreamingServer) HandleRequestHeaders(ctx context.Context, reqCtx *Requ
 			reqCtx.ObjectiveKey = reqCtx.Request.Headers[header.Key]
 		case metadata.ModelNameRewriteKey:
 			reqCtx.TargetModelName = reqCtx.Request.Headers[header.Key]
+		case metadata.RequestTTLKey:
+			reqCtx.RequestTTL = parseRequestTTL(reqCtx.Request.Headers[header.Key])
 		}
 	}
 
+	// This ensures that requests without explicit fairness identifiers are still grouped and managed by the Flow Control
+	// system.
 	if reqCtx.FairnessID == "" {
-		reqCtx.FairnessID = defaultFairnessID
+		reqCtx.FairnessID = metadata.DefaultFairnessID
 	}
 
 	// Extract and parse workload context from X-Workload-Context header
@@ -136,6 +137,22 @@ func extractWorkloadContext(ctx context.Context, headers map[string]string) *dat
 	return &workloadCtx
 }
 
+// parseRequestTTL parses the value of the request TTL header, either a duration (e.g. 30s) or a number of seconds.
+// Invalid and non-positive values are ignored and return zero, leaving the TTL to the InferenceObjective.
+func parseRequestTTL(value string) time.Duration {
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		if seconds <= 0 || seconds > math.MaxInt64/float64(time.Second) {
+			return 0
+		}
+		return time.Duration(seconds * float64(time.Second))
+	}
+	ttl, err := time.ParseDuration(value)
+	if err != nil || ttl <= 0 {
+		return 0
+	}
+	return ttl
+}
+
 func (s *StreamingServer) generateRequestBodyResponses(requestBodyBytes []byte) []*extProcPb.ProcessingResponse {
 	commonResponses := common.BuildChunkedBodyResponses(requestBodyBytes, true)
 	responses := []*extProcPb.ProcessingResponse{}
@@ -170,7 +187,8 @@ func (s *StreamingServer) generateRequestHeaderResponse(ctx context.Context, req
 				Response: &extProcPb.CommonResponse{
 					ClearRouteCache: true,
 					HeaderMutation: &extProcPb.HeaderMutation{
-						SetHeaders: s.generateHeaders(ctx, reqCtx),
+						SetHeaders:    s.generateHeaders(ctx, reqCtx),
+						RemoveHeaders: generateRemovedHeaders(reqCtx),
 					},
 				},
 			},
@@ -228,6 +246,19 @@ func (s *StreamingServer) generateHeaders(ctx context.Context, reqCtx *RequestCo
 	return headers
 }
 
+// generateRemovedHeaders returns the non-system-owned client headers that plugins deleted from the request headers,
+// such that the proxy does not forward them to the model server.
+func generateRemovedHeaders(reqCtx *RequestContext) []string {
+	var removed []string
+	for _, key := range reqCtx.clientHeaders {
+		if _, ok := reqCtx.Request.Headers[key]; ok || request.IsSystemOwnedHeader(key) {
+			continue
+		}
+		removed = append(removed, key)
+	}
+	return removed
+}
+
 func (s *StreamingServer) generateMetadata(endpoint string) *structpb.Struct {
 	return &structpb.Struct{
 		Fields: map[string]*structpb.Value{
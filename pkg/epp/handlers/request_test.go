@@ -20,12 +20,15 @@ import (
 	"context"
 	"strings"
 	"testing"
+	"time"
 
 	configPb "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	extProcPb "github.com/envoyproxy/go-control-plane/envoy/service/ext_proc/v3"
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/protobuf/types/known/structpb"
+	schedulingtypes "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/scheduling"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metadata"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/profile"
 )
 
 func TestHandleRequestHeaders(t *testing.T) {
@@ -36,6 +39,7 @@ func TestHandleRequestHeaders(t *testing.T) {
 		headers        []*configPb.HeaderValue
 		wantHeaders    map[string]string
 		wantFairnessID string
+		wantRequestTTL time.Duration
 	}{
 		{
 			name: "Extracts Fairness ID and Removes Header",
@@ -53,6 +57,29 @@ func TestHandleRequestHeaders(t *testing.T) {
 			},
 			wantFairnessID: "binary-id",
 		},
+		{
+			name: "Parses Request TTL as a Duration",
+			headers: []*configPb.HeaderValue{
+				{Key: metadata.RequestTTLKey, Value: "1m30s"},
+			},
+			wantFairnessID: metadata.DefaultFairnessID,
+			wantRequestTTL: 90 * time.Second,
+		},
+		{
+			name: "Parses Request TTL as Seconds",
+			headers: []*configPb.HeaderValue{
+				{Key: metadata.RequestTTLKey, Value: "2.5"},
+			},
+			wantFairnessID: metadata.DefaultFairnessID,
+			wantRequestTTL: 2500 * time.Millisecond,
+		},
+		{
+			name: "Ignores Invalid Request TTL",
+			headers: []*configPb.HeaderValue{
+				{Key: metadata.RequestTTLKey, Value: "-5s"},
+			},
+			wantFairnessID: metadata.DefaultFairnessID,
+		},
 	}
 
 	for _, tc := range tests {
@@ -71,6 +98,7 @@ func TestHandleRequestHeaders(t *testing.T) {
 			assert.NoError(t, err, "HandleRequestHeaders should not return an error")
 
 			assert.Equal(t, tc.wantFairnessID, reqCtx.FairnessID, "FairnessID should match expected value")
+			assert.Equal(t, tc.wantRequestTTL, reqCtx.RequestTTL, "RequestTTL should match expected value")
 
 			if tc.wantHeaders != nil {
 				for k, v := range tc.wantHeaders {
@@ -109,6 +137,46 @@ func TestGenerateHeaders_Sanitization(t *testing.T) {
 	assert.Equal(t, "123", gotHeaders["Content-Length"])
 }
 
+func TestGenerateRequestHeaderResponse_RemovesDeletedClientHeaders(t *testing.T) {
+	t.Parallel()
+
+	server := &StreamingServer{}
+	reqCtx := &RequestContext{
+		TargetEndpoint: "1.2.3.4:8080",
+		Request:        &Request{Headers: make(map[string]string)},
+		Response:       &Response{},
+	}
+	req := &extProcPb.ProcessingRequest_RequestHeaders{
+		RequestHeaders: &extProcPb.HttpHeaders{
+			Headers: &configPb.HeaderMap{Headers: []*configPb.HeaderValue{
+				{Key: "x-user-data", Value: "important"},
+				{Key: profile.PrefillEndpointHeader, Value: "6.6.6.6:8000"},
+				{Key: metadata.ObjectiveKey, Value: "objective"},
+			}},
+		},
+	}
+	err := server.HandleRequestHeaders(context.Background(), reqCtx, req)
+	assert.NoError(t, err, "HandleRequestHeaders should not return an error")
+
+	// No prefill endpoint is selected, so the P/D profile handler drops the header sent by the client.
+	handler := profile.NewPDProfileHandler(profile.DefaultPrefillProfile, profile.DefaultDecodeProfile,
+		profile.PrefillEndpointHeader, 0)
+	handler.PreRequest(context.Background(), &schedulingtypes.LLMRequest{Headers: reqCtx.Request.Headers},
+		&schedulingtypes.SchedulingResult{
+			ProfileResults: map[string]*schedulingtypes.ProfileRunResult{
+				profile.DefaultDecodeProfile: {},
+			},
+			PrimaryProfileName: profile.DefaultDecodeProfile,
+		})
+
+	resp := server.generateRequestHeaderResponse(context.Background(), reqCtx)
+	mutation := resp.GetRequestHeaders().GetResponse().GetHeaderMutation()
+	assert.Equal(t, []string{profile.PrefillEndpointHeader}, mutation.GetRemoveHeaders())
+	for _, h := range mutation.GetSetHeaders() {
+		assert.NotEqual(t, profile.PrefillEndpointHeader, h.Header.Key, "Removed header should not be set")
+	}
+}
+
 func TestGenerateRequestHeaderResponse_MergeMetadata(t *testing.T) {
 	t.Parallel()
 
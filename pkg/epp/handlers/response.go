@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	configPb "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	extProcPb "github.com/envoyproxy/go-control-plane/envoy/service/ext_proc/v3"
@@ -30,12 +31,25 @@ import (
 	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/common/util/logging"
 	handlerstypes "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/handlers/types"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metrics"
+	errutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/error"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/request"
 )
 
 const (
-	streamingRespPrefix = "data: "
-	streamingEndMsg     = "data: [DONE]"
+	// streamingEndData is the data of the event ending the completions and chat completions streams.
+	streamingEndData = "[DONE]"
+	// Responses API streams end with one of these events instead of streamingEndData.
+	responsesCompletedEvent  = "response.completed"
+	responsesIncompleteEvent = "response.incomplete"
+	responsesFailedEvent     = "response.failed"
+	// Anthropic Messages API streams end with this event instead of streamingEndData.
+	anthropicMessageStopEvent = "message_stop"
+
+	// streamingErrorEvent is the type of the events reporting an error in the middle of a stream.
+	streamingErrorEvent = "error"
+
+	// anthropicMessageDeltaType is the type of the Anthropic streaming events carrying the cumulative output usage.
+	anthropicMessageDeltaType = "message_delta"
 )
 
 // HandleResponseBody always returns the requestContext even in the error case, as the request context is used in error handling.
@@ -45,24 +59,21 @@ func (s *StreamingServer) HandleResponseBody(ctx context.Context, reqCtx *Reques
 	if err != nil {
 		return reqCtx, fmt.Errorf("error marshalling responseBody - %w", err)
 	}
+	var responseBody ResponseBody
+	if err := json.Unmarshal(responseBytes, &responseBody); err != nil {
+		logger.Error(err, "unmarshaling response body")
+	}
 	if response["usage"] != nil {
-		usg := response["usage"].(map[string]any)
-		usage := handlerstypes.Usage{
-			PromptTokens:     int(usg["prompt_tokens"].(float64)),
-			CompletionTokens: int(usg["completion_tokens"].(float64)),
-			TotalTokens:      int(usg["total_tokens"].(float64)),
-		}
-		if usg["prompt_token_details"] != nil {
-			detailsMap := usg["prompt_token_details"].(map[string]any)
-			if cachedTokens, ok := detailsMap["cached_tokens"]; ok {
-				usage.PromptTokenDetails = &handlerstypes.PromptTokenDetails{
-					CachedTokens: int(cachedTokens.(float64)),
-				}
-			}
+		reqCtx.Usage = responseBody.Usage
+		if reqCtx.Usage.TotalTokens == 0 { // the Anthropic Messages API has no total
+			reqCtx.Usage.TotalTokens = reqCtx.Usage.PromptTokens + reqCtx.Usage.CompletionTokens
 		}
+		logger.V(logutil.VERBOSE).Info("Response generated", "usage", reqCtx.Usage)
+	} else if usage, ok := responseBody.openInferenceUsage(); ok {
 		reqCtx.Usage = usage
 		logger.V(logutil.VERBOSE).Info("Response generated", "usage", reqCtx.Usage)
 	}
+	reqCtx.ResponseID = responseBody.ID
 	reqCtx.ResponseSize = len(responseBytes)
 	// ResponseComplete is to indicate the response is complete. In non-streaming
 	// case, it will be set to be true once the response is processed; in
@@ -76,29 +87,75 @@ func (s *StreamingServer) HandleResponseBody(ctx context.Context, reqCtx *Reques
 	return s.director.HandleResponseBodyComplete(ctx, reqCtx)
 }
 
-// The function is to handle streaming response if the modelServer is streaming.
-func (s *StreamingServer) HandleResponseBodyModelStreaming(ctx context.Context, reqCtx *RequestContext, responseText string) {
+// HandleResponseBodyModelStreaming handles a chunk of the body of a streamed response. The chunk is decoded into
+// the server-sent events it completes, which update the usage of the request and are passed to the ResponseStreaming
+// plugins. endOfStream is true for the last chunk of the body.
+func (s *StreamingServer) HandleResponseBodyModelStreaming(ctx context.Context, reqCtx *RequestContext, chunk []byte, endOfStream bool) {
 	logger := log.FromContext(ctx)
-	_, err := s.director.HandleResponseBodyStreaming(ctx, reqCtx)
-	if err != nil {
-		logger.Error(err, "error in HandleResponseBodyStreaming")
+	reqCtx.ResponseChunk = chunk
+	reqCtx.ResponseChunkTimestamp = time.Now()
+
+	events := reqCtx.responseStream.decode(chunk)
+	if endOfStream {
+		events = append(events, reqCtx.responseStream.flush()...)
 	}
+	reqCtx.ResponseEvents = make([]handlerstypes.StreamEvent, 0, len(events))
+	for _, event := range events {
+		streamEvent, err := handleStreamEvent(reqCtx, event)
+		if err != nil {
+			logger.Error(err, "unmarshaling response stream event", "type", event.Type)
+		}
+		reqCtx.ResponseEvents = append(reqCtx.ResponseEvents, streamEvent)
 
-	// Parse usage on EVERY chunk to catch split streams (where usage and [DONE] are in different chunks).
-	if resp := parseRespForUsage(ctx, responseText); resp.Usage.TotalTokens > 0 {
-		reqCtx.Usage = resp.Usage
+		if streamEvent.End && !reqCtx.ResponseComplete {
+			reqCtx.ResponseComplete = true
+			metrics.RecordInputTokens(reqCtx.IncomingModelName, reqCtx.TargetModelName, reqCtx.Usage.PromptTokens)
+			metrics.RecordOutputTokens(reqCtx.IncomingModelName, reqCtx.TargetModelName, reqCtx.Usage.CompletionTokens)
+			cachedToken := 0
+			if reqCtx.Usage.PromptTokenDetails != nil {
+				cachedToken = reqCtx.Usage.PromptTokenDetails.CachedTokens
+			}
+			metrics.RecordPromptCachedTokens(reqCtx.IncomingModelName, reqCtx.TargetModelName, cachedToken)
+		}
 	}
+	reqCtx.recordGeneratedTokens()
 
-	if strings.Contains(responseText, streamingEndMsg) {
-		reqCtx.ResponseComplete = true
-		metrics.RecordInputTokens(reqCtx.IncomingModelName, reqCtx.TargetModelName, reqCtx.Usage.PromptTokens)
-		metrics.RecordOutputTokens(reqCtx.IncomingModelName, reqCtx.TargetModelName, reqCtx.Usage.CompletionTokens)
-		cachedToken := 0
-		if reqCtx.Usage.PromptTokenDetails != nil {
-			cachedToken = reqCtx.Usage.PromptTokenDetails.CachedTokens
+	if _, err := s.director.HandleResponseBodyStreaming(ctx, reqCtx); err != nil {
+		logger.Error(err, "error in HandleResponseBodyStreaming")
+	}
+}
+
+// recordGeneratedTokens updates the token counts and timestamps of a streamed response with its last chunk. Model
+// servers stream one event per decoding step, so each event carrying generated text is counted as one token.
+func (r *RequestContext) recordGeneratedTokens() {
+	r.ResponseChunkTokens = 0
+	r.InterTokenLatency = 0
+	for _, event := range r.ResponseEvents {
+		if event.Delta != "" {
+			r.ResponseChunkTokens++
 		}
-		metrics.RecordPromptCachedTokens(reqCtx.IncomingModelName, reqCtx.TargetModelName, cachedToken)
 	}
+	if r.ResponseChunkTokens == 0 {
+		return
+	}
+
+	r.estimatedOutputTokens += r.ResponseChunkTokens
+	if r.FirstTokenTimestamp.IsZero() {
+		r.FirstTokenTimestamp = r.ResponseChunkTimestamp
+	} else {
+		r.InterTokenLatency = r.ResponseChunkTimestamp.Sub(r.LastTokenTimestamp) / time.Duration(r.ResponseChunkTokens)
+	}
+	r.LastTokenTimestamp = r.ResponseChunkTimestamp
+}
+
+// OutputTokens returns the number of tokens generated so far in a streamed response, and whether it is an estimate.
+// It is the completion tokens of the usage reported by the model server, e.g. when the request sets
+// "stream_options": {"include_usage": true}, or else the number of events carrying generated text.
+func (r *RequestContext) OutputTokens() (int, bool) {
+	if r.Usage.CompletionTokens > 0 {
+		return r.Usage.CompletionTokens, false
+	}
+	return r.estimatedOutputTokens, true
 }
 
 func (s *StreamingServer) HandleResponseHeaders(ctx context.Context, reqCtx *RequestContext, resp *extProcPb.ProcessingRequest_ResponseHeaders) (*RequestContext, error) {
@@ -168,43 +225,259 @@ func (s *StreamingServer) generateResponseHeaders(reqCtx *RequestContext) []*con
 	return headers
 }
 
-// Example message if "stream_options": {"include_usage": "true"} is included in the request:
+// handleStreamEvent parses an event of a streamed response and updates the usage and the response id of the request
+// with it. A usage chunk of a completions stream, sent when "stream_options": {"include_usage": true} is set in the
+// request, looks like:
 // data: {"id":"...","object":"text_completion","created":1739400043,"model":"food-review-0","choices":[],
 // "usage":{"prompt_tokens":7,"total_tokens":17,"completion_tokens":10}}
 //
 // data: [DONE]
-//
-// Noticed that vLLM returns two entries in one response.
-// We need to strip the `data:` prefix and next Data: [DONE] from the message to fetch response data.
-//
-// If include_usage is not included in the request, `data: [DONE]` is returned separately, which
-// indicates end of streaming.
-func parseRespForUsage(ctx context.Context, responseText string) ResponseBody {
-	response := ResponseBody{}
-	logger := log.FromContext(ctx)
+func handleStreamEvent(reqCtx *RequestContext, event sseEvent) (handlerstypes.StreamEvent, error) {
+	streamEvent := handlerstypes.StreamEvent{Type: event.Type, Data: event.Data}
+	if event.Data == streamingEndData {
+		streamEvent.End = true
+		return streamEvent, nil
+	}
 
-	lines := strings.SplitSeq(responseText, "\n")
-	for line := range lines {
-		if !strings.HasPrefix(line, streamingRespPrefix) {
-			continue
+	var body streamEventBody
+	if err := json.Unmarshal([]byte(event.Data), &body); err != nil {
+		return streamEvent, err
+	}
+	if streamEvent.Type == "" {
+		streamEvent.Type = body.Type
+	}
+	streamEvent.Delta, streamEvent.FinishReason = body.generated()
+	streamEvent.Usage = updateStreamUsage(reqCtx, &body)
+	// Model servers report the errors occurring after they started streaming in error events, e.g. vLLM in
+	// {"error": {...}} data and the Anthropic Messages API in error events.
+	if (len(body.Error) > 0 && string(body.Error) != "null") || streamEvent.Type == streamingErrorEvent {
+		reqCtx.ResponseStatusCode = errutil.ClassifyModelServerError(reqCtx.modelServerStatus, []byte(event.Data))
+	}
+
+	switch streamEvent.Type {
+	case responsesCompletedEvent, responsesIncompleteEvent, responsesFailedEvent:
+		streamEvent.End = true
+		if body.Response != nil {
+			streamEvent.FinishReason = body.Response.Status
 		}
-		content := strings.TrimPrefix(line, streamingRespPrefix)
-		if content == "[DONE]" {
-			continue
+	case anthropicMessageStopEvent:
+		streamEvent.End = true
+	}
+
+	if reqCtx.ResponseID == "" {
+		switch {
+		case body.Response != nil:
+			reqCtx.ResponseID = body.Response.ID
+		case body.Message != nil:
+			reqCtx.ResponseID = body.Message.ID
+		default:
+			reqCtx.ResponseID = body.ID
 		}
+	}
+	return streamEvent, nil
+}
 
-		byteSlice := []byte(content)
-		if err := json.Unmarshal(byteSlice, &response); err != nil {
-			logger.Error(err, "unmarshaling response body")
-			continue
+// updateStreamUsage updates the usage of the request with the usage reported by an event of a streamed response,
+// and returns the latter, or nil if the event reports none.
+func updateStreamUsage(reqCtx *RequestContext, body *streamEventBody) *handlerstypes.Usage {
+	switch {
+	case body.Type == anthropicMessageDeltaType:
+		// Anthropic streams carry the cumulative output usage in message_delta events. The usage of a message_delta
+		// event is not a total: it may not report the input tokens.
+		if body.Usage.PromptTokens > 0 {
+			reqCtx.Usage.PromptTokens = body.Usage.PromptTokens
+			reqCtx.Usage.PromptTokenDetails = body.Usage.PromptTokenDetails
+		}
+		reqCtx.Usage.CompletionTokens = body.Usage.CompletionTokens
+		reqCtx.Usage.TotalTokens = reqCtx.Usage.PromptTokens + reqCtx.Usage.CompletionTokens
+		return &body.Usage
+	case body.Usage.TotalTokens > 0:
+		reqCtx.Usage = body.Usage
+		return &body.Usage
+	case body.Response != nil && body.Response.Usage.TotalTokens > 0:
+		// Responses API streams carry the response object, with its usage, in the response lifecycle events.
+		reqCtx.Usage = body.Response.Usage
+		return &body.Response.Usage
+	case body.Message != nil && body.Message.Usage.PromptTokens > 0:
+		// Anthropic streams carry the input usage in the message of the message_start event.
+		reqCtx.Usage.PromptTokens = body.Message.Usage.PromptTokens
+		reqCtx.Usage.PromptTokenDetails = body.Message.Usage.PromptTokenDetails
+		reqCtx.Usage.TotalTokens = reqCtx.Usage.PromptTokens + reqCtx.Usage.CompletionTokens
+		return &body.Message.Usage
+	case body.NumInputTokens != nil || body.NumOutputTokens != nil:
+		// Open Inference Protocol generate streams report the input tokens and the tokens generated since the
+		// previous event in their events.
+		usage := handlerstypes.Usage{}
+		if body.NumInputTokens != nil {
+			usage.PromptTokens = int(*body.NumInputTokens)
+			reqCtx.Usage.PromptTokens = usage.PromptTokens
 		}
+		if body.NumOutputTokens != nil {
+			usage.CompletionTokens = int(*body.NumOutputTokens)
+			reqCtx.Usage.CompletionTokens += usage.CompletionTokens
+		}
+		usage.TotalTokens = usage.PromptTokens + usage.CompletionTokens
+		reqCtx.Usage.TotalTokens = reqCtx.Usage.PromptTokens + reqCtx.Usage.CompletionTokens
+		return &usage
 	}
+	return nil
+}
+
+// streamEventBody is the data of an event of a streamed response.
+type streamEventBody struct {
+	ResponseBody
+	// Choices are the choices of a completions or chat completions chunk.
+	Choices []streamChoice `json:"choices"`
+	// Delta is the generated text of a Responses API delta event, or the delta object of an Anthropic Messages API
+	// delta event.
+	Delta json.RawMessage `json:"delta"`
+	// TextOutput is the generated text of an Open Inference Protocol generate event.
+	TextOutput string `json:"text_output"`
+	// Error is the error reported by an error event.
+	Error json.RawMessage `json:"error"`
+}
+
+// streamChoice is a choice of a completions or chat completions chunk.
+type streamChoice struct {
+	// Text is the generated text of a completions choice.
+	Text string `json:"text"`
+	// Delta is the generated message delta of a chat completions choice.
+	Delta struct {
+		Content          string `json:"content"`
+		ReasoningContent string `json:"reasoning_content"`
+		ToolCalls        []struct {
+			Function struct {
+				Arguments string `json:"arguments"`
+			} `json:"function"`
+		} `json:"tool_calls"`
+	} `json:"delta"`
+	FinishReason string `json:"finish_reason"`
+}
 
-	return response
+// anthropicDelta is the delta object of the content_block_delta and message_delta events of an Anthropic stream.
+type anthropicDelta struct {
+	Text        string `json:"text"`
+	Thinking    string `json:"thinking"`
+	PartialJSON string `json:"partial_json"`
+	StopReason  string `json:"stop_reason"`
+}
+
+// generated returns the text generated in the event and the reason the model stopped generating, if the event
+// reports it.
+func (b *streamEventBody) generated() (string, string) {
+	var text strings.Builder
+	finishReason := ""
+	for _, choice := range b.Choices {
+		text.WriteString(choice.Text)
+		text.WriteString(choice.Delta.ReasoningContent)
+		text.WriteString(choice.Delta.Content)
+		for _, call := range choice.Delta.ToolCalls {
+			text.WriteString(call.Function.Arguments)
+		}
+		if finishReason == "" {
+			finishReason = choice.FinishReason
+		}
+	}
+	text.WriteString(b.TextOutput)
+
+	if len(b.Delta) > 0 {
+		switch b.Delta[0] {
+		case '"':
+			var delta string
+			if json.Unmarshal(b.Delta, &delta) == nil {
+				text.WriteString(delta)
+			}
+		case '{':
+			var delta anthropicDelta
+			if json.Unmarshal(b.Delta, &delta) == nil {
+				text.WriteString(delta.Thinking)
+				text.WriteString(delta.Text)
+				text.WriteString(delta.PartialJSON)
+				if delta.StopReason != "" {
+					finishReason = delta.StopReason
+				}
+			}
+		}
+	}
+	return text.String(), finishReason
 }
 
 type ResponseBody struct {
+	ID    string              `json:"id"`
+	Type  string              `json:"type"`
 	Usage handlerstypes.Usage `json:"usage"`
+	// Status is the status of a Responses API response.
+	Status string `json:"status"`
+	// Response is the response object sent in the events of a Responses API stream.
+	Response *ResponseBody `json:"response"`
+	// Message is the message object sent in the message_start event of an Anthropic stream.
+	Message *ResponseBody `json:"message"`
+
+	// NumInputTokens and NumOutputTokens are the usage of an Open Inference Protocol generate response, as reported
+	// by the Triton vLLM backend when return_num_input_tokens and return_num_output_tokens are set.
+	NumInputTokens  *tensorCount `json:"num_input_tokens"`
+	NumOutputTokens *tensorCount `json:"num_output_tokens"`
+	// Outputs are the output tensors of an Open Inference Protocol infer response, which carry the same usage as
+	// num_input_tokens and num_output_tokens tensors.
+	Outputs []inferOutputTensor `json:"outputs"`
+}
+
+// openInferenceUsage returns the usage of an Open Inference Protocol response, if it reports any.
+func (r *ResponseBody) openInferenceUsage() (handlerstypes.Usage, bool) {
+	input, output := r.NumInputTokens, r.NumOutputTokens
+	for _, tensor := range r.Outputs {
+		var count tensorCount
+		if json.Unmarshal(tensor.Data, &count) != nil {
+			continue
+		}
+		switch tensor.Name {
+		case "num_input_tokens":
+			input = &count
+		case "num_output_tokens":
+			output = &count
+		}
+	}
+	if input == nil && output == nil {
+		return handlerstypes.Usage{}, false
+	}
+
+	usage := handlerstypes.Usage{}
+	if input != nil {
+		usage.PromptTokens = int(*input)
+	}
+	if output != nil {
+		usage.CompletionTokens = int(*output)
+	}
+	usage.TotalTokens = usage.PromptTokens + usage.CompletionTokens
+	return usage, true
+}
+
+// inferOutputTensor is an output tensor of an Open Inference Protocol infer response.
+type inferOutputTensor struct {
+	Name string          `json:"name"`
+	Data json.RawMessage `json:"data"`
+}
+
+// tensorCount is a token count of an Open Inference Protocol response. It is sent either as a number or as tensor
+// data, holding one count per generated sequence, which are summed.
+type tensorCount int
+
+func (c *tensorCount) UnmarshalJSON(data []byte) error {
+	var count int
+	if err := json.Unmarshal(data, &count); err == nil {
+		*c = tensorCount(count)
+		return nil
+	}
+
+	var counts []int
+	if err := json.Unmarshal(data, &counts); err != nil {
+		return err
+	}
+	*c = 0
+	for _, count := range counts {
+		*c += tensorCount(count)
+	}
+	return nil
 }
 
 type PromptTokenDetails struct {
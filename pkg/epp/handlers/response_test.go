@@ -28,6 +28,7 @@ import (
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datalayer"
 	handlerstypes "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/handlers/types"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metadata"
+	errutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/error"
 )
 
 const (
@@ -81,15 +82,109 @@ const (
 	}
 	`
 
-	streamingBodyWithoutUsage = `data: {"id":"cmpl-41764c93-f9d2-4f31-be08-3ba04fa25394","object":"text_completion","created":1740002445,"model":"food-review-0","choices":[],"usage":null}
+	responsesBody = `
+	{
+		"id": "resp_67ccd2bed1ec8190b14f964abc054267",
+		"object": "response",
+		"status": "completed",
+		"model": "meta-llama/Llama-3.1-8B-Instruct",
+		"output": [
+			{
+				"type": "message",
+				"role": "assistant",
+				"content": [{"type": "output_text", "text": "Hello!"}]
+			}
+		],
+		"usage": {
+			"input_tokens": 11,
+			"input_tokens_details": {
+				"cached_tokens": 4
+			},
+			"output_tokens": 100,
+			"total_tokens": 111
+		}
+	}
+	`
+
+	anthropicBody = `
+	{
+		"id": "msg_013Zva2CMHLNnXjNJJKqJ2EF",
+		"type": "message",
+		"role": "assistant",
+		"model": "claude-sonnet-4-5",
+		"content": [{"type": "text", "text": "Hello!"}],
+		"stop_reason": "end_turn",
+		"usage": {
+			"input_tokens": 7,
+			"cache_creation_input_tokens": 0,
+			"cache_read_input_tokens": 4,
+			"output_tokens": 100
+		}
+	}
+	`
+
+	openInferenceBody = `
+	{
+		"id": "req-1",
+		"model_name": "llama",
+		"outputs": [
+			{"name": "text_output", "datatype": "BYTES", "shape": [1], "data": ["Hello!"]},
+			{"name": "num_input_tokens", "datatype": "UINT32", "shape": [1], "data": [11]},
+			{"name": "num_output_tokens", "datatype": "UINT32", "shape": [1], "data": [100]}
+		]
+	}
 	`
 
+	openInferenceGenerateBody = `
+	{
+		"model_name": "llama",
+		"model_version": "1",
+		"text_output": "Hello!",
+		"num_input_tokens": 11,
+		"num_output_tokens": 100
+	}
+	`
+
+	streamingBodyWithoutUsage = `data: {"id":"cmpl-41764c93-f9d2-4f31-be08-3ba04fa25394","object":"text_completion","created":1740002445,"model":"food-review-0","choices":[],"usage":null}
+
+`
+
 	streamingBodyWithUsage = `data: {"id":"cmpl-41764c93-f9d2-4f31-be08-3ba04fa25394","object":"text_completion","created":1740002445,"model":"food-review-0","choices":[],"usage":{"prompt_tokens":7,"total_tokens":17,"completion_tokens":10}}
+
 data: [DONE]
-	`
+
+`
 	streamingBodyWithUsageAndCachedTokens = `data: {"id":"cmpl-41764c93-f9d2-4f31-be08-3ba04fa25394","object":"text_completion","created":1740002445,"model":"food-review-0","choices":[],"usage":{"prompt_tokens":7,"total_tokens":17,"completion_tokens":10,"prompt_token_details":{"cached_tokens":5}}}
+
 data: [DONE]
-	`
+
+`
+	streamingAnthropicBodyStart = `event: message_start
+data: {"type":"message_start","message":{"id":"msg_1","type":"message","role":"assistant","content":[],"usage":{"input_tokens":3,"cache_read_input_tokens":4,"output_tokens":1}}}
+
+event: content_block_start
+data: {"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}
+
+`
+	streamingAnthropicBodyEnd = `event: message_delta
+data: {"type":"message_delta","delta":{"stop_reason":"end_turn"},"usage":{"output_tokens":10}}
+
+event: message_stop
+data: {"type":"message_stop"}
+
+`
+	streamingOpenInferenceBody = `data: {"model_name":"llama","model_version":"1","text_output":"Hel","num_input_tokens":7,"num_output_tokens":4}
+
+data: {"model_name":"llama","model_version":"1","text_output":"lo!","num_input_tokens":7,"num_output_tokens":6}
+
+`
+	streamingResponsesBodyWithUsage = `event: response.output_text.delta
+data: {"type":"response.output_text.delta","item_id":"msg_1","output_index":0,"content_index":0,"delta":"Hi"}
+
+event: response.completed
+data: {"type":"response.completed","response":{"id":"resp_1","object":"response","status":"completed","usage":{"input_tokens":7,"output_tokens":10,"total_tokens":17}}}
+
+`
 )
 
 type mockDirector struct{}
@@ -121,6 +216,7 @@ func TestHandleResponseBody(t *testing.T) {
 		body    []byte
 		reqCtx  *RequestContext
 		want    handlerstypes.Usage
+		wantID  string
 		wantErr bool
 	}{
 		{
@@ -131,6 +227,52 @@ func TestHandleResponseBody(t *testing.T) {
 				TotalTokens:      111,
 				CompletionTokens: 100,
 			},
+			wantID: "cmpl-573498d260f2423f9e42817bbba3743a",
+		},
+		{
+			name: "responses api",
+			body: []byte(responsesBody),
+			want: handlerstypes.Usage{
+				PromptTokens:     11,
+				TotalTokens:      111,
+				CompletionTokens: 100,
+				PromptTokenDetails: &handlerstypes.PromptTokenDetails{
+					CachedTokens: 4,
+				},
+			},
+			wantID: "resp_67ccd2bed1ec8190b14f964abc054267",
+		},
+		{
+			name: "anthropic messages api",
+			body: []byte(anthropicBody),
+			want: handlerstypes.Usage{
+				PromptTokens:     11,
+				TotalTokens:      111,
+				CompletionTokens: 100,
+				PromptTokenDetails: &handlerstypes.PromptTokenDetails{
+					CachedTokens: 4,
+				},
+			},
+			wantID: "msg_013Zva2CMHLNnXjNJJKqJ2EF",
+		},
+		{
+			name: "open inference infer",
+			body: []byte(openInferenceBody),
+			want: handlerstypes.Usage{
+				PromptTokens:     11,
+				TotalTokens:      111,
+				CompletionTokens: 100,
+			},
+			wantID: "req-1",
+		},
+		{
+			name: "open inference generate",
+			body: []byte(openInferenceGenerateBody),
+			want: handlerstypes.Usage{
+				PromptTokens:     11,
+				TotalTokens:      111,
+				CompletionTokens: 100,
+			},
 		},
 		{
 			name: "success with cached tokens",
@@ -143,6 +285,7 @@ func TestHandleResponseBody(t *testing.T) {
 					CachedTokens: 10,
 				},
 			},
+			wantID: "cmpl-573498d260f2423f9e42817bbba3743a",
 		},
 	}
 
@@ -170,6 +313,9 @@ func TestHandleResponseBody(t *testing.T) {
 			if diff := cmp.Diff(test.want, reqCtx.Usage); diff != "" {
 				t.Errorf("HandleResponseBody returned unexpected response, diff(-want, +got): %v", diff)
 			}
+			if reqCtx.ResponseID != test.wantID {
+				t.Errorf("HandleResponseBody returned unexpected response id %q, want %q", reqCtx.ResponseID, test.wantID)
+			}
 		})
 	}
 }
@@ -181,6 +327,7 @@ func TestHandleStreamedResponseBody(t *testing.T) {
 		body    string
 		reqCtx  *RequestContext
 		want    handlerstypes.Usage
+		wantID  string
 		wantErr bool
 	}{
 		{
@@ -221,6 +368,33 @@ func TestHandleStreamedResponseBody(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "responses api streaming request with usage",
+			body: streamingResponsesBodyWithUsage,
+			reqCtx: &RequestContext{
+				modelServerStreaming: true,
+			},
+			wantErr: false,
+			want: handlerstypes.Usage{
+				PromptTokens:     7,
+				TotalTokens:      17,
+				CompletionTokens: 10,
+			},
+			wantID: "resp_1",
+		},
+		{
+			name: "open inference generate stream",
+			body: streamingOpenInferenceBody,
+			reqCtx: &RequestContext{
+				modelServerStreaming: true,
+			},
+			wantErr: false,
+			want: handlerstypes.Usage{
+				PromptTokens:     7,
+				TotalTokens:      17,
+				CompletionTokens: 10,
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -231,15 +405,40 @@ func TestHandleStreamedResponseBody(t *testing.T) {
 			if reqCtx == nil {
 				reqCtx = &RequestContext{}
 			}
-			server.HandleResponseBodyModelStreaming(ctx, reqCtx, test.body)
+			server.HandleResponseBodyModelStreaming(ctx, reqCtx, []byte(test.body), true)
 
 			if diff := cmp.Diff(test.want, reqCtx.Usage); diff != "" {
 				t.Errorf("HandleResponseBody returned unexpected response, diff(-want, +got): %v", diff)
 			}
+			if test.wantID != "" && reqCtx.ResponseID != test.wantID {
+				t.Errorf("HandleResponseBody returned unexpected response id %q, want %q", reqCtx.ResponseID, test.wantID)
+			}
 		})
 	}
 }
 
+func TestHandleAnthropicStreamedResponseBody(t *testing.T) {
+	ctx := logutil.NewTestLoggerIntoContext(context.Background())
+	server := &StreamingServer{director: &mockDirector{}}
+	reqCtx := &RequestContext{modelServerStreaming: true}
+
+	server.HandleResponseBodyModelStreaming(ctx, reqCtx, []byte(streamingAnthropicBodyStart), false)
+	assert.False(t, reqCtx.ResponseComplete)
+	assert.Equal(t, "msg_1", reqCtx.ResponseID)
+
+	server.HandleResponseBodyModelStreaming(ctx, reqCtx, []byte(streamingAnthropicBodyEnd), true)
+	assert.True(t, reqCtx.ResponseComplete, "message_stop should complete the response")
+	want := handlerstypes.Usage{
+		PromptTokens:       7,
+		CompletionTokens:   10,
+		TotalTokens:        17,
+		PromptTokenDetails: &handlerstypes.PromptTokenDetails{CachedTokens: 4},
+	}
+	if diff := cmp.Diff(want, reqCtx.Usage); diff != "" {
+		t.Errorf("HandleResponseBodyModelStreaming returned unexpected usage, diff(-want, +got): %v", diff)
+	}
+}
+
 func TestHandleResponseBodyModelStreaming_TokenAccumulation(t *testing.T) {
 	t.Parallel()
 
@@ -251,7 +450,7 @@ func TestHandleResponseBodyModelStreaming_TokenAccumulation(t *testing.T) {
 		{
 			name: "Standard: Usage and DONE in same chunk",
 			chunks: []string{
-				`data: {"usage":{"prompt_tokens":5,"completion_tokens":10,"total_tokens":15}}` + "\n" + `data: [DONE]`,
+				`data: {"usage":{"prompt_tokens":5,"completion_tokens":10,"total_tokens":15}}` + "\n\n" + `data: [DONE]` + "\n\n",
 			},
 			wantUsage: handlerstypes.Usage{PromptTokens: 5, CompletionTokens: 10, TotalTokens: 15},
 		},
@@ -259,26 +458,26 @@ func TestHandleResponseBodyModelStreaming_TokenAccumulation(t *testing.T) {
 			name: "Split: Usage in Chunk 1, DONE in Chunk 2",
 			chunks: []string{
 				// Chunk 1: Usage data arrives
-				`data: {"usage":{"prompt_tokens":5,"completion_tokens":10,"total_tokens":15}}` + "\n",
+				`data: {"usage":{"prompt_tokens":5,"completion_tokens":10,"total_tokens":15}}` + "\n\n",
 				// Chunk 2: Stream termination. Should NOT overwrite the usage from Chunk 1.
-				`data: [DONE]`,
+				`data: [DONE]` + "\n\n",
 			},
 			wantUsage: handlerstypes.Usage{PromptTokens: 5, CompletionTokens: 10, TotalTokens: 15},
 		},
 		{
 			name: "Fragmented: Content -> Usage -> DONE",
 			chunks: []string{
-				`data: {"choices":[{"text":"Hello"}]}` + "\n",
-				`data: {"usage":{"prompt_tokens":5,"completion_tokens":10,"total_tokens":15}}` + "\n",
-				`data: [DONE]`,
+				`data: {"choices":[{"text":"Hello"}]}` + "\n\n",
+				`data: {"usage":{"prompt_tokens":5,"completion_tokens":10,"total_tokens":15}}` + "\n\n",
+				`data: [DONE]` + "\n\n",
 			},
 			wantUsage: handlerstypes.Usage{PromptTokens: 5, CompletionTokens: 10, TotalTokens: 15},
 		},
 		{
 			name: "No Usage Data",
 			chunks: []string{
-				`data: {"choices":[{"text":"Hello"}]}` + "\n",
-				`data: [DONE]`,
+				`data: {"choices":[{"text":"Hello"}]}` + "\n\n",
+				`data: [DONE]` + "\n\n",
 			},
 			wantUsage: handlerstypes.Usage{}, // Zero values
 		},
@@ -292,7 +491,7 @@ func TestHandleResponseBodyModelStreaming_TokenAccumulation(t *testing.T) {
 			reqCtx := &RequestContext{}
 
 			for _, chunk := range tc.chunks {
-				server.HandleResponseBodyModelStreaming(context.Background(), reqCtx, chunk)
+				server.HandleResponseBodyModelStreaming(context.Background(), reqCtx, []byte(chunk), false)
 			}
 
 			assert.Equal(t, tc.wantUsage, reqCtx.Usage, "Usage data should match expected accumulation")
@@ -301,6 +500,140 @@ func TestHandleResponseBodyModelStreaming_TokenAccumulation(t *testing.T) {
 	}
 }
 
+func TestHandleResponseBodyModelStreaming_Events(t *testing.T) {
+	ctx := logutil.NewTestLoggerIntoContext(context.Background())
+	usage := &handlerstypes.Usage{PromptTokens: 7, CompletionTokens: 2, TotalTokens: 9}
+
+	tests := []struct {
+		name   string
+		chunks []string
+		want   [][]handlerstypes.StreamEvent
+	}{
+		{
+			name: "chat completions chunks split mid-event",
+			chunks: []string{
+				`data: {"choices":[{"delta":{"role":"assistant"}}]}` + "\n\n" + `data: {"choices":[{"delta":{"content":"Hel`,
+				`lo"}}]}` + "\n\n" + `data: {"choices":[{"delta":{"content":"!"},"finish_reason":"stop"}]}` + "\n\n",
+				`data: {"choices":[],"usage":{"prompt_tokens":7,"completion_tokens":2,"total_tokens":9}}` + "\n\n" + `data: [DONE]` + "\n\n",
+			},
+			want: [][]handlerstypes.StreamEvent{
+				{{Data: `{"choices":[{"delta":{"role":"assistant"}}]}`}},
+				{
+					{Data: `{"choices":[{"delta":{"content":"Hello"}}]}`, Delta: "Hello"},
+					{Data: `{"choices":[{"delta":{"content":"!"},"finish_reason":"stop"}]}`, Delta: "!", FinishReason: "stop"},
+				},
+				{
+					{Data: `{"choices":[],"usage":{"prompt_tokens":7,"completion_tokens":2,"total_tokens":9}}`, Usage: usage},
+					{Data: "[DONE]", End: true},
+				},
+			},
+		},
+		{
+			name: "anthropic messages stream",
+			chunks: []string{
+				"event: message_start\n" + `data: {"type":"message_start","message":{"id":"msg_1","usage":{"input_tokens":7,"output_tokens":1}}}` + "\n\n" +
+					"event: content_block_delta\n" + `data: {"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Hi"}}` + "\n\n",
+				"event: message_delta\n" + `data: {"type":"message_delta","delta":{"stop_reason":"end_turn"},"usage":{"output_tokens":2}}` + "\n\n" +
+					"event: message_stop\n" + `data: {"type":"message_stop"}` + "\n\n",
+			},
+			want: [][]handlerstypes.StreamEvent{
+				{
+					{Type: "message_start", Data: `{"type":"message_start","message":{"id":"msg_1","usage":{"input_tokens":7,"output_tokens":1}}}`, Usage: &handlerstypes.Usage{PromptTokens: 7, CompletionTokens: 1}},
+					{Type: "content_block_delta", Data: `{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Hi"}}`, Delta: "Hi"},
+				},
+				{
+					{Type: "message_delta", Data: `{"type":"message_delta","delta":{"stop_reason":"end_turn"},"usage":{"output_tokens":2}}`, FinishReason: "end_turn", Usage: &handlerstypes.Usage{CompletionTokens: 2}},
+					{Type: "message_stop", Data: `{"type":"message_stop"}`, End: true},
+				},
+			},
+		},
+		{
+			name: "responses api stream",
+			chunks: []string{
+				"event: response.output_text.delta\n" + `data: {"type":"response.output_text.delta","delta":"Hi"}` + "\n\n" +
+					"event: response.completed\n" + `data: {"type":"response.completed","response":{"id":"resp_1","status":"completed","usage":{"input_tokens":7,"output_tokens":2,"total_tokens":9}}}` + "\n\n",
+			},
+			want: [][]handlerstypes.StreamEvent{
+				{
+					{Type: "response.output_text.delta", Data: `{"type":"response.output_text.delta","delta":"Hi"}`, Delta: "Hi"},
+					{Type: "response.completed", Data: `{"type":"response.completed","response":{"id":"resp_1","status":"completed","usage":{"input_tokens":7,"output_tokens":2,"total_tokens":9}}}`, FinishReason: "completed", Usage: usage, End: true},
+				},
+			},
+		},
+		{
+			name: "open inference generate stream",
+			chunks: []string{
+				`data: {"text_output":"Hi","num_input_tokens":7,"num_output_tokens":2}` + "\n\n",
+			},
+			want: [][]handlerstypes.StreamEvent{
+				{{Data: `{"text_output":"Hi","num_input_tokens":7,"num_output_tokens":2}`, Delta: "Hi", Usage: usage}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := &StreamingServer{director: &mockDirector{}}
+			reqCtx := &RequestContext{modelServerStreaming: true}
+
+			for i, chunk := range test.chunks {
+				server.HandleResponseBodyModelStreaming(ctx, reqCtx, []byte(chunk), i == len(test.chunks)-1)
+				if diff := cmp.Diff(test.want[i], reqCtx.ResponseEvents); diff != "" {
+					t.Errorf("chunk %d: unexpected events, diff(-want, +got): %v", i, diff)
+				}
+			}
+			assert.Equal(t, *usage, reqCtx.Usage)
+		})
+	}
+}
+
+func TestHandleResponseBodyModelStreaming_OutputTokens(t *testing.T) {
+	ctx := logutil.NewTestLoggerIntoContext(context.Background())
+	server := &StreamingServer{director: &mockDirector{}}
+	reqCtx := &RequestContext{modelServerStreaming: true}
+
+	// A role-only chunk carries no token.
+	server.HandleResponseBodyModelStreaming(ctx, reqCtx, []byte(`data: {"choices":[{"delta":{"role":"assistant"}}]}`+"\n\n"), false)
+	assert.Zero(t, reqCtx.ResponseChunkTokens)
+	assert.True(t, reqCtx.FirstTokenTimestamp.IsZero())
+
+	server.HandleResponseBodyModelStreaming(ctx, reqCtx, []byte(`data: {"choices":[{"delta":{"content":"Hel"}}]}`+"\n\n"), false)
+	firstToken := reqCtx.ResponseChunkTimestamp
+	assert.Equal(t, 1, reqCtx.ResponseChunkTokens)
+	assert.Equal(t, firstToken, reqCtx.FirstTokenTimestamp)
+	assert.Zero(t, reqCtx.InterTokenLatency)
+
+	// Two tokens arriving in one chunk share the latency since the previous token.
+	server.HandleResponseBodyModelStreaming(ctx, reqCtx, []byte(`data: {"choices":[{"delta":{"content":"lo"}}]}`+"\n\n"+
+		`data: {"choices":[{"delta":{"content":"!"}}]}`+"\n\n"), false)
+	assert.Equal(t, 2, reqCtx.ResponseChunkTokens)
+	assert.Equal(t, firstToken, reqCtx.FirstTokenTimestamp)
+	assert.Equal(t, reqCtx.ResponseChunkTimestamp.Sub(firstToken)/2, reqCtx.InterTokenLatency)
+	outputTokens, estimated := reqCtx.OutputTokens()
+	assert.Equal(t, 3, outputTokens)
+	assert.True(t, estimated)
+
+	// The usage reported by the model server replaces the estimate.
+	server.HandleResponseBodyModelStreaming(ctx, reqCtx, []byte(`data: {"choices":[],"usage":{"prompt_tokens":7,"completion_tokens":4,"total_tokens":11}}`+"\n\n"+
+		`data: [DONE]`+"\n\n"), true)
+	assert.Zero(t, reqCtx.ResponseChunkTokens)
+	outputTokens, estimated = reqCtx.OutputTokens()
+	assert.Equal(t, 4, outputTokens)
+	assert.False(t, estimated)
+}
+
+func TestHandleResponseBodyModelStreaming_ErrorEvent(t *testing.T) {
+	ctx := logutil.NewTestLoggerIntoContext(context.Background())
+	server := &StreamingServer{director: &mockDirector{}}
+	reqCtx := &RequestContext{modelServerStreaming: true}
+
+	server.HandleResponseBodyModelStreaming(ctx, reqCtx, []byte(`data: {"choices":[{"delta":{"content":"Hi"}}]}`+"\n\n"), false)
+	assert.Empty(t, reqCtx.ResponseStatusCode)
+
+	server.HandleResponseBodyModelStreaming(ctx, reqCtx, []byte(`data: {"error":{"message":"CUDA out of memory. Tried to allocate 2.00 GiB.","type":"InternalServerError","code":500}}`+"\n\n"), true)
+	assert.Equal(t, errutil.ModelServerOutOfMemory, reqCtx.ResponseStatusCode)
+}
+
 func TestGenerateResponseHeaders_Sanitization(t *testing.T) {
 	server := &StreamingServer{}
 	reqCtx := &RequestContext{
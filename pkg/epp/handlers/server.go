@@ -20,9 +20,11 @@ import (
 	"context"
 	"encoding/json"
 	"io"
+	"strconv"
 	"strings"
 	"time"
 
+	configPb "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	extProcPb "github.com/envoyproxy/go-control-plane/envoy/service/ext_proc/v3"
 	envoyTypePb "github.com/envoyproxy/go-control-plane/envoy/type/v3"
 	"github.com/go-logr/logr"
@@ -90,6 +92,7 @@ type RequestContext struct {
 	ResponseCompleteTimestamp time.Time
 	RequestSize               int
 	Usage                     handlerstypes.Usage
+	ResponseID                string
 	ResponseSize              int
 	ResponseComplete          bool
 	ResponseStatusCode        string
@@ -102,8 +105,40 @@ type RequestContext struct {
 	// extracted from the X-Workload-Context header for workload-aware routing
 	WorkloadContext *datastore.WorkloadContext
 
+	// RequestTTL is how long the request may wait in the Flow Control queues, from the RequestTTLKey header and the
+	// InferenceObjective of the request. Zero if neither sets it.
+	RequestTTL time.Duration
+
+	// ClientCancelled is true when the client disconnected before the request completed.
+	ClientCancelled bool
+
 	RequestState         StreamRequestState
 	modelServerStreaming bool
+	// clientHeaders are the keys of the request headers received from the client. Those that plugins deleted from
+	// Request.Headers are removed from the request forwarded to the model server.
+	clientHeaders []string
+	// modelServerStatus is the HTTP status of an error response of the model server.
+	modelServerStatus int
+	// responseStream decodes the server-sent events of a streamed response across the chunks of its body.
+	responseStream sseDecoder
+	// ResponseChunk is the last received chunk of a streamed response, and ResponseChunkTimestamp when the EPP
+	// received it.
+	ResponseChunk          []byte
+	ResponseChunkTimestamp time.Time
+	// ResponseEvents are the events of a streamed response completed by its last received chunk.
+	ResponseEvents []handlerstypes.StreamEvent
+	// ResponseChunkTokens is the number of tokens generated in the last received chunk of a streamed response.
+	ResponseChunkTokens int
+	// FirstTokenTimestamp and LastTokenTimestamp are when the EPP received the first and the last chunks of a streamed
+	// response carrying generated text.
+	FirstTokenTimestamp time.Time
+	LastTokenTimestamp  time.Time
+	// InterTokenLatency is the time per token between the last received chunk of a streamed response and the
+	// previous chunk carrying generated text. It is zero for the chunk carrying the first token and for chunks carrying
+	// no generated text.
+	InterTokenLatency time.Duration
+	// estimatedOutputTokens is the number of tokens generated so far in a streamed response, counted from its events.
+	estimatedOutputTokens int
 
 	Response *Response
 
@@ -117,10 +152,13 @@ type RequestContext struct {
 }
 
 type Request struct {
-	Headers  map[string]string
-	Body     map[string]any
+	Headers map[string]string
+	// RawBody is the request body as received from the client. It is forwarded to the model server as is, except for
+	// the model value, which is replaced in place when the target model differs.
+	RawBody  []byte
 	Metadata map[string]any
 }
+
 type Response struct {
 	Headers         map[string]string
 	DynamicMetadata *structpb.Struct
@@ -156,7 +194,6 @@ func (s *StreamingServer) Process(srv extProcPb.ExternalProcessor_ProcessServer)
 		RequestState: RequestReceived,
 		Request: &Request{
 			Headers:  make(map[string]string),
-			Body:     make(map[string]any),
 			Metadata: make(map[string]any),
 		},
 		Response: &Response{
@@ -201,12 +238,15 @@ func (s *StreamingServer) Process(srv extProcPb.ExternalProcessor_ProcessServer)
 	for {
 		select {
 		case <-ctx.Done():
+			reqCtx.ClientCancelled = true
 			return ctx.Err()
 		default:
 		}
 
 		req, recvErr := srv.Recv()
 		if recvErr == io.EOF || status.Code(recvErr) == codes.Canceled {
+			// The proxy cancels the stream when the client disconnects.
+			reqCtx.ClientCancelled = status.Code(recvErr) == codes.Canceled || ctx.Err() != nil
 			return nil
 		}
 		if recvErr != nil {
@@ -237,10 +277,9 @@ func (s *StreamingServer) Process(srv extProcPb.ExternalProcessor_ProcessServer)
 
 			// Message is buffered, we can read and decode.
 			if v.RequestBody.EndOfStream {
-				loggerTrace.Info("decoding")
-				if errUnmarshal := json.Unmarshal(body, &reqCtx.Request.Body); errUnmarshal != nil {
+				if !requtil.IsJSONObject(body) {
 					if logger.V(logutil.DEBUG).Enabled() {
-						logger.Info("Error unmarshaling request body", "body", string(body), "err", errUnmarshal)
+						logger.Info("Error unmarshaling request body", "body", string(body))
 					}
 					err = errutil.Error{
 						Code: errutil.BadRequest,
@@ -250,6 +289,7 @@ func (s *StreamingServer) Process(srv extProcPb.ExternalProcessor_ProcessServer)
 				}
 
 				// Body stream complete. Capture raw size for flow control.
+				reqCtx.Request.RawBody = body
 				reqCtx.RequestSize = len(body)
 				body = []byte{}
 
@@ -271,14 +311,9 @@ func (s *StreamingServer) Process(srv extProcPb.ExternalProcessor_ProcessServer)
 					s.datastore.WorkloadHandleDispatchedRequest(reqCtx.WorkloadContext.WorkloadID, waitTime)
 				}
 
-				// Marshal after HandleRequest to include modifications (e.g., model rewriting).
-				var requestBodyBytes []byte
-				requestBodyBytes, err = json.Marshal(reqCtx.Request.Body)
-				if err != nil {
-					logger.V(logutil.DEFAULT).Error(err, "Error marshalling request body")
-					break
-				}
-				// Update RequestSize to match marshalled body for Content-Length header.
+				// The raw body includes the modifications of HandleRequest (e.g., model rewriting).
+				requestBodyBytes := reqCtx.Request.RawBody
+				// Update RequestSize to match the forwarded body for Content-Length header.
 				reqCtx.RequestSize = len(requestBodyBytes)
 				reqCtx.reqHeaderResp = s.generateRequestHeaderResponse(ctx, reqCtx)
 				reqCtx.reqBodyResp = s.generateRequestBodyResponses(requestBodyBytes)
@@ -293,8 +328,10 @@ func (s *StreamingServer) Process(srv extProcPb.ExternalProcessor_ProcessServer)
 				value := string(header.RawValue)
 
 				loggerTrace.Info("header", "key", header.Key, "value", value)
-				if header.Key == "status" && value != "200" {
+				if (header.Key == "status" || header.Key == ":status") && value != "200" {
+					// Refined from the response body once it is received.
 					reqCtx.ResponseStatusCode = errutil.ModelServerError
+					reqCtx.modelServerStatus, _ = strconv.Atoi(value)
 				} else if header.Key == "content-type" && strings.Contains(value, "text/event-stream") {
 					reqCtx.modelServerStreaming = true
 					loggerTrace.Info("model server is streaming response")
@@ -315,10 +352,8 @@ func (s *StreamingServer) Process(srv extProcPb.ExternalProcessor_ProcessServer)
 
 		case *extProcPb.ProcessingRequest_ResponseBody:
 			if reqCtx.modelServerStreaming {
-				// Currently we punt on response parsing if the modelServer is streaming, and we just passthrough.
-
-				responseText := string(v.ResponseBody.Body)
-				s.HandleResponseBodyModelStreaming(ctx, reqCtx, responseText)
+				// The chunks are passed through as they are received, and parsed on the side for their usage.
+				s.HandleResponseBodyModelStreaming(ctx, reqCtx, v.ResponseBody.Body, v.ResponseBody.EndOfStream)
 				if v.ResponseBody.EndOfStream {
 					loggerTrace.Info("stream completed")
 					reqCtx.ResponseComplete = true
@@ -339,6 +374,11 @@ func (s *StreamingServer) Process(srv extProcPb.ExternalProcessor_ProcessServer)
 				// Message is buffered, we can read and decode.
 				if v.ResponseBody.EndOfStream {
 					loggerTrace.Info("stream completed")
+					if reqCtx.ResponseStatusCode != "" {
+						reqCtx.ResponseStatusCode = errutil.ClassifyModelServerError(reqCtx.modelServerStatus, body)
+						logger.V(logutil.DEBUG).Info("Model server returned an error", "status", reqCtx.modelServerStatus,
+							"errorCode", reqCtx.ResponseStatusCode, "body", string(body))
+					}
 					// Don't send a 500 on a response error. Just let the message passthrough and log our error for debugging purposes.
 					// We assume the body is valid JSON, err messages are not guaranteed to be json, and so capturing and sending a 500 obfuscates the response message.
 					// Using the standard 'err' var will send an immediate error response back to the caller.
@@ -467,6 +507,19 @@ func (r *RequestContext) updateStateAndSendIfNeeded(srv extProcPb.ExternalProces
 	return nil
 }
 
+// errorResponseBody is the JSON body of the error responses of the requests rejected or evicted by Flow Control, which
+// tells clients why and for how long to back off.
+type errorResponseBody struct {
+	Error errorResponseDetails `json:"error"`
+}
+
+type errorResponseDetails struct {
+	Code              string `json:"code"`
+	Message           string `json:"message"`
+	RetryAfterSeconds int64  `json:"retryAfterSeconds,omitempty"`
+	*errutil.QueueDetails
+}
+
 func buildErrResponse(err error) (*extProcPb.ProcessingResponse, error) {
 	var resp *extProcPb.ProcessingResponse
 
@@ -483,6 +536,17 @@ func buildErrResponse(err error) (*extProcPb.ProcessingResponse, error) {
 				},
 			},
 		}
+	// This code can be returned by admission plugins when the request exceeds the rate limits of its fairness ID.
+	case errutil.RateLimited:
+		resp = &extProcPb.ProcessingResponse{
+			Response: &extProcPb.ProcessingResponse_ImmediateResponse{
+				ImmediateResponse: &extProcPb.ImmediateResponse{
+					Status: &envoyTypePb.HttpStatus{
+						Code: envoyTypePb.StatusCode_TooManyRequests,
+					},
+				},
+			},
+		}
 	// This code can be returned by when EPP processes the request and run into server-side errors.
 	case errutil.Internal:
 		resp = &extProcPb.ProcessingResponse{
@@ -530,8 +594,42 @@ func buildErrResponse(err error) (*extProcPb.ProcessingResponse, error) {
 		return nil, status.Errorf(status.Code(err), "failed to handle request: %v", err)
 	}
 
-	if err.Error() != "" {
-		resp.Response.(*extProcPb.ProcessingResponse_ImmediateResponse).ImmediateResponse.Body = []byte(err.Error())
+	immediateResponse := resp.Response.(*extProcPb.ProcessingResponse_ImmediateResponse).ImmediateResponse
+	epperr := err.(errutil.Error)
+	// Retry-After is a whole number of seconds, rounded up so that clients do not retry too early.
+	retryAfterSeconds := int64((epperr.RetryAfter + time.Second - 1) / time.Second)
+	var headers []*configPb.HeaderValueOption
+	if retryAfterSeconds > 0 {
+		headers = append(headers, &configPb.HeaderValueOption{
+			Header: &configPb.HeaderValue{
+				Key:      "retry-after",
+				RawValue: []byte(strconv.FormatInt(retryAfterSeconds, 10)),
+			},
+		})
+	}
+
+	if epperr.Queue != nil {
+		body, marshalErr := json.Marshal(errorResponseBody{Error: errorResponseDetails{
+			Code:              epperr.Code,
+			Message:           epperr.Msg,
+			RetryAfterSeconds: retryAfterSeconds,
+			QueueDetails:      epperr.Queue,
+		}})
+		if marshalErr != nil {
+			return nil, status.Errorf(codes.Internal, "failed to marshal error response: %v", marshalErr)
+		}
+		immediateResponse.Body = body
+		headers = append(headers, &configPb.HeaderValueOption{
+			Header: &configPb.HeaderValue{
+				Key:      "content-type",
+				RawValue: []byte("application/json"),
+			},
+		})
+	} else if err.Error() != "" {
+		immediateResponse.Body = []byte(err.Error())
+	}
+	if len(headers) > 0 {
+		immediateResponse.Headers = &extProcPb.HeaderMutation{SetHeaders: headers}
 	}
 
 	return resp, nil
@@ -0,0 +1,104 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handlers
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	envoyTypePb "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	errutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/error"
+)
+
+func TestBuildErrResponse(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		wantStatus     envoyTypePb.StatusCode
+		wantRetryAfter string
+		wantBody       string
+		wantErr        bool
+	}{
+		{
+			name:       "resource exhausted",
+			err:        errutil.Error{Code: errutil.InferencePoolResourceExhausted, Msg: "no capacity"},
+			wantStatus: envoyTypePb.StatusCode_TooManyRequests,
+		},
+		{
+			name:           "rate limited with a retry delay rounded up",
+			err:            errutil.Error{Code: errutil.RateLimited, Msg: "budget exceeded", RetryAfter: 2500 * time.Millisecond},
+			wantStatus:     envoyTypePb.StatusCode_TooManyRequests,
+			wantRetryAfter: "3",
+		},
+		{
+			name: "queue at capacity with a JSON body",
+			err: errutil.Error{
+				Code:       errutil.InferencePoolResourceExhausted,
+				Msg:        "request rejected by flow control",
+				RetryAfter: 6 * time.Second,
+				Queue: &errutil.QueueDetails{
+					Reason: errutil.QueueAtCapacity, PriorityBand: "Critical", Priority: 1, QueueDepth: 60,
+				},
+			},
+			wantStatus:     envoyTypePb.StatusCode_TooManyRequests,
+			wantRetryAfter: "6",
+			wantBody: `{"error":{"code":"InferencePoolResourceExhausted","message":"request rejected by flow control",` +
+				`"retryAfterSeconds":6,"reason":"QueueAtCapacity","priorityBand":"Critical","priority":1,"queueDepth":60}}`,
+		},
+		{
+			name:       "bad request",
+			err:        errutil.Error{Code: errutil.BadRequest, Msg: "invalid body"},
+			wantStatus: envoyTypePb.StatusCode_BadRequest,
+		},
+		{
+			name:    "unknown error",
+			err:     errors.New("unexpected"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := buildErrResponse(tt.err)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			immediateResponse := resp.GetImmediateResponse()
+			assert.Equal(t, tt.wantStatus, immediateResponse.GetStatus().GetCode())
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, string(immediateResponse.GetBody()))
+			} else {
+				assert.Equal(t, tt.err.Error(), string(immediateResponse.GetBody()))
+			}
+
+			headers := map[string]string{}
+			for _, header := range immediateResponse.GetHeaders().GetSetHeaders() {
+				headers[header.GetHeader().GetKey()] = string(header.GetHeader().GetRawValue())
+			}
+			assert.Equal(t, tt.wantRetryAfter, headers["retry-after"])
+			if tt.wantBody != "" {
+				assert.Equal(t, "application/json", headers["content-type"])
+			}
+		})
+	}
+}
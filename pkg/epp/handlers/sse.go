@@ -0,0 +1,137 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handlers
+
+import "bytes"
+
+var utf8BOM = []byte("\xef\xbb\xbf")
+
+// sseEvent is an event decoded from a server-sent events stream.
+type sseEvent struct {
+	// Type is the value of the event field of the event, empty if the event has none.
+	Type string
+	// Data is the data of the event, with the lines of multi-line data joined by newlines.
+	Data string
+}
+
+// sseDecoder incrementally decodes a server-sent events stream, following
+// https://html.spec.whatwg.org/multipage/server-sent-events.html#event-stream-interpretation.
+// The stream may be split into chunks anywhere, including in the middle of a line or of a CRLF line ending.
+type sseDecoder struct {
+	// started is set once the first chunk has been decoded, after which a byte order mark is no longer expected.
+	started bool
+	// line holds the incomplete last line of the chunks decoded so far.
+	line []byte
+	// skipLF is set when the last decoded chunk ended with a carriage return, so that a line feed starting the next
+	// chunk is read as part of the same CRLF line ending.
+	skipLF bool
+
+	eventType string
+	data      []byte
+}
+
+// decode returns the events completed by the chunk, in the order of the stream.
+func (d *sseDecoder) decode(chunk []byte) []sseEvent {
+	if !d.started && len(chunk) > 0 {
+		d.started = true
+		chunk = bytes.TrimPrefix(chunk, utf8BOM)
+	}
+	if d.skipLF && len(chunk) > 0 {
+		d.skipLF = false
+		chunk = bytes.TrimPrefix(chunk, []byte("\n"))
+	}
+
+	var events []sseEvent
+	for len(chunk) > 0 {
+		i := bytes.IndexAny(chunk, "\r\n")
+		if i < 0 {
+			d.line = append(d.line, chunk...)
+			break
+		}
+		line := chunk[:i]
+		if len(d.line) > 0 {
+			d.line = append(d.line, line...)
+			line = d.line
+		}
+		if chunk[i] == '\r' {
+			if i+1 == len(chunk) {
+				d.skipLF = true
+			} else if chunk[i+1] == '\n' {
+				i++
+			}
+		}
+		chunk = chunk[i+1:]
+
+		if event, ok := d.processLine(line); ok {
+			events = append(events, event)
+		}
+		d.line = d.line[:0]
+	}
+	return events
+}
+
+// flush returns the event left incomplete at the end of the stream, if any. Unlike browsers, which discard it, the
+// decoder dispatches it, as some model servers do not end the last event of their streams with a blank line.
+func (d *sseDecoder) flush() []sseEvent {
+	var events []sseEvent
+	if len(d.line) > 0 {
+		if event, ok := d.processLine(d.line); ok {
+			events = append(events, event)
+		}
+		d.line = d.line[:0]
+	}
+	if event, ok := d.dispatch(); ok {
+		events = append(events, event)
+	}
+	return events
+}
+
+// processLine processes a line of the stream, returning the event it completes, if any.
+func (d *sseDecoder) processLine(line []byte) (sseEvent, bool) {
+	if len(line) == 0 {
+		return d.dispatch()
+	}
+	if line[0] == ':' { // comment
+		return sseEvent{}, false
+	}
+
+	field, value, found := bytes.Cut(line, []byte(":"))
+	if found {
+		value = bytes.TrimPrefix(value, []byte(" "))
+	}
+	switch string(field) {
+	case "event":
+		d.eventType = string(value)
+	case "data":
+		d.data = append(d.data, value...)
+		d.data = append(d.data, '\n')
+	}
+	// The id and retry fields are only meaningful to reconnecting clients, and unknown fields are ignored.
+	return sseEvent{}, false
+}
+
+// dispatch returns the event buffered so far, if it has data, and resets the buffers.
+func (d *sseDecoder) dispatch() (sseEvent, bool) {
+	defer func() {
+		d.eventType = ""
+		d.data = d.data[:0]
+	}()
+	if len(d.data) == 0 {
+		return sseEvent{}, false
+	}
+	return sseEvent{Type: d.eventType, Data: string(d.data[:len(d.data)-1])}, true
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handlers
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSSEDecoder(t *testing.T) {
+	tests := []struct {
+		name   string
+		chunks []string
+		want   []sseEvent
+	}{
+		{
+			name:   "data only events",
+			chunks: []string{"data: {\"a\":1}\n\ndata: [DONE]\n\n"},
+			want:   []sseEvent{{Data: `{"a":1}`}, {Data: "[DONE]"}},
+		},
+		{
+			name:   "event split across chunks",
+			chunks: []string{"da", "ta: {\"a\"", ":1}\n", "\n"},
+			want:   []sseEvent{{Data: `{"a":1}`}},
+		},
+		{
+			name:   "event field",
+			chunks: []string{"event: message_stop\ndata: {\"type\":\"message_stop\"}\n\n"},
+			want:   []sseEvent{{Type: "message_stop", Data: `{"type":"message_stop"}`}},
+		},
+		{
+			name:   "multi-line data",
+			chunks: []string{"data: first\ndata:second\ndata\n\n"},
+			want:   []sseEvent{{Data: "first\nsecond\n"}},
+		},
+		{
+			name:   "CRLF line endings split between chunks",
+			chunks: []string{"event: a\r\ndata: 1\r", "\n\r", "\ndata: 2\r\r"},
+			want:   []sseEvent{{Type: "a", Data: "1"}, {Data: "2"}},
+		},
+		{
+			name:   "comments, ids and unknown fields are ignored",
+			chunks: []string{": keep-alive\n\nid: 1\nretry: 10\nfoo: bar\ndata: x\n\n"},
+			want:   []sseEvent{{Data: "x"}},
+		},
+		{
+			name:   "events without data are not dispatched",
+			chunks: []string{"event: ping\n\n\n\ndata: x\n\n"},
+			want:   []sseEvent{{Data: "x"}},
+		},
+		{
+			name:   "byte order mark",
+			chunks: []string{"\xef\xbb\xbfdata: x\n\n"},
+			want:   []sseEvent{{Data: "x"}},
+		},
+		{
+			name:   "incomplete last event is flushed",
+			chunks: []string{"data: {\"a\":1}\n\ndata: [DONE]"},
+			want:   []sseEvent{{Data: `{"a":1}`}, {Data: "[DONE]"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var decoder sseDecoder
+			var got []sseEvent
+			for _, chunk := range test.chunks {
+				got = append(got, decoder.decode([]byte(chunk))...)
+			}
+			got = append(got, decoder.flush()...)
+
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("sseDecoder returned unexpected events, diff(-want, +got): %v", diff)
+			}
+		})
+	}
+}
@@ -16,6 +16,8 @@ limitations under the License.
 
 package types
 
+import "encoding/json"
+
 type Usage struct {
 	PromptTokens       int                 `json:"prompt_tokens"`
 	CompletionTokens   int                 `json:"completion_tokens"`
@@ -26,3 +28,71 @@ type Usage struct {
 type PromptTokenDetails struct {
 	CachedTokens int `json:"cached_tokens"`
 }
+
+// UnmarshalJSON parses the usage of the completions APIs, the usage of the Responses API, which names the prompt
+// and completion tokens input and output tokens, and the usage of the Anthropic Messages API, whose input tokens
+// exclude the tokens read from and written to the prompt cache and which has no total.
+func (u *Usage) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var usage struct {
+		PromptTokens        *int                `json:"prompt_tokens"`
+		CompletionTokens    *int                `json:"completion_tokens"`
+		TotalTokens         int                 `json:"total_tokens"`
+		PromptTokenDetails  *PromptTokenDetails `json:"prompt_token_details"`
+		InputTokens         int                 `json:"input_tokens"`
+		OutputTokens        int                 `json:"output_tokens"`
+		InputTokensDetails  *PromptTokenDetails `json:"input_tokens_details"`
+		CacheReadTokens     *int                `json:"cache_read_input_tokens"`
+		CacheCreationTokens int                 `json:"cache_creation_input_tokens"`
+	}
+	if err := json.Unmarshal(data, &usage); err != nil {
+		return err
+	}
+
+	*u = Usage{
+		PromptTokens:       usage.InputTokens,
+		CompletionTokens:   usage.OutputTokens,
+		TotalTokens:        usage.TotalTokens,
+		PromptTokenDetails: usage.InputTokensDetails,
+	}
+	if usage.PromptTokens != nil {
+		u.PromptTokens = *usage.PromptTokens
+	}
+	if usage.CompletionTokens != nil {
+		u.CompletionTokens = *usage.CompletionTokens
+	}
+	if usage.PromptTokenDetails != nil {
+		u.PromptTokenDetails = usage.PromptTokenDetails
+	}
+	if usage.CacheReadTokens != nil {
+		u.PromptTokens += *usage.CacheReadTokens + usage.CacheCreationTokens
+		u.PromptTokenDetails = &PromptTokenDetails{CachedTokens: *usage.CacheReadTokens}
+	}
+	return nil
+}
+
+// StreamEvent is an event of a streamed (server-sent events) model server response.
+type StreamEvent struct {
+	// Type is the type of the event: its event field, or else the type field of its data. It is empty for the
+	// events of the OpenAI completions and chat completions streams, which only carry data.
+	Type string
+	// Data is the data of the event, with the lines of multi-line data joined by newlines.
+	Data string
+	// Delta is the text generated in the event: the text of the completions and chat completions choices, the delta
+	// of the Responses API and Anthropic Messages API delta events or the text_output of Open Inference Protocol
+	// generate events.
+	Delta string
+	// FinishReason is the reason the model stopped generating, set on the event reporting it: the finish_reason of
+	// the completions APIs, the stop_reason of the Anthropic Messages API or the status of a finished Responses API
+	// response.
+	FinishReason string
+	// Usage is the token usage reported by the event, nil if it reports none. It is a total for the completions APIs
+	// and the Responses API, holds the cumulative output tokens for the message_delta events of the Anthropic Messages
+	// API and the tokens generated since the previous event for Open Inference Protocol generate events.
+	Usage *Usage
+	// End is true for the event ending the stream: the [DONE] event of the completions APIs, the terminal response
+	// events of the Responses API or the message_stop event of the Anthropic Messages API.
+	End bool
+}
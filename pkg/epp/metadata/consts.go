@@ -30,8 +30,13 @@ const (
 	DestinationEndpointServedKey = "x-gateway-destination-endpoint-served"
 	// FlowFairnessIDKey is the header key used to pass the fairness ID to be used in Flow Control.
 	FlowFairnessIDKey = "x-gateway-inference-fairness-id"
+	// DefaultFairnessID is the fairness ID of the requests that do not provide one in the FlowFairnessIDKey header.
+	DefaultFairnessID = "default-flow"
 	// ObjectiveKey is the header key used to specify the objective of an incoming request.
 	ObjectiveKey = "x-gateway-inference-objective"
+	// RequestTTLKey is the header key used by clients to shorten how long their request may wait in the Flow Control
+	// queues, either as a duration (e.g. 30s) or as a number of seconds.
+	RequestTTLKey = "x-gateway-inference-request-ttl"
 	// ModelNameRewriteKey is the header key used to specify the model name to be used when the request is forwarded to the model server.
 	ModelNameRewriteKey = "x-gateway-model-name-rewrite"
 )
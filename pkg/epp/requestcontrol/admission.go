@@ -18,6 +18,7 @@ package requestcontrol
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -27,6 +28,7 @@ import (
 	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/flowcontrol/contracts"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/flowcontrol/types"
+	schedulingtypes "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/scheduling"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/handlers"
 	errutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/error"
 	requtil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/request"
@@ -171,7 +173,9 @@ func (fcac *FlowControlAdmissionController) Admit(
 		requestID:         reqCtx.SchedulingRequest.RequestId,
 		fairnessID:        reqCtx.FairnessID,
 		priority:          priority,
+		ttl:               reqCtx.RequestTTL,
 		requestByteSize:   uint64(reqCtx.RequestSize),
+		estimatedTokens:   estimatedPromptTokens(reqCtx.SchedulingRequest),
 		reqMetadata:       reqCtx.Request.Metadata,
 		inferencePoolName: fcac.poolName,
 		modelName:         reqCtx.IncomingModelName,
@@ -210,7 +214,9 @@ type flowControlRequest struct {
 	requestID         string
 	fairnessID        string
 	priority          int
+	ttl               time.Duration
 	requestByteSize   uint64
+	estimatedTokens   uint64
 	reqMetadata       map[string]any
 	inferencePoolName string
 	modelName         string
@@ -218,11 +224,15 @@ type flowControlRequest struct {
 	workloadContext   types.WorkloadContext
 }
 
-var _ types.FlowControlRequest = &flowControlRequest{}
+var (
+	_ types.FlowControlRequest     = &flowControlRequest{}
+	_ types.TokenEstimatingRequest = &flowControlRequest{}
+)
 
 func (r *flowControlRequest) ID() string                                { return r.requestID }
-func (r *flowControlRequest) InitialEffectiveTTL() time.Duration        { return 0 } // Use controller default.
+func (r *flowControlRequest) InitialEffectiveTTL() time.Duration        { return r.ttl }
 func (r *flowControlRequest) ByteSize() uint64                          { return r.requestByteSize }
+func (r *flowControlRequest) EstimatedTokens() uint64                   { return r.estimatedTokens }
 func (r *flowControlRequest) GetMetadata() map[string]any               { return r.reqMetadata }
 func (r *flowControlRequest) GetWorkloadContext() types.WorkloadContext { return r.workloadContext }
 func (r *flowControlRequest) InferencePoolName() string                 { return r.inferencePoolName }
@@ -232,6 +242,15 @@ func (r *flowControlRequest) FlowKey() types.FlowKey {
 	return types.FlowKey{ID: r.fairnessID, Priority: r.priority}
 }
 
+// estimatedPromptTokens returns the number of prompt tokens of the request: exact if it is sent as token IDs, and
+// otherwise estimated from the length of its plain text.
+func estimatedPromptTokens(request *schedulingtypes.LLMRequest) uint64 {
+	if request == nil {
+		return 0
+	}
+	return uint64(request.Body.EstimatedPromptTokens())
+}
+
 // translateFlowControlOutcome maps the context-rich outcome of the Flow Control layer to the public errutil.Error
 // contract used by the Director.
 func translateFlowControlOutcome(outcome types.QueueOutcome, err error) error {
@@ -244,9 +263,14 @@ func translateFlowControlOutcome(outcome types.QueueOutcome, err error) error {
 	case types.QueueOutcomeDispatched:
 		return nil
 	case types.QueueOutcomeRejectedCapacity:
-		return errutil.Error{Code: errutil.InferencePoolResourceExhausted, Msg: msg}
+		return withQueueState(errutil.Error{Code: errutil.InferencePoolResourceExhausted, Msg: msg},
+			errutil.QueueAtCapacity, err)
 	case types.QueueOutcomeEvictedTTL:
-		return errutil.Error{Code: errutil.ServiceUnavailable, Msg: "request timed out in queue: " + msg}
+		return withQueueState(errutil.Error{Code: errutil.ServiceUnavailable, Msg: "request timed out in queue: " + msg},
+			errutil.QueueTimeout, err)
+	case types.QueueOutcomeEvictedPreempted:
+		return withQueueState(errutil.Error{Code: errutil.ServiceUnavailable, Msg: "request preempted in queue: " + msg},
+			errutil.QueuePreempted, err)
 	case types.QueueOutcomeEvictedContextCancelled:
 		return errutil.Error{Code: errutil.ServiceUnavailable, Msg: "client disconnected: " + msg}
 	case types.QueueOutcomeRejectedOther, types.QueueOutcomeEvictedOther:
@@ -255,3 +279,21 @@ func translateFlowControlOutcome(outcome types.QueueOutcome, err error) error {
 		return errutil.Error{Code: errutil.Internal, Msg: "unhandled flow control outcome: " + msg}
 	}
 }
+
+// withQueueState adds the state of the queue of the request reported by the Flow Control layer, if any, to the error
+// returned to the client, so that it knows how long to back off for.
+func withQueueState(epperr errutil.Error, reason string, err error) errutil.Error {
+	var queueStateErr *types.QueueStateError
+	if !errors.As(err, &queueStateErr) {
+		return epperr
+	}
+	state := queueStateErr.State
+	epperr.RetryAfter = state.EstimatedWait
+	epperr.Queue = &errutil.QueueDetails{
+		Reason:       reason,
+		PriorityBand: state.PriorityName,
+		Priority:     state.Priority,
+		QueueDepth:   state.QueueDepth,
+	}
+	return epperr
+}
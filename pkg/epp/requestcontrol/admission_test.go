@@ -20,6 +20,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -142,6 +143,7 @@ func TestFlowControlRequestAdapter(t *testing.T) {
 		fairnessID      string
 		priority        int
 		requestByteSize uint64
+		ttl             time.Duration
 		expectFlowKey   fctypes.FlowKey
 	}{
 		{
@@ -152,6 +154,15 @@ func TestFlowControlRequestAdapter(t *testing.T) {
 			requestByteSize: 1024,
 			expectFlowKey:   fctypes.FlowKey{ID: "flow-1", Priority: 10},
 		},
+		{
+			name:            "with_ttl",
+			requestID:       "req-2",
+			fairnessID:      "flow-1",
+			priority:        10,
+			requestByteSize: 1024,
+			ttl:             45 * time.Second,
+			expectFlowKey:   fctypes.FlowKey{ID: "flow-1", Priority: 10},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -162,12 +173,47 @@ func TestFlowControlRequestAdapter(t *testing.T) {
 				fairnessID:      tc.fairnessID,
 				priority:        tc.priority,
 				requestByteSize: tc.requestByteSize,
+				ttl:             tc.ttl,
 			}
 
 			assert.Equal(t, tc.requestID, fcReq.ID(), "ID() mismatch")
 			assert.Equal(t, tc.requestByteSize, fcReq.ByteSize(), "ByteSize() mismatch")
 			assert.Equal(t, tc.expectFlowKey, fcReq.FlowKey(), "FlowKey() mismatch")
-			assert.Zero(t, fcReq.InitialEffectiveTTL(), "InitialEffectiveTTL() should be zero")
+			assert.Equal(t, tc.ttl, fcReq.InitialEffectiveTTL(), "InitialEffectiveTTL() mismatch")
+		})
+	}
+}
+
+func TestEstimatedPromptTokens(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		request  *schedulingtypes.LLMRequest
+		expected uint64
+	}{
+		{name: "nil request", request: nil, expected: 0},
+		{name: "no body", request: &schedulingtypes.LLMRequest{}, expected: 0},
+		{
+			name: "plain text prompt",
+			request: &schedulingtypes.LLMRequest{Body: &schedulingtypes.LLMRequestBody{
+				Completions: &schedulingtypes.CompletionsRequest{Prompt: schedulingtypes.Prompt{Raw: "0123456789abcdef"}},
+			}},
+			expected: 4,
+		},
+		{
+			name: "token IDs prompt",
+			request: &schedulingtypes.LLMRequest{Body: &schedulingtypes.LLMRequestBody{
+				Completions: &schedulingtypes.CompletionsRequest{Prompt: schedulingtypes.Prompt{Tokens: [][]uint32{{1, 2, 3}}}},
+			}},
+			expected: 3,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expected, estimatedPromptTokens(tc.request))
 		})
 	}
 }
@@ -190,6 +236,8 @@ func TestFlowControlAdmissionController_Admit(t *testing.T) {
 		expectErr       bool
 		expectErrCode   string
 		expectErrSubstr string
+		expectQueue     *errutil.QueueDetails
+		expectRetry     time.Duration
 	}{
 		{
 			name:      "sheddable_dispatched",
@@ -220,6 +268,53 @@ func TestFlowControlAdmissionController_Admit(t *testing.T) {
 			expectErrCode:   errutil.ServiceUnavailable,
 			expectErrSubstr: "request timed out in queue: timeout",
 		},
+		{
+			name:      "fc_reject_capacity_with_queue_state",
+			priority:  0,
+			fcOutcome: fctypes.QueueOutcomeRejectedCapacity,
+			fcErr: &fctypes.QueueStateError{
+				Err:   fctypes.ErrQueueAtCapacity,
+				State: fctypes.QueueState{Priority: 0, PriorityName: "Critical", QueueDepth: 60, EstimatedWait: 6 * time.Second},
+			},
+			expectErr:     true,
+			expectErrCode: errutil.InferencePoolResourceExhausted,
+			expectQueue: &errutil.QueueDetails{
+				Reason: errutil.QueueAtCapacity, PriorityBand: "Critical", Priority: 0, QueueDepth: 60,
+			},
+			expectRetry: 6 * time.Second,
+		},
+		{
+			name:      "fc_evict_ttl_with_queue_state",
+			priority:  0,
+			fcOutcome: fctypes.QueueOutcomeEvictedTTL,
+			fcErr: &fctypes.QueueStateError{
+				Err:   fctypes.ErrTTLExpired,
+				State: fctypes.QueueState{Priority: 0, PriorityName: "Critical", QueueDepth: 12, EstimatedWait: time.Minute},
+			},
+			expectErr:       true,
+			expectErrCode:   errutil.ServiceUnavailable,
+			expectErrSubstr: "request timed out in queue",
+			expectQueue: &errutil.QueueDetails{
+				Reason: errutil.QueueTimeout, PriorityBand: "Critical", Priority: 0, QueueDepth: 12,
+			},
+			expectRetry: time.Minute,
+		},
+		{
+			name:      "fc_evict_preempted",
+			priority:  -1,
+			fcOutcome: fctypes.QueueOutcomeEvictedPreempted,
+			fcErr: &fctypes.QueueStateError{
+				Err:   fctypes.ErrPreempted,
+				State: fctypes.QueueState{Priority: -1, PriorityName: "Sheddable", QueueDepth: 40, EstimatedWait: 8 * time.Second},
+			},
+			expectErr:       true,
+			expectErrCode:   errutil.ServiceUnavailable,
+			expectErrSubstr: "request preempted in queue",
+			expectQueue: &errutil.QueueDetails{
+				Reason: errutil.QueuePreempted, PriorityBand: "Sheddable", Priority: -1, QueueDepth: 40,
+			},
+			expectRetry: 8 * time.Second,
+		},
 		{
 			name:            "fc_evict_context_cancelled",
 			priority:        0,
@@ -273,6 +368,8 @@ func TestFlowControlAdmissionController_Admit(t *testing.T) {
 				if assert.ErrorAs(t, err, &e, "error should be of type errutil.Error") {
 					assert.Equal(t, tc.expectErrCode, e.Code, "incorrect error code for scenario: %s", tc.name)
 					assert.Contains(t, e.Msg, tc.expectErrSubstr, "incorrect error message substring for scenario: %s", tc.name)
+					assert.Equal(t, tc.expectQueue, e.Queue, "incorrect queue details for scenario: %s", tc.name)
+					assert.Equal(t, tc.expectRetry, e.RetryAfter, "incorrect retry delay for scenario: %s", tc.name)
 				}
 			}
 		})
@@ -20,6 +20,7 @@ package requestcontrol
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/rand"
 	"net"
@@ -36,6 +37,7 @@ import (
 	fwk "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/requestcontrol"
 	schedulingtypes "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/scheduling"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/handlers"
+	handlerstypes "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/handlers/types"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metrics"
 	errutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/error"
 	requtil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/request"
@@ -119,17 +121,39 @@ func (d *Director) getInferenceObjective(ctx context.Context, reqCtx *handlers.R
 	return infObjective
 }
 
+// requestTTL returns how long a request may wait in the Flow Control queues: the TTL of its InferenceObjective, or
+// the default TTL if it sets none, shortened by the TTL requested by the client, if any. Zero leaves the TTL to the
+// Flow Control defaults.
+func requestTTL(infObjective *v1alpha2.InferenceObjective, clientTTL, defaultTTL time.Duration) time.Duration {
+	if infObjective.Spec.RequestTTL == nil || infObjective.Spec.RequestTTL.Duration <= 0 {
+		if defaultTTL > 0 && clientTTL > defaultTTL {
+			return defaultTTL
+		}
+		return clientTTL
+	}
+	objectiveTTL := infObjective.Spec.RequestTTL.Duration
+	if clientTTL > 0 && clientTTL < objectiveTTL {
+		return clientTTL
+	}
+	return objectiveTTL
+}
+
 // HandleRequest orchestrates the request lifecycle.
 // It always returns the requestContext even in the error case, as the request context is used in error handling.
 func (d *Director) HandleRequest(ctx context.Context, reqCtx *handlers.RequestContext) (*handlers.RequestContext, error) {
 	logger := log.FromContext(ctx)
 
 	// Parse Request, Resolve Target Models, and Determine Parameters
-	requestBodyMap := reqCtx.Request.Body
+	path := reqCtx.Request.Headers[requtil.PathHeaderKey]
 	var ok bool
-	reqCtx.IncomingModelName, ok = requestBodyMap["model"].(string)
+	reqCtx.IncomingModelName, ok = requtil.BodyModel(reqCtx.Request.RawBody)
+	// Open Inference Protocol requests carry the model name in the path (/v2/models/{name}/infer) instead of the body.
+	modelInPath := !ok && requtil.ModelFromPath(path) != ""
+	if modelInPath {
+		reqCtx.IncomingModelName = requtil.ModelFromPath(path)
+	}
 
-	if !ok {
+	if !ok && !modelInPath {
 		return reqCtx, errutil.Error{Code: errutil.BadRequest, Msg: "model not found in request body"}
 	}
 	if reqCtx.TargetModelName == "" {
@@ -139,15 +163,25 @@ func (d *Director) HandleRequest(ctx context.Context, reqCtx *handlers.RequestCo
 
 	d.applyWeightedModelRewrite(reqCtx)
 
-	reqCtx.Request.Body["model"] = reqCtx.TargetModelName
+	if modelInPath {
+		// The body must be left untouched, as the generate extension treats its top-level fields as model inputs.
+		reqCtx.Request.Headers[requtil.PathHeaderKey] = requtil.ReplaceModelInPath(path, reqCtx.TargetModelName)
+	} else if reqCtx.TargetModelName != reqCtx.IncomingModelName {
+		rawBody, err := requtil.ReplaceBodyModel(reqCtx.Request.RawBody, reqCtx.TargetModelName)
+		if err != nil {
+			return reqCtx, errutil.Error{Code: errutil.BadRequest, Msg: fmt.Errorf("failed to rewrite model: %w", err).Error()}
+		}
+		reqCtx.Request.RawBody = rawBody
+	}
 
-	requestBody, err := requtil.ExtractRequestBody(reqCtx.Request.Body)
+	requestBody, err := requtil.ExtractRequestBody(reqCtx.Request.RawBody, path)
 	if err != nil {
 		return reqCtx, errutil.Error{Code: errutil.BadRequest, Msg: fmt.Errorf("failed to extract request data: %w", err).Error()}
 	}
 
 	// Parse inference objective.
 	infObjective := d.getInferenceObjective(ctx, reqCtx)
+	reqCtx.RequestTTL = requestTTL(infObjective, reqCtx.RequestTTL, d.requestControlPlugins.defaultRequestTTL)
 
 	// Prepare LLMRequest (needed for both saturation detection and Scheduler)
 	reqCtx.SchedulingRequest = &schedulingtypes.LLMRequest{
@@ -157,11 +191,24 @@ func (d *Director) HandleRequest(ctx context.Context, reqCtx *handlers.RequestCo
 		Headers:     reqCtx.Request.Headers,
 	}
 
-	logger = logger.WithValues("objectiveKey", reqCtx.ObjectiveKey, "incomingModelName", reqCtx.IncomingModelName, "targetModelName", reqCtx.TargetModelName, "priority", infObjective.Spec.Priority)
+	logger = logger.WithValues("objectiveKey", reqCtx.ObjectiveKey, "incomingModelName", reqCtx.IncomingModelName, "targetModelName", reqCtx.TargetModelName, "priority", infObjective.Spec.Priority, "requestTTL", reqCtx.RequestTTL)
 
 	ctx = log.IntoContext(ctx, logger)
 	logger.V(logutil.DEBUG).Info("LLM request assembled")
 
+	// Rate limit the request before it waits in the Flow Control queues, and refund the rate limiters if it is rejected
+	// afterwards.
+	if err := d.runRateLimiters(ctx, reqCtx.SchedulingRequest); err != nil {
+		logger.V(logutil.DEFAULT).Info("Request rate limited", "reason", err.Error())
+		return reqCtx, denialError(err, "request rate limited")
+	}
+	admitted := false
+	defer func() {
+		if !admitted {
+			d.runRateLimiterRefunds(ctx, reqCtx.SchedulingRequest)
+		}
+	}()
+
 	if err := d.admissionController.Admit(ctx, reqCtx, *infObjective.Spec.Priority); err != nil {
 		logger.V(logutil.DEFAULT).Info("Request rejected by admission control", "error", err)
 		return reqCtx, err
@@ -182,9 +229,9 @@ func (d *Director) HandleRequest(ctx context.Context, reqCtx *handlers.RequestCo
 	}
 
 	// Run admit request plugins
-	if !d.runAdmissionPlugins(ctx, reqCtx.SchedulingRequest, snapshotOfCandidatePods) {
-		logger.V(logutil.DEFAULT).Info("Request cannot be admitted")
-		return reqCtx, errutil.Error{Code: errutil.Internal, Msg: "request cannot be admitted"}
+	if err := d.runAdmissionPlugins(ctx, reqCtx.SchedulingRequest, snapshotOfCandidatePods); err != nil {
+		logger.V(logutil.DEFAULT).Info("Request cannot be admitted", "reason", err.Error())
+		return reqCtx, denialError(err, "request cannot be admitted")
 	}
 
 	result, err := d.scheduler.Schedule(ctx, reqCtx.SchedulingRequest, snapshotOfCandidatePods)
@@ -200,9 +247,20 @@ func (d *Director) HandleRequest(ctx context.Context, reqCtx *handlers.RequestCo
 		return reqCtx, err
 	}
 
+	admitted = true
 	return reqCtx, nil
 }
 
+// denialError returns the error sent to the client for a request denied by a plugin. Plugins denying the request with
+// an errutil.Error decide the response, other denial reasons are internal errors.
+func denialError(denyReason error, msg string) error {
+	var denial errutil.Error
+	if errors.As(denyReason, &denial) {
+		return denial
+	}
+	return errutil.Error{Code: errutil.Internal, Msg: msg}
+}
+
 func (d *Director) applyWeightedModelRewrite(reqCtx *handlers.RequestContext) {
 	rewriteRule, modelRewriteName := d.datastore.ModelRewriteGet(reqCtx.IncomingModelName)
 	if rewriteRule == nil {
@@ -300,10 +358,22 @@ func (d *Director) HandleResponseReceived(ctx context.Context, reqCtx *handlers.
 func (d *Director) HandleResponseBodyStreaming(ctx context.Context, reqCtx *handlers.RequestContext) (*handlers.RequestContext, error) {
 	logger := log.FromContext(ctx).WithValues("stage", "bodyChunk")
 	logger.V(logutil.TRACE).Info("Entering HandleResponseBodyChunk")
+	outputTokens, estimated := reqCtx.OutputTokens()
 	response := &fwk.Response{
-		RequestId:   reqCtx.Request.Headers[requtil.RequestIdHeaderKey],
-		Headers:     reqCtx.Response.Headers,
-		EndOfStream: reqCtx.ResponseComplete,
+		RequestId:             reqCtx.Request.Headers[requtil.RequestIdHeaderKey],
+		Headers:               reqCtx.Response.Headers,
+		Body:                  string(reqCtx.ResponseChunk),
+		IsStreaming:           true,
+		EndOfStream:           reqCtx.ResponseComplete,
+		Usage:                 reqCtx.Usage,
+		Events:                reqCtx.ResponseEvents,
+		Content:               streamContent(reqCtx.ResponseEvents),
+		ChunkTokens:           reqCtx.ResponseChunkTokens,
+		OutputTokens:          outputTokens,
+		OutputTokensEstimated: estimated,
+		ReceivedTimestamp:     reqCtx.ResponseChunkTimestamp,
+		FirstTokenTimestamp:   reqCtx.FirstTokenTimestamp,
+		InterTokenLatency:     reqCtx.InterTokenLatency,
 	}
 
 	d.runResponseStreamingPlugins(ctx, reqCtx.SchedulingRequest, response, reqCtx.TargetPod)
@@ -311,6 +381,15 @@ func (d *Director) HandleResponseBodyStreaming(ctx context.Context, reqCtx *hand
 	return reqCtx, nil
 }
 
+// streamContent returns the text generated in the events of a chunk of a streamed response.
+func streamContent(events []handlerstypes.StreamEvent) string {
+	var content strings.Builder
+	for _, event := range events {
+		content.WriteString(event.Delta)
+	}
+	return content.String()
+}
+
 // HandleResponseBodyComplete is called when the response body is fully received.
 func (d *Director) HandleResponseBodyComplete(ctx context.Context, reqCtx *handlers.RequestContext) (*handlers.RequestContext, error) {
 	logger := log.FromContext(ctx).WithValues("stage", "bodyChunk")
@@ -320,6 +399,10 @@ func (d *Director) HandleResponseBodyComplete(ctx context.Context, reqCtx *handl
 		Headers:         reqCtx.Response.Headers,
 		DynamicMetadata: reqCtx.Response.DynamicMetadata,
 		Usage:           reqCtx.Usage,
+		ResponseID:      reqCtx.ResponseID,
+		ErrorCode:       reqCtx.ResponseStatusCode,
+		Incomplete:      !reqCtx.ResponseComplete,
+		ClientCancelled: reqCtx.ClientCancelled,
 	}
 
 	d.runResponseCompletePlugins(ctx, reqCtx.SchedulingRequest, response, reqCtx.TargetPod)
@@ -358,18 +441,47 @@ func (d *Director) runPrepareDataPlugins(ctx context.Context,
 	return prepareDataPluginsWithTimeout(prepareDataTimeout, d.requestControlPlugins.prepareDataPlugins, ctx, request, endpoints)
 }
 
+// runAdmissionPlugins returns the denial reason of the first plugin denying the request, or nil if all of them
+// admit it.
 func (d *Director) runAdmissionPlugins(ctx context.Context,
-	request *schedulingtypes.LLMRequest, endpoints []schedulingtypes.Endpoint) bool {
+	request *schedulingtypes.LLMRequest, endpoints []schedulingtypes.Endpoint) error {
 	loggerDebug := log.FromContext(ctx).V(logutil.DEBUG)
 	for _, plugin := range d.requestControlPlugins.admissionPlugins {
 		loggerDebug.Info("Running AdmitRequest plugin", "plugin", plugin.TypedName())
 		if denyReason := plugin.AdmitRequest(ctx, request, endpoints); denyReason != nil {
 			loggerDebug.Info("AdmitRequest plugin denied the request", "plugin", plugin.TypedName(), "reason", denyReason.Error())
-			return false
+			return denyReason
 		}
 		loggerDebug.Info("Completed running AdmitRequest plugin successfully", "plugin", plugin.TypedName())
 	}
-	return true
+	return nil
+}
+
+// runRateLimiters returns the denial reason of the first rate limiter denying the request, or nil if all of them
+// allow it. The rate limiters that allowed the request before one denied it are refunded.
+func (d *Director) runRateLimiters(ctx context.Context, request *schedulingtypes.LLMRequest) error {
+	loggerDebug := log.FromContext(ctx).V(logutil.DEBUG)
+	for i, plugin := range d.requestControlPlugins.rateLimiters {
+		loggerDebug.Info("Running RateLimiter plugin", "plugin", plugin.TypedName())
+		if denyReason := plugin.LimitRequest(ctx, request); denyReason != nil {
+			loggerDebug.Info("RateLimiter plugin denied the request", "plugin", plugin.TypedName(), "reason", denyReason.Error())
+			for _, charged := range d.requestControlPlugins.rateLimiters[:i] {
+				charged.RequestRejected(ctx, request)
+			}
+			return denyReason
+		}
+		loggerDebug.Info("Completed running RateLimiter plugin successfully", "plugin", plugin.TypedName())
+	}
+	return nil
+}
+
+// runRateLimiterRefunds refunds the charge of a request rejected after the rate limiters allowed it.
+func (d *Director) runRateLimiterRefunds(ctx context.Context, request *schedulingtypes.LLMRequest) {
+	loggerDebug := log.FromContext(ctx).V(logutil.DEBUG)
+	for _, plugin := range d.requestControlPlugins.rateLimiters {
+		loggerDebug.Info("Refunding RateLimiter plugin", "plugin", plugin.TypedName())
+		plugin.RequestRejected(ctx, request)
+	}
 }
 
 func (d *Director) runResponseReceivedPlugins(ctx context.Context, request *schedulingtypes.LLMRequest, response *fwk.Response, targetEndpoint *datalayer.EndpointMetadata) {
@@ -18,10 +18,11 @@ package requestcontrol
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
-	"maps"
 	"sort"
+	"strings"
 	"testing"
 	"time"
 
@@ -45,6 +46,7 @@ import (
 	fwk "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/requestcontrol"
 	schedulingtypes "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/scheduling"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/handlers"
+	handlerstypes "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/handlers/types"
 	errutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/error"
 	poolutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/pool"
 	requtil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/request"
@@ -154,6 +156,31 @@ func (m *mockAdmissionPlugin) AdmitRequest(ctx context.Context, request *schedul
 	return m.denialError
 }
 
+type mockRateLimiter struct {
+	typedName   fwkplugin.TypedName
+	denialError error
+	refunded    bool
+}
+
+func newMockRateLimiter(name string, denialError error) *mockRateLimiter {
+	return &mockRateLimiter{
+		typedName:   fwkplugin.TypedName{Type: "mock-rate-limiter", Name: name},
+		denialError: denialError,
+	}
+}
+
+func (m *mockRateLimiter) TypedName() fwkplugin.TypedName {
+	return m.typedName
+}
+
+func (m *mockRateLimiter) LimitRequest(ctx context.Context, request *schedulingtypes.LLMRequest) error {
+	return m.denialError
+}
+
+func (m *mockRateLimiter) RequestRejected(ctx context.Context, request *schedulingtypes.LLMRequest) {
+	m.refunded = true
+}
+
 type mockProducedDataType struct {
 	value int
 }
@@ -306,15 +333,19 @@ func TestDirector_HandleRequest(t *testing.T) {
 	tests := []struct {
 		name                    string
 		reqBodyMap              map[string]any
+		path                    string // Request path, sent in the :path header if set.
 		mockAdmissionController *mockAdmissionController
 		inferenceObjectiveName  string
 		schedulerMockSetup      func(m *mockScheduler)
 		initialTargetModelName  string                   // Initial target model in the reqCtx.
 		wantErrCode             string                   // Expected errutil code string
 		wantReqCtx              *handlers.RequestContext // Fields to check in the returned RequestContext
-		wantMutatedBodyModel    string                   // Expected model in reqCtx.Request.Body after PostDispatch
+		wantMutatedBodyModel    string                   // Expected model in reqCtx.Request.RawBody after PostDispatch
+		wantPath                string                   // Expected :path header, if the model is carried by the path
 		targetModelName         string                   // Expected model name after target model resolution
 		admitRequestDenialError error                    // Expected denial error from admission plugin
+		rateLimitDenialError    error                    // Denial error of the rate limiter
+		wantRateLimitRefunded   bool                     // Whether the rate limiter is refunded
 		prepareDataPlugin       *mockPrepareDataPlugin
 	}{
 		{
@@ -365,6 +396,29 @@ func TestDirector_HandleRequest(t *testing.T) {
 			},
 			wantMutatedBodyModel:   modelRewritten,
 			inferenceObjectiveName: model,
+		}, {
+			name: "successful open inference request with model rewrite",
+			reqBodyMap: map[string]any{
+				"inputs": []any{map[string]any{"name": "text_input", "shape": []any{1}, "datatype": "BYTES", "data": []any{"some prompt"}}},
+			},
+			path:                    "/v2/models/" + modelToBeRewritten + "/infer",
+			mockAdmissionController: &mockAdmissionController{admitErr: nil},
+			schedulerMockSetup: func(m *mockScheduler) {
+				m.scheduleResults = defaultSuccessfulScheduleResults
+			},
+			wantReqCtx: &handlers.RequestContext{
+				ObjectiveKey:    model,
+				TargetModelName: modelRewritten,
+				TargetPod: &datalayer.EndpointMetadata{
+					NamespacedName: types.NamespacedName{Namespace: "default", Name: "pod1"},
+					Address:        "192.168.1.100",
+					Port:           "8000",
+					MetricsHost:    "192.168.1.100:8000",
+				},
+				TargetEndpoint: "192.168.1.100:8000,192.168.2.100:8000,192.168.4.100:8000",
+			},
+			wantPath:               "/v2/models/" + modelRewritten + "/infer",
+			inferenceObjectiveName: model,
 		}, {
 			name: "successful chat completions request",
 			reqBodyMap: map[string]any{
@@ -472,6 +526,33 @@ func TestDirector_HandleRequest(t *testing.T) {
 			targetModelName:         model,
 			admitRequestDenialError: errors.New("denied by admit plugin"),
 			wantErrCode:             errutil.Internal,
+			wantRateLimitRefunded:   true,
+		},
+		{
+			name: "denied request by admit request plugin with a canonical error",
+			reqBodyMap: map[string]any{
+				"model":  model,
+				"prompt": "critical prompt",
+			},
+			mockAdmissionController: &mockAdmissionController{admitErr: nil},
+			schedulerMockSetup: func(m *mockScheduler) {
+				m.scheduleResults = defaultSuccessfulScheduleResults
+			},
+			wantMutatedBodyModel:    model,
+			targetModelName:         model,
+			admitRequestDenialError: errutil.Error{Code: errutil.RateLimited, Msg: "rate limited", RetryAfter: time.Second},
+			wantErrCode:             errutil.RateLimited,
+			wantRateLimitRefunded:   true,
+		},
+		{
+			name: "denied request by rate limiter before admission control",
+			reqBodyMap: map[string]any{
+				"model":  model,
+				"prompt": "critical prompt",
+			},
+			mockAdmissionController: &mockAdmissionController{admitErr: errutil.Error{Code: errutil.InferencePoolResourceExhausted, Msg: "simulated admission rejection"}},
+			rateLimitDenialError:    errutil.Error{Code: errutil.RateLimited, Msg: "rate limited", RetryAfter: time.Second},
+			wantErrCode:             errutil.RateLimited,
 		},
 		{
 			name: "successful chat completions request with multiple messages",
@@ -564,6 +645,7 @@ func TestDirector_HandleRequest(t *testing.T) {
 			inferenceObjectiveName:  objectiveNameSheddable,
 			mockAdmissionController: &mockAdmissionController{admitErr: errutil.Error{Code: errutil.InferencePoolResourceExhausted, Msg: "simulated admission rejection"}},
 			wantErrCode:             errutil.InferencePoolResourceExhausted,
+			wantRateLimitRefunded:   true,
 		},
 		{
 			name:                    "model not found, expect err",
@@ -596,6 +678,7 @@ func TestDirector_HandleRequest(t *testing.T) {
 			},
 			wantErrCode:            errutil.InferencePoolResourceExhausted,
 			inferenceObjectiveName: objectiveName,
+			wantRateLimitRefunded:  true,
 		},
 		{
 			name: "scheduler returns nil result and nil error",
@@ -610,6 +693,7 @@ func TestDirector_HandleRequest(t *testing.T) {
 			},
 			wantErrCode:            errutil.Internal,
 			inferenceObjectiveName: objectiveName,
+			wantRateLimitRefunded:  true,
 		},
 	}
 
@@ -662,10 +746,12 @@ func TestDirector_HandleRequest(t *testing.T) {
 					config = config.WithPrepareDataPlugins(test.prepareDataPlugin)
 				}
 				config = config.WithAdmissionPlugins(newMockAdmissionPlugin("test-admit-plugin", test.admitRequestDenialError))
+				rateLimiter := newMockRateLimiter("test-rate-limiter", test.rateLimitDenialError)
+				config = config.WithRateLimiters(rateLimiter)
 
 				locator := NewCachedPodLocator(context.Background(), NewDatastorePodLocator(ds), time.Minute)
 				director := NewDirectorWithConfig(ds, mockSched, test.mockAdmissionController, locator, config)
-				if test.name == "successful request with model rewrite" {
+				if strings.HasSuffix(test.name, "with model rewrite") {
 					mockDs := &mockDatastore{
 						pods:     ds.PodList(datastore.AllPodsPredicate),
 						rewrites: []*v1alpha2.InferenceModelRewrite{rewrite},
@@ -674,10 +760,11 @@ func TestDirector_HandleRequest(t *testing.T) {
 					director.podLocator = NewCachedPodLocator(context.Background(), NewDatastorePodLocator(mockDs), time.Minute)
 				}
 
+				rawBody, err := json.Marshal(test.reqBodyMap)
+				assert.NoError(t, err, "failed to marshal the request body")
 				reqCtx := &handlers.RequestContext{
 					Request: &handlers.Request{
-						// Create a copy of the map for each test run to avoid mutation issues.
-						Body: make(map[string]any),
+						RawBody: rawBody,
 						Headers: map[string]string{
 							requtil.RequestIdHeaderKey: "test-req-id-" + test.name, // Ensure a default request ID
 						},
@@ -685,10 +772,12 @@ func TestDirector_HandleRequest(t *testing.T) {
 					ObjectiveKey:    test.inferenceObjectiveName,
 					TargetModelName: test.initialTargetModelName,
 				}
-				// Deep copy the body map.
-				maps.Copy(reqCtx.Request.Body, test.reqBodyMap)
+				if test.path != "" {
+					reqCtx.Request.Headers[requtil.PathHeaderKey] = test.path
+				}
 
 				returnedReqCtx, err := director.HandleRequest(ctx, reqCtx)
+				assert.Equal(t, test.wantRateLimitRefunded, rateLimiter.refunded, "Rate limiter refund mismatch")
 
 				if test.wantErrCode != "" {
 					assert.Error(t, err, "HandleRequest() should have returned an error")
@@ -710,9 +799,13 @@ func TestDirector_HandleRequest(t *testing.T) {
 				}
 
 				if test.wantMutatedBodyModel != "" {
-					assert.NotNil(t, returnedReqCtx.Request.Body, "Expected mutated body, but reqCtx.Request.Body is nil")
-					assert.Equal(t, test.wantMutatedBodyModel, returnedReqCtx.Request.Body["model"],
-						"Mutated reqCtx.Request.Body model mismatch")
+					model, ok := requtil.BodyModel(returnedReqCtx.Request.RawBody)
+					assert.True(t, ok, "Expected mutated body, but reqCtx.Request.RawBody has no model")
+					assert.Equal(t, test.wantMutatedBodyModel, model, "Mutated reqCtx.Request.RawBody model mismatch")
+				}
+				if test.wantPath != "" {
+					assert.Equal(t, test.wantPath, returnedReqCtx.Request.Headers[requtil.PathHeaderKey], ":path header mismatch")
+					assert.Equal(t, rawBody, returnedReqCtx.Request.RawBody, "A body that doesn't carry the model must be left untouched")
 				}
 			})
 		}
@@ -1055,6 +1148,53 @@ func TestDirector_SelectWeightedModel(t *testing.T) {
 	}
 }
 
+func TestRequestTTL(t *testing.T) {
+	tests := []struct {
+		name         string
+		objectiveTTL *metav1.Duration
+		clientTTL    time.Duration
+		defaultTTL   time.Duration
+		want         time.Duration
+	}{
+		{name: "neither", defaultTTL: time.Minute, want: 0},
+		{name: "objective only", objectiveTTL: &metav1.Duration{Duration: time.Minute}, want: time.Minute},
+		{name: "client only", clientTTL: 10 * time.Second, defaultTTL: time.Minute, want: 10 * time.Second},
+		{name: "client only without default", clientTTL: time.Hour, want: time.Hour},
+		{
+			name:       "client cannot extend the default",
+			clientTTL:  time.Hour,
+			defaultTTL: time.Minute,
+			want:       time.Minute,
+		},
+		{
+			name:         "objective overrides the default",
+			objectiveTTL: &metav1.Duration{Duration: time.Hour},
+			clientTTL:    2 * time.Hour,
+			defaultTTL:   time.Minute,
+			want:         time.Hour,
+		},
+		{
+			name:         "client shortens the objective",
+			objectiveTTL: &metav1.Duration{Duration: time.Minute},
+			clientTTL:    10 * time.Second,
+			want:         10 * time.Second,
+		},
+		{
+			name:         "client cannot extend the objective",
+			objectiveTTL: &metav1.Duration{Duration: time.Minute},
+			clientTTL:    time.Hour,
+			want:         time.Minute,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			infObjective := &v1alpha2.InferenceObjective{Spec: v1alpha2.InferenceObjectiveSpec{RequestTTL: test.objectiveTTL}}
+			assert.Equal(t, test.want, requestTTL(infObjective, test.clientTTL, test.defaultTTL))
+		})
+	}
+}
+
 func TestDirector_HandleResponseReceived(t *testing.T) {
 	pr1 := newTestResponseReceived("pr1")
 
@@ -1118,6 +1258,12 @@ func TestDirector_HandleResponseStreaming(t *testing.T) {
 			Headers: map[string]string{"X-Test-Streaming-Header": "StreamValue"},
 		},
 		TargetPod: &datalayer.EndpointMetadata{NamespacedName: types.NamespacedName{Namespace: "namespace1", Name: "test-pod-name"}},
+		ResponseEvents: []handlerstypes.StreamEvent{
+			{Data: `{"choices":[{"delta":{"content":"Hel"}}]}`, Delta: "Hel"},
+			{Data: `{"choices":[{"delta":{"content":"lo"}}]}`, Delta: "lo"},
+		},
+		ResponseChunkTokens: 2,
+		InterTokenLatency:   10 * time.Millisecond,
 	}
 
 	_, err := director.HandleResponseBodyStreaming(ctx, reqCtx)
@@ -1134,6 +1280,13 @@ func TestDirector_HandleResponseStreaming(t *testing.T) {
 	if diff := cmp.Diff("namespace1/test-pod-name", ps1.lastTargetPodOnStreaming); diff != "" {
 		t.Errorf("Scheduler.OnStreaming TargetPodName mismatch (-want +got):\n%s", diff)
 	}
+	if diff := cmp.Diff("Hello", ps1.lastRespOnStreaming.Content); diff != "" {
+		t.Errorf("Scheduler.OnStreaming Content mismatch (-want +got):\n%s", diff)
+	}
+	if ps1.lastRespOnStreaming.ChunkTokens != 2 || ps1.lastRespOnStreaming.InterTokenLatency != 10*time.Millisecond {
+		t.Errorf("Scheduler.OnStreaming got %d chunk tokens and %v inter-token latency, want 2 and 10ms",
+			ps1.lastRespOnStreaming.ChunkTokens, ps1.lastRespOnStreaming.InterTokenLatency)
+	}
 }
 
 func TestDirector_HandleResponseComplete(t *testing.T) {
@@ -1171,6 +1324,17 @@ func TestDirector_HandleResponseComplete(t *testing.T) {
 	if diff := cmp.Diff("namespace1/test-pod-name", pc1.lastTargetPodOnComplete); diff != "" {
 		t.Errorf("Scheduler.OnComplete TargetPodName mismatch (-want +got):\n%s", diff)
 	}
+	if !pc1.lastRespOnComplete.Incomplete {
+		t.Errorf("Scheduler.OnComplete got a complete response, want incomplete as the response was not fully received")
+	}
+
+	reqCtx.ResponseComplete = true
+	if _, err := director.HandleResponseBodyComplete(ctx, reqCtx); err != nil {
+		t.Fatalf("HandleResponseBodyComplete() returned unexpected error: %v", err)
+	}
+	if pc1.lastRespOnComplete.Incomplete {
+		t.Errorf("Scheduler.OnComplete got an incomplete response, want complete")
+	}
 }
 
 const (
@@ -23,11 +23,13 @@ import (
 	"sync"
 	"time"
 
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/common/util/logging"
 	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datalayer/plugins/outlierdetection"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datastore"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/flowcontrol/contracts"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metadata"
@@ -329,3 +331,54 @@ func (c *CachedPodLocator) cleanup() {
 		}
 	}
 }
+
+// --- OutlierEjectingPodLocator (The Decorator) ---
+
+// EndpointEjector decides which endpoints are temporarily ejected from the candidate pods of the requests, e.g. the
+// outlier-detector requestcontrol plugin.
+type EndpointEjector interface {
+	// OutlierStatus returns the outlier detection status of the endpoint at the given time.
+	OutlierStatus(endpoint types.NamespacedName, now time.Time) *outlierdetection.OutlierStatus
+}
+
+// OutlierEjectingPodLocator is a decorator for contracts.PodLocator that removes the ejected endpoints from the
+// candidate pods, and publishes the outlier detection status of the candidate pods as an endpoint attribute.
+//
+// If all the candidate pods are ejected, they are all returned: an ejected endpoint is still preferred to failing
+// the request.
+type OutlierEjectingPodLocator struct {
+	delegate contracts.PodLocator
+	ejector  EndpointEjector
+}
+
+var _ contracts.PodLocator = &OutlierEjectingPodLocator{}
+
+// NewOutlierEjectingPodLocator creates a new OutlierEjectingPodLocator.
+func NewOutlierEjectingPodLocator(delegate contracts.PodLocator, ejector EndpointEjector) *OutlierEjectingPodLocator {
+	return &OutlierEjectingPodLocator{
+		delegate: delegate,
+		ejector:  ejector,
+	}
+}
+
+// Locate returns the candidate pods of the delegate that are not ejected.
+func (l *OutlierEjectingPodLocator) Locate(ctx context.Context, requestMetadata map[string]any) []backendmetrics.PodMetrics {
+	pods := l.delegate.Locate(ctx, requestMetadata)
+	now := time.Now()
+
+	candidates := make([]backendmetrics.PodMetrics, 0, len(pods))
+	for _, pod := range pods {
+		status := l.ejector.OutlierStatus(pod.GetMetadata().NamespacedName, now)
+		pod.Put(outlierdetection.OutlierStatusKey, status)
+		if !status.Ejected {
+			candidates = append(candidates, pod)
+		}
+	}
+
+	if len(candidates) == 0 && len(pods) > 0 {
+		log.FromContext(ctx).V(logutil.DEBUG).Info("all candidate pods are ejected, ignoring the ejections",
+			"podCount", len(pods))
+		return pods
+	}
+	return candidates
+}
@@ -25,9 +25,11 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 
 	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datalayer"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datalayer/plugins/outlierdetection"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metadata"
 )
 
@@ -274,8 +276,72 @@ func TestCachedPodLocator_CacheIsolation_EmptyVsDefault(t *testing.T) {
 	assert.Equal(t, 2, mockDelegate.callCount(), "Empty subset should not hit the default cache key")
 }
 
+// --- OutlierEjectingPodLocator Tests ---
+
+func TestOutlierEjectingPodLocator_Locate(t *testing.T) {
+	t.Parallel()
+
+	podA := datalayer.NewEndpoint(&datalayer.EndpointMetadata{
+		NamespacedName: types.NamespacedName{Namespace: "default", Name: "pod-a"}, Address: "10.0.0.1"}, nil)
+	podB := datalayer.NewEndpoint(&datalayer.EndpointMetadata{
+		NamespacedName: types.NamespacedName{Namespace: "default", Name: "pod-b"}, Address: "10.0.0.2"}, nil)
+
+	tests := []struct {
+		name           string
+		ejected        []string
+		expectedPodIPs []string
+	}{
+		{
+			name:           "No ejected pods returns all pods",
+			expectedPodIPs: []string{"10.0.0.1", "10.0.0.2"},
+		},
+		{
+			name:           "Ejected pods are removed",
+			ejected:        []string{"pod-a"},
+			expectedPodIPs: []string{"10.0.0.2"},
+		},
+		{
+			name:           "All pods ejected returns all pods",
+			ejected:        []string{"pod-a", "pod-b"},
+			expectedPodIPs: []string{"10.0.0.1", "10.0.0.2"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ejector := &mockEndpointEjector{ejected: sets.New(tc.ejected...)}
+			locator := NewOutlierEjectingPodLocator(&mockPodLocator{result: []backendmetrics.PodMetrics{podA, podB}}, ejector)
+
+			result := locator.Locate(context.Background(), nil)
+
+			var resultIPs []string
+			for _, pm := range result {
+				resultIPs = append(resultIPs, pm.GetMetadata().GetIPAddress())
+			}
+			assert.ElementsMatch(t, tc.expectedPodIPs, resultIPs)
+		})
+	}
+
+	// The status of the pods is published as an endpoint attribute.
+	locator := NewOutlierEjectingPodLocator(&mockPodLocator{result: []backendmetrics.PodMetrics{podA, podB}},
+		&mockEndpointEjector{ejected: sets.New("pod-a")})
+	locator.Locate(context.Background(), nil)
+	status, ok := podA.Get(outlierdetection.OutlierStatusKey)
+	require.True(t, ok)
+	assert.True(t, status.(*outlierdetection.OutlierStatus).Ejected)
+}
+
 // --- Helpers & Mocks ---
 
+// mockEndpointEjector implements EndpointEjector.
+type mockEndpointEjector struct {
+	ejected sets.Set[string]
+}
+
+func (m *mockEndpointEjector) OutlierStatus(endpoint types.NamespacedName, _ time.Time) *outlierdetection.OutlierStatus {
+	return &outlierdetection.OutlierStatus{Ejected: m.ejected.Has(endpoint.Name)}
+}
+
 // mockPodLocator implements contracts.PodLocator.
 type mockPodLocator struct {
 	mu     sync.Mutex
@@ -0,0 +1,390 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package outlierdetection
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/common/util/logging"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datalayer"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datalayer/plugins/outlierdetection"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/plugin"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/requestcontrol"
+	schedulingtypes "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/scheduling"
+	errutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/error"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/window"
+)
+
+const (
+	// OutlierDetectorType is the type of the OutlierDetector plugin.
+	OutlierDetectorType = "outlier-detector"
+
+	// latencyWindowBuckets is the number of buckets the latency window of an endpoint is divided into.
+	latencyWindowBuckets = 10
+)
+
+// Parameters are the parameters of the OutlierDetector plugin. They mirror the outlier detection of Envoy clusters.
+type Parameters struct {
+	// ConsecutiveFailures is the number of requests failing in a row on an endpoint that ejects it. A request fails
+	// when the model server responds with a server error, e.g. a 5xx status or an out of memory error, or when the
+	// response is not fully received, e.g. because the connection to the model server was reset or the client gave up
+	// waiting. Defaults to 5.
+	ConsecutiveFailures int `json:"consecutiveFailures"`
+	// BaseEjectionTime is the time an endpoint is ejected for the first time. It doubles with each consecutive
+	// ejection of the endpoint, up to MaxEjectionTime. Defaults to 30s.
+	BaseEjectionTime metav1.Duration `json:"baseEjectionTime"`
+	// MaxEjectionTime is the maximum time an endpoint is ejected for. Once an endpoint has not been ejected for that
+	// long, its ejection time is reset to BaseEjectionTime. Defaults to 300s.
+	MaxEjectionTime metav1.Duration `json:"maxEjectionTime"`
+	// MaxEjectionPercent is the maximum percentage of the endpoints of the pool that can be ejected at the same time.
+	// One endpoint can always be ejected, unless it is the last one of the pool. Defaults to 10.
+	MaxEjectionPercent int `json:"maxEjectionPercent"`
+	// LatencyWindow is the duration of the sliding window over which the latencies of the requests served by the
+	// endpoints are compared. The latency of a request is its time to first token when it is streamed and its full
+	// latency otherwise. Defaults to 60s.
+	LatencyWindow metav1.Duration `json:"latencyWindow"`
+	// LatencyFactor ejects an endpoint whose mean latency over the window is more than LatencyFactor times the median
+	// of the mean latencies of the endpoints. It must be greater than 1, or 0 to disable the latency outlier
+	// detection. Defaults to 3.
+	LatencyFactor float64 `json:"latencyFactor"`
+	// LatencyMinimumRequests is the number of requests an endpoint must have served over the window for its latency
+	// to be compared. Defaults to 10.
+	LatencyMinimumRequests int `json:"latencyMinimumRequests"`
+	// LatencyMinimumEndpoints is the number of endpoints that must have served LatencyMinimumRequests requests over
+	// the window for their latencies to be compared. Defaults to 3.
+	LatencyMinimumEndpoints int `json:"latencyMinimumEndpoints"`
+}
+
+// DefaultParameters are the default parameters of the OutlierDetector plugin.
+var DefaultParameters = Parameters{
+	ConsecutiveFailures:     5,
+	BaseEjectionTime:        metav1.Duration{Duration: 30 * time.Second},
+	MaxEjectionTime:         metav1.Duration{Duration: 300 * time.Second},
+	MaxEjectionPercent:      10,
+	LatencyWindow:           metav1.Duration{Duration: 60 * time.Second},
+	LatencyFactor:           3,
+	LatencyMinimumRequests:  10,
+	LatencyMinimumEndpoints: 3,
+}
+
+func (p *Parameters) validate() error {
+	var errs []error
+	if p.ConsecutiveFailures <= 0 {
+		errs = append(errs, fmt.Errorf("consecutiveFailures must be > 0, got %d", p.ConsecutiveFailures))
+	}
+	if p.BaseEjectionTime.Duration <= 0 {
+		errs = append(errs, fmt.Errorf("baseEjectionTime must be > 0, got %s", p.BaseEjectionTime.Duration))
+	}
+	if p.MaxEjectionTime.Duration < p.BaseEjectionTime.Duration {
+		errs = append(errs, fmt.Errorf("maxEjectionTime must be >= baseEjectionTime, got %s", p.MaxEjectionTime.Duration))
+	}
+	if p.MaxEjectionPercent < 0 || p.MaxEjectionPercent > 100 {
+		errs = append(errs, fmt.Errorf("maxEjectionPercent must be in [0, 100], got %d", p.MaxEjectionPercent))
+	}
+	if p.LatencyWindow.Duration < time.Second {
+		errs = append(errs, fmt.Errorf("latencyWindow must be >= 1s, got %s", p.LatencyWindow.Duration))
+	}
+	if p.LatencyFactor != 0 && p.LatencyFactor <= 1 {
+		errs = append(errs, fmt.Errorf("latencyFactor must be > 1 or 0, got %g", p.LatencyFactor))
+	}
+	if p.LatencyMinimumRequests <= 0 {
+		errs = append(errs, fmt.Errorf("latencyMinimumRequests must be > 0, got %d", p.LatencyMinimumRequests))
+	}
+	if p.LatencyMinimumEndpoints <= 0 {
+		errs = append(errs, fmt.Errorf("latencyMinimumEndpoints must be > 0, got %d", p.LatencyMinimumEndpoints))
+	}
+	return errors.Join(errs...)
+}
+
+// compile-time type assertion
+var (
+	_ requestcontrol.PreRequest        = &OutlierDetector{}
+	_ requestcontrol.ResponseStreaming = &OutlierDetector{}
+	_ requestcontrol.ResponseComplete  = &OutlierDetector{}
+)
+
+// OutlierDetectorFactory defines the factory function for the OutlierDetector.
+func OutlierDetectorFactory(name string, rawParameters json.RawMessage, handle plugin.Handle) (plugin.Plugin, error) {
+	parameters := DefaultParameters
+	if len(rawParameters) > 0 {
+		if err := json.Unmarshal(rawParameters, &parameters); err != nil {
+			return nil, fmt.Errorf("failed to parse the parameters of the '%s' plugin - %w", OutlierDetectorType, err)
+		}
+	}
+	if err := parameters.validate(); err != nil {
+		return nil, fmt.Errorf("invalid parameters of the '%s' plugin - %w", OutlierDetectorType, err)
+	}
+	return NewOutlierDetector(parameters, func() int { return len(handle.PodList()) }).WithName(name), nil
+}
+
+// NewOutlierDetector returns a new OutlierDetector. poolSize returns the number of endpoints of the pool, which the
+// maximum number of ejected endpoints is computed from.
+func NewOutlierDetector(parameters Parameters, poolSize func() int) *OutlierDetector {
+	return &OutlierDetector{
+		typedName: plugin.TypedName{Type: OutlierDetectorType, Name: OutlierDetectorType},
+		params:    parameters,
+		poolSize:  poolSize,
+		now:       time.Now,
+		endpoints: map[types.NamespacedName]*endpointState{},
+	}
+}
+
+// OutlierDetector tracks the failures and the latencies of the requests served by each endpoint, and ejects the
+// endpoints that fail repeatedly or are much slower than the others for an exponentially increasing time, like the
+// outlier detection of Envoy. It catches endpoints that are wedged while still reporting healthy metrics, e.g. a model
+// server whose queue looks empty because it stopped making progress.
+//
+// The ejected endpoints are removed from the candidate endpoints of the requests by the pod locator, which also
+// publishes the OutlierStatus of the endpoints as an endpoint attribute.
+type OutlierDetector struct {
+	typedName plugin.TypedName
+	params    Parameters
+	poolSize  func() int
+	now       func() time.Time
+
+	// requests holds the requestTiming of the requests in flight, keyed by request id.
+	requests sync.Map
+
+	mu        sync.Mutex
+	endpoints map[types.NamespacedName]*endpointState
+}
+
+// requestTiming holds the timestamps of a request in flight that its latency is computed from.
+type requestTiming struct {
+	sent       time.Time
+	firstToken time.Time
+}
+
+// endpointState is the outlier detection state of an endpoint.
+type endpointState struct {
+	consecutiveFailures int
+	ejections           int
+	ejectedUntil        time.Time
+	// latencies holds the latencies of the requests served by the endpoint over the latency window, in nanoseconds.
+	latencies *window.Counter
+	// lastSeen is when the endpoint last completed a request, used to forget the endpoints that left the pool.
+	lastSeen time.Time
+}
+
+// TypedName returns the type and name tuple of this plugin instance.
+func (d *OutlierDetector) TypedName() plugin.TypedName {
+	return d.typedName
+}
+
+// WithName sets the name of the plugin.
+func (d *OutlierDetector) WithName(name string) *OutlierDetector {
+	d.typedName.Name = name
+	return d
+}
+
+// PreRequest records when the request is sent to the model server.
+func (d *OutlierDetector) PreRequest(_ context.Context, request *schedulingtypes.LLMRequest, _ *schedulingtypes.SchedulingResult) {
+	if request == nil {
+		return
+	}
+	d.requests.Store(request.RequestId, &requestTiming{sent: d.now()})
+}
+
+// ResponseStreaming records when the first token of a streamed response is received.
+func (d *OutlierDetector) ResponseStreaming(_ context.Context, request *schedulingtypes.LLMRequest, response *requestcontrol.Response, _ *datalayer.EndpointMetadata) {
+	if request == nil || response.FirstTokenTimestamp.IsZero() {
+		return
+	}
+	if value, ok := d.requests.Load(request.RequestId); ok {
+		if timing := value.(*requestTiming); timing.firstToken.IsZero() {
+			timing.firstToken = response.FirstTokenTimestamp
+		}
+	}
+}
+
+// ResponseComplete records the outcome and the latency of the request on the endpoint that served it, and ejects the
+// endpoint if it became an outlier.
+func (d *OutlierDetector) ResponseComplete(ctx context.Context, request *schedulingtypes.LLMRequest, response *requestcontrol.Response, targetEndpoint *datalayer.EndpointMetadata) {
+	var timing *requestTiming
+	if request != nil {
+		if value, ok := d.requests.LoadAndDelete(request.RequestId); ok {
+			timing = value.(*requestTiming)
+		}
+	}
+	if targetEndpoint == nil {
+		return
+	}
+	logger := log.FromContext(ctx).WithName(d.typedName.String()).WithValues("endpoint", targetEndpoint.NamespacedName)
+	now := d.now()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	state, ok := d.endpoints[targetEndpoint.NamespacedName]
+	if !ok {
+		state = &endpointState{latencies: window.NewCounter(d.params.LatencyWindow.Duration, latencyWindowBuckets)}
+		d.endpoints[targetEndpoint.NamespacedName] = state
+	}
+	state.lastSeen = now
+	if now.Before(state.ejectedUntil) {
+		return // a request sent before the endpoint was ejected
+	}
+	if response != nil && response.ClientCancelled {
+		return // the client disconnected, which says nothing about the endpoint
+	}
+
+	if isFailure(response) {
+		state.consecutiveFailures++
+		if state.consecutiveFailures >= d.params.ConsecutiveFailures {
+			d.eject(logger, state, now, "consecutive failures", "consecutiveFailures", state.consecutiveFailures)
+		}
+		return
+	}
+	state.consecutiveFailures = 0
+
+	if timing == nil || d.params.LatencyFactor == 0 {
+		return
+	}
+	latency := now.Sub(timing.sent)
+	if !timing.firstToken.IsZero() {
+		latency = timing.firstToken.Sub(timing.sent)
+	}
+	state.latencies.Add(now, float64(latency))
+	if mean, median, ok := d.latencyOutlier(state, now); ok {
+		d.eject(logger, state, now, "latency outlier", "meanLatency", mean, "medianLatency", median)
+	}
+}
+
+// OutlierStatus returns the outlier detection status of the endpoint at the given time.
+func (d *OutlierDetector) OutlierStatus(endpoint types.NamespacedName, now time.Time) *outlierdetection.OutlierStatus {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	state, ok := d.endpoints[endpoint]
+	if !ok {
+		return &outlierdetection.OutlierStatus{}
+	}
+	return &outlierdetection.OutlierStatus{
+		ConsecutiveFailures: state.consecutiveFailures,
+		Ejections:           state.ejections,
+		EjectedUntil:        state.ejectedUntil,
+		Ejected:             now.Before(state.ejectedUntil),
+	}
+}
+
+// isFailure returns true if the request failed because of the model server: its response was cut short by a
+// connection reset, or it is a server error.
+func isFailure(response *requestcontrol.Response) bool {
+	if response == nil {
+		return false
+	}
+	if response.Incomplete {
+		return true
+	}
+	switch response.ErrorCode {
+	case errutil.ModelServerError, errutil.ModelServerOutOfMemory:
+		return true
+	}
+	return false
+}
+
+// eject ejects the endpoint for an exponentially increasing time, unless the maximum number of endpoints is already
+// ejected. It must be called with the lock held.
+func (d *OutlierDetector) eject(logger logr.Logger, state *endpointState, now time.Time, reason string, keysAndValues ...any) {
+	if ejected, maxEjected := d.ejectedEndpoints(now), d.maxEjectedEndpoints(); ejected >= maxEjected {
+		logger.V(logutil.DEFAULT).Info("Not ejecting outlier endpoint, too many endpoints are ejected", append([]any{
+			"reason", reason, "ejectedEndpoints", ejected, "maxEjectedEndpoints", maxEjected}, keysAndValues...)...)
+		return
+	}
+
+	if !state.ejectedUntil.IsZero() && now.Sub(state.ejectedUntil) >= d.params.MaxEjectionTime.Duration {
+		state.ejections = 0
+	}
+	ejectionTime := d.params.MaxEjectionTime.Duration
+	if state.ejections < 32 {
+		ejectionTime = min(d.params.BaseEjectionTime.Duration<<state.ejections, d.params.MaxEjectionTime.Duration)
+	}
+	state.ejections++
+	state.ejectedUntil = now.Add(ejectionTime)
+	state.consecutiveFailures = 0
+	state.latencies.Reset()
+
+	logger.V(logutil.DEFAULT).Info("Ejecting outlier endpoint", append([]any{
+		"reason", reason, "ejectionTime", ejectionTime, "ejections", state.ejections}, keysAndValues...)...)
+}
+
+// ejectedEndpoints returns the number of endpoints ejected at the given time, forgetting on the way the endpoints that
+// have not served requests for long, e.g. because they left the pool. It must be called with the lock held.
+func (d *OutlierDetector) ejectedEndpoints(now time.Time) int {
+	retention := max(d.params.MaxEjectionTime.Duration, d.params.LatencyWindow.Duration)
+	ejected := 0
+	for name, state := range d.endpoints {
+		switch {
+		case now.Before(state.ejectedUntil):
+			ejected++
+		case now.Sub(state.lastSeen) > retention && now.Sub(state.ejectedUntil) > retention:
+			delete(d.endpoints, name)
+		}
+	}
+	return ejected
+}
+
+// maxEjectedEndpoints returns the number of endpoints of the pool that can be ejected at the same time.
+func (d *OutlierDetector) maxEjectedEndpoints() int {
+	poolSize := d.poolSize()
+	return min(max(poolSize*d.params.MaxEjectionPercent/100, 1), poolSize-1)
+}
+
+// latencyOutlier returns the mean latency of the endpoint and the median of the mean latencies of the endpoints,
+// and whether the endpoint is an outlier. It must be called with the lock held.
+func (d *OutlierDetector) latencyOutlier(state *endpointState, now time.Time) (time.Duration, time.Duration, bool) {
+	mean, count := meanLatency(state.latencies, now)
+	if count < d.params.LatencyMinimumRequests {
+		return 0, 0, false
+	}
+
+	means := []time.Duration{}
+	for _, other := range d.endpoints {
+		if otherMean, otherCount := meanLatency(other.latencies, now); otherCount >= d.params.LatencyMinimumRequests {
+			means = append(means, otherMean)
+		}
+	}
+	if len(means) < d.params.LatencyMinimumEndpoints {
+		return 0, 0, false
+	}
+	slices.Sort(means)
+	median := means[len(means)/2]
+	if len(means)%2 == 0 {
+		median = (means[len(means)/2-1] + median) / 2
+	}
+	return mean, median, float64(mean) > d.params.LatencyFactor*float64(median)
+}
+
+// meanLatency returns the mean latency and the number of the requests of the latency window ending at the given time.
+func meanLatency(latencies *window.Counter, now time.Time) (time.Duration, int) {
+	count, sum := latencies.Total(now)
+	if count == 0 {
+		return 0, 0
+	}
+	return time.Duration(sum / float64(count)), count
+}
@@ -0,0 +1,267 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package outlierdetection
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datalayer"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/requestcontrol"
+	schedulingtypes "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/scheduling"
+	errutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/error"
+)
+
+// fakeClock is a clock advanced by the tests.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func newTestDetector(params Parameters, poolSize int) (*OutlierDetector, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	detector := NewOutlierDetector(params, func() int { return poolSize })
+	detector.now = clock.Now
+	return detector, clock
+}
+
+func endpoint(name string) *datalayer.EndpointMetadata {
+	return &datalayer.EndpointMetadata{NamespacedName: types.NamespacedName{Namespace: "default", Name: name}}
+}
+
+// serve runs the hooks of the detector for a request served by the endpoint in the given latency.
+func serve(detector *OutlierDetector, clock *fakeClock, target *datalayer.EndpointMetadata, latency time.Duration, response *requestcontrol.Response) {
+	request := &schedulingtypes.LLMRequest{RequestId: fmt.Sprintf("%s-%d", target.NamespacedName.Name, clock.now.UnixNano())}
+	detector.PreRequest(context.Background(), request, nil)
+	clock.now = clock.now.Add(latency)
+	detector.ResponseComplete(context.Background(), request, response, target)
+}
+
+func TestOutlierDetector_ConsecutiveFailures(t *testing.T) {
+	detector, clock := newTestDetector(DefaultParameters, 10)
+	pod := endpoint("pod-a")
+	failure := &requestcontrol.Response{ErrorCode: errutil.ModelServerError}
+
+	for range DefaultParameters.ConsecutiveFailures - 1 {
+		serve(detector, clock, pod, time.Second, failure)
+	}
+	// A success resets the count, and a client error is not a failure of the endpoint.
+	serve(detector, clock, pod, time.Second, &requestcontrol.Response{})
+	serve(detector, clock, pod, time.Second, &requestcontrol.Response{ErrorCode: errutil.ContextLengthExceeded})
+	status := detector.OutlierStatus(pod.NamespacedName, clock.now)
+	assert.Equal(t, 0, status.ConsecutiveFailures)
+	assert.False(t, status.Ejected)
+
+	for range DefaultParameters.ConsecutiveFailures - 1 {
+		serve(detector, clock, pod, time.Second, &requestcontrol.Response{Incomplete: true})
+	}
+	serve(detector, clock, pod, time.Second, &requestcontrol.Response{ErrorCode: errutil.ModelServerOutOfMemory})
+	status = detector.OutlierStatus(pod.NamespacedName, clock.now)
+	assert.True(t, status.Ejected)
+	assert.Equal(t, 1, status.Ejections)
+	assert.Equal(t, clock.now.Add(DefaultParameters.BaseEjectionTime.Duration), status.EjectedUntil)
+
+	// The requests completed while the endpoint is ejected are ignored.
+	serve(detector, clock, pod, time.Second, failure)
+	assert.Equal(t, 0, detector.OutlierStatus(pod.NamespacedName, clock.now).ConsecutiveFailures)
+
+	assert.False(t, detector.OutlierStatus(pod.NamespacedName, status.EjectedUntil).Ejected)
+}
+
+func TestOutlierDetector_ClientCancelled(t *testing.T) {
+	detector, clock := newTestDetector(DefaultParameters, 10)
+	pod := endpoint("pod-a")
+
+	for range DefaultParameters.ConsecutiveFailures - 1 {
+		serve(detector, clock, pod, time.Second, &requestcontrol.Response{Incomplete: true})
+	}
+	// Client disconnects neither count as failures nor reset the count.
+	for range DefaultParameters.ConsecutiveFailures {
+		serve(detector, clock, pod, time.Second, &requestcontrol.Response{Incomplete: true, ClientCancelled: true})
+	}
+	status := detector.OutlierStatus(pod.NamespacedName, clock.now)
+	assert.Equal(t, DefaultParameters.ConsecutiveFailures-1, status.ConsecutiveFailures)
+	assert.False(t, status.Ejected)
+}
+
+func TestOutlierDetector_EjectionBackoff(t *testing.T) {
+	params := DefaultParameters
+	params.ConsecutiveFailures = 1
+	detector, clock := newTestDetector(params, 10)
+	pod := endpoint("pod-a")
+	failure := &requestcontrol.Response{ErrorCode: errutil.ModelServerError}
+
+	wantEjectionTimes := []time.Duration{30 * time.Second, 60 * time.Second, 120 * time.Second, 240 * time.Second,
+		300 * time.Second, 300 * time.Second}
+	for i, want := range wantEjectionTimes {
+		serve(detector, clock, pod, time.Second, failure)
+		status := detector.OutlierStatus(pod.NamespacedName, clock.now)
+		require.True(t, status.Ejected, "ejection %d", i+1)
+		assert.Equal(t, want, status.EjectedUntil.Sub(clock.now), "ejection %d", i+1)
+		clock.now = status.EjectedUntil
+	}
+
+	// The backoff is reset once the endpoint has not been ejected for the maximum ejection time.
+	clock.now = clock.now.Add(params.MaxEjectionTime.Duration)
+	serve(detector, clock, pod, time.Second, failure)
+	status := detector.OutlierStatus(pod.NamespacedName, clock.now)
+	assert.Equal(t, 1, status.Ejections)
+	assert.Equal(t, params.BaseEjectionTime.Duration, status.EjectedUntil.Sub(clock.now))
+}
+
+func TestOutlierDetector_MaxEjectionPercent(t *testing.T) {
+	tests := []struct {
+		name               string
+		poolSize           int
+		maxEjectionPercent int
+		wantEjected        int
+	}{
+		{name: "at least one endpoint", poolSize: 4, maxEjectionPercent: 10, wantEjected: 1},
+		{name: "percentage of the pool", poolSize: 4, maxEjectionPercent: 50, wantEjected: 2},
+		{name: "never the last endpoint", poolSize: 4, maxEjectionPercent: 100, wantEjected: 3},
+		{name: "single endpoint", poolSize: 1, maxEjectionPercent: 100, wantEjected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := DefaultParameters
+			params.ConsecutiveFailures = 1
+			params.MaxEjectionPercent = tt.maxEjectionPercent
+			detector, clock := newTestDetector(params, tt.poolSize)
+
+			ejected := 0
+			for i := range tt.poolSize {
+				pod := endpoint(fmt.Sprintf("pod-%d", i))
+				serve(detector, clock, pod, time.Second, &requestcontrol.Response{ErrorCode: errutil.ModelServerError})
+				if detector.OutlierStatus(pod.NamespacedName, clock.now).Ejected {
+					ejected++
+				}
+			}
+			assert.Equal(t, tt.wantEjected, ejected)
+		})
+	}
+}
+
+func TestOutlierDetector_LatencyOutlier(t *testing.T) {
+	params := DefaultParameters
+	params.LatencyMinimumRequests = 3
+	detector, clock := newTestDetector(params, 4)
+	ok := &requestcontrol.Response{}
+	fast, slow := endpoint("fast"), endpoint("slow")
+
+	// Not enough endpoints have served requests yet for the latencies to be compared.
+	for range params.LatencyMinimumRequests {
+		serve(detector, clock, slow, 10*time.Second, ok)
+		serve(detector, clock, fast, time.Second, ok)
+	}
+	assert.False(t, detector.OutlierStatus(slow.NamespacedName, clock.now).Ejected)
+
+	for range params.LatencyMinimumRequests {
+		serve(detector, clock, endpoint("other"), 2*time.Second, ok)
+	}
+	serve(detector, clock, slow, 10*time.Second, ok)
+	assert.True(t, detector.OutlierStatus(slow.NamespacedName, clock.now).Ejected)
+	assert.False(t, detector.OutlierStatus(fast.NamespacedName, clock.now).Ejected)
+}
+
+func TestOutlierDetector_LatencyWindowSlides(t *testing.T) {
+	params := DefaultParameters
+	params.LatencyMinimumRequests = 3
+	detector, clock := newTestDetector(params, 4)
+	ok := &requestcontrol.Response{}
+	slow := endpoint("slow")
+
+	for range params.LatencyMinimumRequests {
+		serve(detector, clock, slow, 10*time.Second, ok)
+	}
+	// The slow requests leave the window before the other endpoints have served enough requests.
+	clock.now = clock.now.Add(params.LatencyWindow.Duration)
+	for range params.LatencyMinimumRequests {
+		serve(detector, clock, endpoint("fast-1"), time.Second, ok)
+		serve(detector, clock, endpoint("fast-2"), time.Second, ok)
+	}
+	serve(detector, clock, slow, time.Second, ok)
+	assert.False(t, detector.OutlierStatus(slow.NamespacedName, clock.now).Ejected)
+}
+
+func TestOutlierDetector_TimeToFirstToken(t *testing.T) {
+	params := DefaultParameters
+	params.LatencyMinimumRequests = 1
+	detector, clock := newTestDetector(params, 4)
+	for _, name := range []string{"fast-1", "fast-2"} {
+		serve(detector, clock, endpoint(name), time.Second, &requestcontrol.Response{})
+	}
+
+	// A long streamed response whose first token came quickly is not an outlier.
+	pod := endpoint("streaming")
+	request := &schedulingtypes.LLMRequest{RequestId: "streaming"}
+	detector.PreRequest(context.Background(), request, nil)
+	detector.ResponseStreaming(context.Background(), request, &requestcontrol.Response{
+		FirstTokenTimestamp: clock.now.Add(500 * time.Millisecond)}, pod)
+	clock.now = clock.now.Add(time.Minute)
+	detector.ResponseComplete(context.Background(), request, &requestcontrol.Response{}, pod)
+	assert.False(t, detector.OutlierStatus(pod.NamespacedName, clock.now).Ejected)
+}
+
+func TestOutlierDetectorFactory(t *testing.T) {
+	tests := []struct {
+		name       string
+		jsonParams string
+		want       Parameters
+		expectErr  bool
+	}{
+		{name: "defaults", want: DefaultParameters},
+		{
+			name:       "custom parameters",
+			jsonParams: `{"consecutiveFailures": 3, "baseEjectionTime": "10s", "maxEjectionTime": "1m", "latencyFactor": 0}`,
+			want: func() Parameters {
+				params := DefaultParameters
+				params.ConsecutiveFailures = 3
+				params.BaseEjectionTime = metav1.Duration{Duration: 10 * time.Second}
+				params.MaxEjectionTime = metav1.Duration{Duration: time.Minute}
+				params.LatencyFactor = 0
+				return params
+			}(),
+		},
+		{name: "max ejection time below base ejection time", jsonParams: `{"maxEjectionTime": "10s"}`, expectErr: true},
+		{name: "latency factor of 1", jsonParams: `{"latencyFactor": 1}`, expectErr: true},
+		{name: "max ejection percent above 100", jsonParams: `{"maxEjectionPercent": 101}`, expectErr: true},
+		{name: "invalid json", jsonParams: `{"consecutiveFailures": "5"}`, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plugin, err := OutlierDetectorFactory("detector", []byte(tt.jsonParams), nil)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			detector := plugin.(*OutlierDetector)
+			assert.Equal(t, "detector", detector.TypedName().Name)
+			assert.Equal(t, tt.want, detector.params)
+		})
+	}
+}
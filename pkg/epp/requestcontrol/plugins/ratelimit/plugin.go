@@ -0,0 +1,379 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"path"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/common/util/logging"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datalayer"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/plugin"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/requestcontrol"
+	schedulingtypes "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/scheduling"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metadata"
+	errutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/error"
+)
+
+const (
+	// TokenBucketRateLimiterType is the type of the TokenBucketRateLimiter plugin.
+	TokenBucketRateLimiterType = "token-bucket-rate-limiter"
+
+	// reservationTimeout is how long the reservation of an allowed request is kept waiting for its response to complete
+	// or for its rejection, in case neither is reported.
+	reservationTimeout = time.Hour
+	// sweepInterval is the minimum interval between two sweeps of the expired reservations and the idle buckets.
+	sweepInterval = time.Minute
+)
+
+// Parameters are the parameters of the TokenBucketRateLimiter plugin.
+type Parameters struct {
+	// Budgets are the rate limits of the fairness IDs. The requests of a fairness ID are limited by the first budget
+	// whose pattern matches the ID, and are not limited if none does.
+	Budgets []Budget `json:"budgets"`
+}
+
+// Budget is a rate limit applied to each of the fairness IDs matching its pattern.
+type Budget struct {
+	// FairnessID is the pattern of the fairness IDs the budget applies to, in the syntax of path.Match, e.g. "tenant-a"
+	// or "team-*". Each fairness ID matching the pattern gets a budget of its own.
+	FairnessID string `json:"fairnessID"`
+	// RequestsPerSecond is the number of requests per second of the fairness ID. 0 means unlimited.
+	RequestsPerSecond float64 `json:"requestsPerSecond"`
+	// RequestBurst is the number of requests that can be sent at once after a period of inactivity. Defaults to
+	// RequestsPerSecond, rounded up.
+	RequestBurst int `json:"requestBurst"`
+	// TokensPerMinute is the number of tokens per minute of the fairness ID, counting both the prompt and the generated
+	// tokens. A request is charged its estimated prompt tokens plus its max_tokens when it is allowed, and the charge
+	// is corrected with the usage reported by the model server when the response completes. All of the tokens of a
+	// minute can be used at once. 0 means unlimited.
+	TokensPerMinute int64 `json:"tokensPerMinute"`
+}
+
+func (p *Parameters) validate() error {
+	var errs []error
+	for i, budget := range p.Budgets {
+		if budget.FairnessID == "" {
+			errs = append(errs, fmt.Errorf("budgets[%d].fairnessID must not be empty", i))
+		} else if _, err := path.Match(budget.FairnessID, ""); err != nil {
+			errs = append(errs, fmt.Errorf("budgets[%d].fairnessID %q is not a valid pattern - %w", i, budget.FairnessID, err))
+		}
+		if budget.RequestsPerSecond < 0 {
+			errs = append(errs, fmt.Errorf("budgets[%d].requestsPerSecond must be >= 0, got %g", i, budget.RequestsPerSecond))
+		}
+		if budget.RequestBurst < 0 {
+			errs = append(errs, fmt.Errorf("budgets[%d].requestBurst must be >= 0, got %d", i, budget.RequestBurst))
+		}
+		if budget.TokensPerMinute < 0 {
+			errs = append(errs, fmt.Errorf("budgets[%d].tokensPerMinute must be >= 0, got %d", i, budget.TokensPerMinute))
+		}
+		if budget.RequestsPerSecond == 0 && budget.TokensPerMinute == 0 {
+			errs = append(errs, fmt.Errorf("budgets[%d] must set requestsPerSecond or tokensPerMinute", i))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// compile-time type assertion
+var (
+	_ requestcontrol.RateLimiter      = &TokenBucketRateLimiter{}
+	_ requestcontrol.ResponseComplete = &TokenBucketRateLimiter{}
+)
+
+// TokenBucketRateLimiterFactory defines the factory function for the TokenBucketRateLimiter.
+func TokenBucketRateLimiterFactory(name string, rawParameters json.RawMessage, _ plugin.Handle) (plugin.Plugin, error) {
+	parameters := Parameters{}
+	if len(rawParameters) > 0 {
+		if err := json.Unmarshal(rawParameters, &parameters); err != nil {
+			return nil, fmt.Errorf("failed to parse the parameters of the '%s' plugin - %w", TokenBucketRateLimiterType, err)
+		}
+	}
+	if err := parameters.validate(); err != nil {
+		return nil, fmt.Errorf("invalid parameters of the '%s' plugin - %w", TokenBucketRateLimiterType, err)
+	}
+	return NewTokenBucketRateLimiter(parameters).WithName(name), nil
+}
+
+// NewTokenBucketRateLimiter returns a new TokenBucketRateLimiter.
+func NewTokenBucketRateLimiter(parameters Parameters) *TokenBucketRateLimiter {
+	return &TokenBucketRateLimiter{
+		typedName:    plugin.TypedName{Type: TokenBucketRateLimiterType, Name: TokenBucketRateLimiterType},
+		params:       parameters,
+		now:          time.Now,
+		limiters:     map[string]*limiter{},
+		reservations: map[string]reservation{},
+	}
+}
+
+// TokenBucketRateLimiter enforces per fairness ID budgets of requests per second and tokens per minute with token
+// buckets. The requests exceeding their budget are rejected with a 429 response telling the client when to retry,
+// before they wait in the Flow Control queues.
+type TokenBucketRateLimiter struct {
+	typedName plugin.TypedName
+	params    Parameters
+	now       func() time.Time
+
+	mu sync.Mutex
+	// limiters holds the buckets of the fairness IDs, keyed by fairness ID.
+	limiters map[string]*limiter
+	// reservations holds the charges of the allowed requests whose response has not completed yet, keyed by request id.
+	reservations map[string]reservation
+	lastSweep    time.Time
+}
+
+// limiter holds the buckets of a fairness ID. A nil bucket is unlimited.
+type limiter struct {
+	requests *tokenBucket
+	tokens   *tokenBucket
+}
+
+// reservation is the charge of an allowed request: a request and its estimated tokens.
+type reservation struct {
+	fairnessID string
+	tokens     float64
+	allowed    time.Time
+}
+
+// TypedName returns the type and name tuple of this plugin instance.
+func (l *TokenBucketRateLimiter) TypedName() plugin.TypedName {
+	return l.typedName
+}
+
+// WithName sets the name of the plugin.
+func (l *TokenBucketRateLimiter) WithName(name string) *TokenBucketRateLimiter {
+	l.typedName.Name = name
+	return l
+}
+
+// LimitRequest allows the request if the budget of its fairness ID has a request and its estimated tokens left, and
+// charges them to the budget. Otherwise, it denies the request with a RateLimited error holding the time until the
+// budget refills enough.
+func (l *TokenBucketRateLimiter) LimitRequest(ctx context.Context, request *schedulingtypes.LLMRequest) error {
+	if request == nil {
+		return nil
+	}
+	fairnessID := request.Headers[metadata.FlowFairnessIDKey]
+	if fairnessID == "" {
+		fairnessID = metadata.DefaultFairnessID
+	}
+	tokens := float64(request.Body.EstimatedPromptTokens() + request.Body.MaxOutputTokens())
+	now := l.now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+	limiter := l.limiter(fairnessID, now)
+	if limiter == nil {
+		return nil
+	}
+
+	var wait time.Duration
+	var exceeded string
+	if limiter.requests != nil {
+		if requestsWait := limiter.requests.wait(1, now); requestsWait > 0 {
+			wait, exceeded = requestsWait, "requests per second"
+		}
+	}
+	if limiter.tokens != nil {
+		if tokensWait := limiter.tokens.wait(tokens, now); tokensWait > wait {
+			wait, exceeded = tokensWait, "tokens per minute"
+		}
+	}
+	if wait > 0 {
+		log.FromContext(ctx).V(logutil.DEBUG).Info("Rate limiting request", "fairnessID", fairnessID,
+			"exceeded", exceeded, "estimatedTokens", tokens, "retryAfter", wait)
+		return errutil.Error{
+			Code:       errutil.RateLimited,
+			Msg:        fmt.Sprintf("fairness ID %q exceeded its budget of %s", fairnessID, exceeded),
+			RetryAfter: wait,
+		}
+	}
+
+	if limiter.requests != nil {
+		limiter.requests.take(1)
+	}
+	if limiter.tokens != nil {
+		limiter.tokens.take(tokens)
+	}
+	l.reservations[request.RequestId] = reservation{fairnessID: fairnessID, tokens: tokens, allowed: now}
+	return nil
+}
+
+// RequestRejected refunds the request and the tokens charged to a request rejected after it was allowed, e.g. by the
+// scheduler.
+func (l *TokenBucketRateLimiter) RequestRejected(_ context.Context, request *schedulingtypes.LLMRequest) {
+	if request == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	reserved, ok := l.reservations[request.RequestId]
+	if !ok {
+		return
+	}
+	delete(l.reservations, request.RequestId)
+
+	limiter, ok := l.limiters[reserved.fairnessID]
+	if !ok {
+		return
+	}
+	if limiter.requests != nil {
+		limiter.requests.take(-1)
+	}
+	if limiter.tokens != nil {
+		limiter.tokens.take(-reserved.tokens)
+	}
+}
+
+// ResponseComplete corrects the tokens charged to the request with the usage reported by the model server, refunding
+// the unused tokens or charging the extra ones. The estimated charge is kept if the model server reported no usage.
+func (l *TokenBucketRateLimiter) ResponseComplete(_ context.Context, request *schedulingtypes.LLMRequest, response *requestcontrol.Response, _ *datalayer.EndpointMetadata) {
+	if request == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	reserved, ok := l.reservations[request.RequestId]
+	if !ok {
+		return
+	}
+	delete(l.reservations, request.RequestId)
+
+	if response == nil {
+		return
+	}
+	used := response.Usage.TotalTokens
+	if used == 0 {
+		used = response.Usage.PromptTokens + response.Usage.CompletionTokens
+	}
+	if used == 0 {
+		return
+	}
+	if limiter, ok := l.limiters[reserved.fairnessID]; ok && limiter.tokens != nil {
+		limiter.tokens.take(float64(used) - reserved.tokens)
+	}
+}
+
+// limiter returns the limiter of the fairness ID, creating it from the first matching budget, or nil if no budget
+// matches the fairness ID. It must be called with the lock held.
+func (l *TokenBucketRateLimiter) limiter(fairnessID string, now time.Time) *limiter {
+	if existing, ok := l.limiters[fairnessID]; ok {
+		return existing
+	}
+	for _, budget := range l.params.Budgets {
+		if matched, _ := path.Match(budget.FairnessID, fairnessID); !matched {
+			continue
+		}
+		created := &limiter{}
+		if budget.RequestsPerSecond > 0 {
+			burst := budget.RequestBurst
+			if burst == 0 {
+				burst = int(math.Ceil(budget.RequestsPerSecond))
+			}
+			created.requests = newTokenBucket(budget.RequestsPerSecond, float64(burst), now)
+		}
+		if budget.TokensPerMinute > 0 {
+			created.tokens = newTokenBucket(float64(budget.TokensPerMinute)/60, float64(budget.TokensPerMinute), now)
+		}
+		l.limiters[fairnessID] = created
+		return created
+	}
+	return nil
+}
+
+// sweep drops the reservations of the requests that never completed, and the limiters whose buckets refilled, which
+// are the same as new ones. The limiters of the open reservations are kept, so that their charges can still be
+// corrected. It must be called with the lock held.
+func (l *TokenBucketRateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+	reserved := map[string]bool{}
+	for requestID, reservation := range l.reservations {
+		if now.Sub(reservation.allowed) > reservationTimeout {
+			delete(l.reservations, requestID)
+			continue
+		}
+		reserved[reservation.fairnessID] = true
+	}
+	for fairnessID, limiter := range l.limiters {
+		if !reserved[fairnessID] && limiter.requests.full(now) && limiter.tokens.full(now) {
+			delete(l.limiters, fairnessID)
+		}
+	}
+}
+
+// tokenBucket is a bucket of tokens refilled at a constant rate up to its capacity. Its tokens can go negative when
+// the tokens charged to a request are corrected upwards, delaying the next requests until the debt is paid.
+type tokenBucket struct {
+	// rate is the number of tokens added to the bucket per second.
+	rate     float64
+	capacity float64
+	tokens   float64
+	updated  time.Time
+}
+
+func newTokenBucket(rate, capacity float64, now time.Time) *tokenBucket {
+	return &tokenBucket{rate: rate, capacity: capacity, tokens: capacity, updated: now}
+}
+
+// refill adds the tokens accumulated since the last refill.
+func (b *tokenBucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.updated); elapsed > 0 {
+		b.tokens = min(b.tokens+elapsed.Seconds()*b.rate, b.capacity)
+		b.updated = now
+	}
+}
+
+// wait returns how long until the bucket holds the given number of tokens, 0 if it already does. A number of tokens
+// larger than the capacity of the bucket only waits for the bucket to be full.
+func (b *tokenBucket) wait(tokens float64, now time.Time) time.Duration {
+	b.refill(now)
+	missing := min(tokens, b.capacity) - b.tokens
+	if missing <= 0 {
+		return 0
+	}
+	return time.Duration(math.Ceil(missing / b.rate * float64(time.Second)))
+}
+
+// take removes tokens from the bucket, or adds them back if negative.
+func (b *tokenBucket) take(tokens float64) {
+	b.tokens = min(b.tokens-tokens, b.capacity)
+}
+
+// full returns true if the bucket is full, or is nil.
+func (b *tokenBucket) full(now time.Time) bool {
+	if b == nil {
+		return true
+	}
+	b.refill(now)
+	return b.tokens >= b.capacity
+}
@@ -0,0 +1,250 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/requestcontrol"
+	schedulingtypes "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/scheduling"
+	handlerstypes "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/handlers/types"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metadata"
+	errutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/error"
+)
+
+// fakeClock is a clock advanced by the tests.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func newTestLimiter(budgets ...Budget) (*TokenBucketRateLimiter, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	limiter := NewTokenBucketRateLimiter(Parameters{Budgets: budgets})
+	limiter.now = clock.Now
+	return limiter, clock
+}
+
+var requestCount int
+
+// newRequest returns a completions request of the fairness ID with a prompt of promptTokens estimated tokens.
+func newRequest(fairnessID string, promptTokens, maxTokens int) *schedulingtypes.LLMRequest {
+	requestCount++
+	headers := map[string]string{}
+	if fairnessID != "" {
+		headers[metadata.FlowFairnessIDKey] = fairnessID
+	}
+	return &schedulingtypes.LLMRequest{
+		RequestId: fmt.Sprintf("request-%d", requestCount),
+		Headers:   headers,
+		Body: &schedulingtypes.LLMRequestBody{
+			Completions: &schedulingtypes.CompletionsRequest{
+				Prompt:    schedulingtypes.Prompt{Raw: strings.Repeat("abcd", promptTokens)},
+				MaxTokens: maxTokens,
+			},
+		},
+	}
+}
+
+// retryAfter returns the retry delay of a RateLimited error, failing the test for any other error.
+func retryAfter(t *testing.T, err error) time.Duration {
+	t.Helper()
+	var rateLimited errutil.Error
+	require.ErrorAs(t, err, &rateLimited)
+	require.Equal(t, errutil.RateLimited, rateLimited.Code)
+	return rateLimited.RetryAfter
+}
+
+func TestTokenBucketRateLimiter_RequestsPerSecond(t *testing.T) {
+	limiter, clock := newTestLimiter(Budget{FairnessID: "tenant", RequestsPerSecond: 2, RequestBurst: 3})
+	ctx := context.Background()
+
+	for range 3 {
+		require.NoError(t, limiter.LimitRequest(ctx, newRequest("tenant", 10, 0)))
+	}
+	assert.Equal(t, 500*time.Millisecond, retryAfter(t, limiter.LimitRequest(ctx, newRequest("tenant", 10, 0))))
+
+	clock.now = clock.now.Add(500 * time.Millisecond)
+	require.NoError(t, limiter.LimitRequest(ctx, newRequest("tenant", 10, 0)))
+	assert.Error(t, limiter.LimitRequest(ctx, newRequest("tenant", 10, 0)))
+}
+
+func TestTokenBucketRateLimiter_TokensPerMinute(t *testing.T) {
+	limiter, clock := newTestLimiter(Budget{FairnessID: "tenant", TokensPerMinute: 600})
+	ctx := context.Background()
+
+	// Each request is charged its 100 prompt tokens plus its 200 max tokens.
+	require.NoError(t, limiter.LimitRequest(ctx, newRequest("tenant", 100, 200)))
+	require.NoError(t, limiter.LimitRequest(ctx, newRequest("tenant", 100, 200)))
+	// The budget refills 10 tokens per second.
+	assert.Equal(t, 30*time.Second, retryAfter(t, limiter.LimitRequest(ctx, newRequest("tenant", 100, 200))))
+
+	clock.now = clock.now.Add(10 * time.Second)
+	assert.Equal(t, 20*time.Second, retryAfter(t, limiter.LimitRequest(ctx, newRequest("tenant", 100, 200))))
+	require.NoError(t, limiter.LimitRequest(ctx, newRequest("tenant", 50, 50)))
+}
+
+func TestTokenBucketRateLimiter_UsageReconciliation(t *testing.T) {
+	limiter, _ := newTestLimiter(Budget{FairnessID: "tenant", TokensPerMinute: 600})
+	ctx := context.Background()
+
+	first := newRequest("tenant", 100, 500)
+	require.NoError(t, limiter.LimitRequest(ctx, first))
+	assert.Error(t, limiter.LimitRequest(ctx, newRequest("tenant", 100, 100)))
+
+	// The request only used 150 of the 600 tokens charged, the rest is refunded.
+	limiter.ResponseComplete(ctx, first, &requestcontrol.Response{Usage: handlerstypes.Usage{TotalTokens: 150}}, nil)
+	second := newRequest("tenant", 100, 100)
+	require.NoError(t, limiter.LimitRequest(ctx, second))
+
+	// The request used 400 more tokens than the 200 charged, leaving the budget 150 tokens in debt.
+	limiter.ResponseComplete(ctx, second, &requestcontrol.Response{Usage: handlerstypes.Usage{PromptTokens: 100, CompletionTokens: 500}}, nil)
+	assert.Equal(t, 35*time.Second, retryAfter(t, limiter.LimitRequest(ctx, newRequest("tenant", 100, 100))))
+}
+
+func TestTokenBucketRateLimiter_NoUsageKeepsCharge(t *testing.T) {
+	limiter, _ := newTestLimiter(Budget{FairnessID: "tenant", TokensPerMinute: 600})
+	ctx := context.Background()
+
+	request := newRequest("tenant", 100, 500)
+	require.NoError(t, limiter.LimitRequest(ctx, request))
+	limiter.ResponseComplete(ctx, request, &requestcontrol.Response{Incomplete: true}, nil)
+	assert.Error(t, limiter.LimitRequest(ctx, newRequest("tenant", 100, 100)))
+}
+
+func TestTokenBucketRateLimiter_RequestRejected(t *testing.T) {
+	limiter, _ := newTestLimiter(Budget{FairnessID: "tenant", RequestsPerSecond: 1, TokensPerMinute: 600})
+	ctx := context.Background()
+
+	rejected := newRequest("tenant", 100, 500)
+	require.NoError(t, limiter.LimitRequest(ctx, rejected))
+	assert.Error(t, limiter.LimitRequest(ctx, newRequest("tenant", 10, 0)))
+
+	// The request and the tokens charged to a request rejected after it was allowed are refunded, once.
+	limiter.RequestRejected(ctx, rejected)
+	limiter.RequestRejected(ctx, rejected)
+	assert.Empty(t, limiter.reservations)
+	require.NoError(t, limiter.LimitRequest(ctx, newRequest("tenant", 100, 500)))
+	assert.Error(t, limiter.LimitRequest(ctx, newRequest("tenant", 10, 0)))
+}
+
+func TestTokenBucketRateLimiter_RequestLargerThanBudget(t *testing.T) {
+	limiter, clock := newTestLimiter(Budget{FairnessID: "tenant", TokensPerMinute: 600})
+	ctx := context.Background()
+
+	// A request larger than the budget is allowed when the budget is full, rather than never.
+	require.NoError(t, limiter.LimitRequest(ctx, newRequest("tenant", 1000, 0)))
+	assert.Equal(t, 100*time.Second, retryAfter(t, limiter.LimitRequest(ctx, newRequest("tenant", 1000, 0))))
+
+	clock.now = clock.now.Add(100 * time.Second)
+	require.NoError(t, limiter.LimitRequest(ctx, newRequest("tenant", 1000, 0)))
+}
+
+func TestTokenBucketRateLimiter_FairnessIDPatterns(t *testing.T) {
+	limiter, _ := newTestLimiter(
+		Budget{FairnessID: "team-a", RequestsPerSecond: 2},
+		Budget{FairnessID: "team-*", RequestsPerSecond: 1},
+		Budget{FairnessID: metadata.DefaultFairnessID, RequestsPerSecond: 1},
+	)
+	ctx := context.Background()
+
+	// team-a is limited by the first budget matching it.
+	require.NoError(t, limiter.LimitRequest(ctx, newRequest("team-a", 10, 0)))
+	require.NoError(t, limiter.LimitRequest(ctx, newRequest("team-a", 10, 0)))
+	assert.Error(t, limiter.LimitRequest(ctx, newRequest("team-a", 10, 0)))
+
+	// Each fairness ID matching a pattern gets a budget of its own.
+	require.NoError(t, limiter.LimitRequest(ctx, newRequest("team-b", 10, 0)))
+	assert.Error(t, limiter.LimitRequest(ctx, newRequest("team-b", 10, 0)))
+	require.NoError(t, limiter.LimitRequest(ctx, newRequest("team-c", 10, 0)))
+
+	// Requests without a fairness ID get the default one.
+	require.NoError(t, limiter.LimitRequest(ctx, newRequest("", 10, 0)))
+	assert.Error(t, limiter.LimitRequest(ctx, newRequest("", 10, 0)))
+
+	// Fairness IDs matching no budget are not limited.
+	for range 10 {
+		require.NoError(t, limiter.LimitRequest(ctx, newRequest("other", 10, 0)))
+	}
+}
+
+func TestTokenBucketRateLimiter_Sweep(t *testing.T) {
+	limiter, clock := newTestLimiter(Budget{FairnessID: "*", RequestsPerSecond: 1, TokensPerMinute: 600})
+	ctx := context.Background()
+
+	require.NoError(t, limiter.LimitRequest(ctx, newRequest("tenant-a", 100, 0)))
+	require.NoError(t, limiter.LimitRequest(ctx, newRequest("tenant-b", 100, 0)))
+	assert.Len(t, limiter.limiters, 2)
+	assert.Len(t, limiter.reservations, 2)
+
+	// The limiters of the open reservations are kept although they refilled, so that their charges can be corrected.
+	clock.now = clock.now.Add(2 * sweepInterval)
+	require.NoError(t, limiter.LimitRequest(ctx, newRequest("tenant-c", 100, 0)))
+	assert.Len(t, limiter.limiters, 3)
+	assert.Len(t, limiter.reservations, 3)
+
+	// The reservations of the requests that never completed expire, and the limiters that refilled are dropped.
+	clock.now = clock.now.Add(reservationTimeout + time.Second)
+	require.NoError(t, limiter.LimitRequest(ctx, newRequest("tenant-d", 100, 0)))
+	assert.Len(t, limiter.limiters, 1)
+	assert.Len(t, limiter.reservations, 1)
+}
+
+func TestTokenBucketRateLimiterFactory(t *testing.T) {
+	tests := []struct {
+		name       string
+		jsonParams string
+		want       Parameters
+		expectErr  bool
+	}{
+		{name: "no budgets", want: Parameters{}},
+		{
+			name:       "budgets",
+			jsonParams: `{"budgets": [{"fairnessID": "team-*", "requestsPerSecond": 5, "requestBurst": 10, "tokensPerMinute": 100000}]}`,
+			want: Parameters{Budgets: []Budget{
+				{FairnessID: "team-*", RequestsPerSecond: 5, RequestBurst: 10, TokensPerMinute: 100000},
+			}},
+		},
+		{name: "missing fairness ID", jsonParams: `{"budgets": [{"requestsPerSecond": 5}]}`, expectErr: true},
+		{name: "invalid pattern", jsonParams: `{"budgets": [{"fairnessID": "team-[", "requestsPerSecond": 5}]}`, expectErr: true},
+		{name: "no limit", jsonParams: `{"budgets": [{"fairnessID": "team-a"}]}`, expectErr: true},
+		{name: "negative limit", jsonParams: `{"budgets": [{"fairnessID": "team-a", "tokensPerMinute": -1}]}`, expectErr: true},
+		{name: "invalid json", jsonParams: `{"budgets": {}}`, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plugin, err := TokenBucketRateLimiterFactory("limiter", []byte(tt.jsonParams), nil)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			limiter := plugin.(*TokenBucketRateLimiter)
+			assert.Equal(t, "limiter", limiter.TypedName().Name)
+			assert.Equal(t, tt.want, limiter.params)
+		})
+	}
+}
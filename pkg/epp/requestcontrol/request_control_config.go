@@ -17,6 +17,8 @@ limitations under the License.
 package requestcontrol
 
 import (
+	"time"
+
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/plugin"
 	fwk "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/requestcontrol"
 )
@@ -25,6 +27,7 @@ import (
 func NewConfig() *Config {
 	return &Config{
 		admissionPlugins:         []fwk.AdmissionPlugin{},
+		rateLimiters:             []fwk.RateLimiter{},
 		prepareDataPlugins:       []fwk.PrepareDataPlugin{},
 		preRequestPlugins:        []fwk.PreRequest{},
 		responseReceivedPlugins:  []fwk.ResponseReceived{},
@@ -36,11 +39,15 @@ func NewConfig() *Config {
 // Config provides a configuration for the requestcontrol plugins.
 type Config struct {
 	admissionPlugins         []fwk.AdmissionPlugin
+	rateLimiters             []fwk.RateLimiter
 	prepareDataPlugins       []fwk.PrepareDataPlugin
 	preRequestPlugins        []fwk.PreRequest
 	responseReceivedPlugins  []fwk.ResponseReceived
 	responseStreamingPlugins []fwk.ResponseStreaming
 	responseCompletePlugins  []fwk.ResponseComplete
+	// defaultRequestTTL is the TTL of the requests whose InferenceObjective sets none. It bounds the TTL requested
+	// by their clients.
+	defaultRequestTTL time.Duration
 }
 
 // WithPreRequestPlugins sets the given plugins as the PreRequest plugins.
@@ -71,6 +78,13 @@ func (c *Config) WithResponseCompletePlugins(plugins ...fwk.ResponseComplete) *C
 	return c
 }
 
+// WithDefaultRequestTTL sets the default TTL of the Flow Control requests, which bounds the TTL requested by the
+// clients of the requests whose InferenceObjective sets none.
+func (c *Config) WithDefaultRequestTTL(ttl time.Duration) *Config {
+	c.defaultRequestTTL = ttl
+	return c
+}
+
 // WithPrepareDataPlugins sets the given plugins as the PrepareData plugins.
 func (c *Config) WithPrepareDataPlugins(plugins ...fwk.PrepareDataPlugin) *Config {
 	c.prepareDataPlugins = plugins
@@ -83,6 +97,12 @@ func (c *Config) WithAdmissionPlugins(plugins ...fwk.AdmissionPlugin) *Config {
 	return c
 }
 
+// WithRateLimiters sets the given plugins as the RateLimiter plugins.
+func (c *Config) WithRateLimiters(plugins ...fwk.RateLimiter) *Config {
+	c.rateLimiters = plugins
+	return c
+}
+
 // AddPlugins adds the given plugins to the Config.
 // The type of each plugin is checked and added to the corresponding list of plugins in the Config.
 // If a plugin implements multiple plugin interfaces, it will be added to each corresponding list.
@@ -103,6 +123,12 @@ func (c *Config) AddPlugins(pluginObjects ...plugin.Plugin) {
 		if prepareDataPlugin, ok := plugin.(fwk.PrepareDataPlugin); ok {
 			c.prepareDataPlugins = append(c.prepareDataPlugins, prepareDataPlugin)
 		}
+		if admissionPlugin, ok := plugin.(fwk.AdmissionPlugin); ok {
+			c.admissionPlugins = append(c.admissionPlugins, admissionPlugin)
+		}
+		if rateLimiter, ok := plugin.(fwk.RateLimiter); ok {
+			c.rateLimiters = append(c.rateLimiters, rateLimiter)
+		}
 	}
 }
 
@@ -0,0 +1,61 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"slices"
+
+	framework "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/scheduling"
+)
+
+// thresholdFilterParameters defines the common parameters of the threshold based filters.
+type thresholdFilterParameters[T int | float64] struct {
+	// Threshold is the maximal load of an endpoint for it to pass the filter.
+	Threshold *T `json:"threshold"`
+	// FallbackLeastLoaded is the number of least loaded endpoints to keep when all endpoints exceed the threshold.
+	// A value of 0 disables the fallback, in which case no endpoint is kept.
+	FallbackLeastLoaded int `json:"fallbackLeastLoaded"`
+}
+
+// filterByLoad returns the endpoints whose load does not exceed the threshold. When no endpoint qualifies,
+// it returns up to fallbackLeastLoaded endpoints with the lowest load, in ascending order of load.
+func filterByLoad[T int | float64](endpoints []framework.Endpoint, load func(framework.Endpoint) T, threshold T,
+	fallbackLeastLoaded int) []framework.Endpoint {
+	filtered := []framework.Endpoint{}
+	for _, endpoint := range endpoints {
+		if load(endpoint) <= threshold {
+			filtered = append(filtered, endpoint)
+		}
+	}
+	if len(filtered) > 0 || fallbackLeastLoaded <= 0 {
+		return filtered
+	}
+
+	leastLoaded := slices.Clone(endpoints)
+	slices.SortStableFunc(leastLoaded, func(a, b framework.Endpoint) int {
+		loadA, loadB := load(a), load(b)
+		switch {
+		case loadA < loadB:
+			return -1
+		case loadA > loadB:
+			return 1
+		default:
+			return 0
+		}
+	})
+	return leastLoaded[:min(fallbackLeastLoaded, len(leastLoaded))]
+}
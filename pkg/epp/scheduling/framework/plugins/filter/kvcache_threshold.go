@@ -0,0 +1,93 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	fwkplugin "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/plugin"
+	framework "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/scheduling"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/saturationdetector/framework/plugins/utilizationdetector"
+)
+
+const (
+	KVCacheThresholdFilterType = "kv-cache-threshold-filter"
+)
+
+// compile-time type assertion
+var _ framework.Filter = &KVCacheThresholdFilter{}
+
+// KVCacheThresholdFilterFactory defines the factory function for KVCacheThresholdFilter.
+func KVCacheThresholdFilterFactory(name string, rawParameters json.RawMessage, _ fwkplugin.Handle) (fwkplugin.Plugin, error) {
+	parameters := thresholdFilterParameters[float64]{}
+	if rawParameters != nil {
+		if err := json.Unmarshal(rawParameters, &parameters); err != nil {
+			return nil, fmt.Errorf("failed to parse the parameters of the '%s' filter - %w", KVCacheThresholdFilterType, err)
+		}
+	}
+
+	threshold := utilizationdetector.DefaultKVCacheUtilThreshold
+	if parameters.Threshold != nil {
+		threshold = *parameters.Threshold
+		if threshold < 0 || threshold > 1 {
+			return nil, fmt.Errorf("'%s' filter threshold must be between 0 and 1, got %v", KVCacheThresholdFilterType, threshold)
+		}
+	}
+	if parameters.FallbackLeastLoaded < 0 {
+		return nil, fmt.Errorf("'%s' filter fallbackLeastLoaded must not be negative, got %d", KVCacheThresholdFilterType,
+			parameters.FallbackLeastLoaded)
+	}
+
+	return NewKVCacheThresholdFilter(threshold, parameters.FallbackLeastLoaded).WithName(name), nil
+}
+
+// NewKVCacheThresholdFilter initializes a new KVCacheThresholdFilter and returns its pointer.
+func NewKVCacheThresholdFilter(threshold float64, fallbackLeastLoaded int) *KVCacheThresholdFilter {
+	return &KVCacheThresholdFilter{
+		typedName:           fwkplugin.TypedName{Type: KVCacheThresholdFilterType, Name: KVCacheThresholdFilterType},
+		threshold:           threshold,
+		fallbackLeastLoaded: fallbackLeastLoaded,
+	}
+}
+
+// KVCacheThresholdFilter keeps the endpoints whose KV cache utilization does not exceed the threshold.
+// When all endpoints exceed the threshold, it optionally keeps the endpoints with the lowest utilization.
+type KVCacheThresholdFilter struct {
+	typedName           fwkplugin.TypedName
+	threshold           float64
+	fallbackLeastLoaded int
+}
+
+// TypedName returns the type and name tuple of this plugin instance.
+func (f *KVCacheThresholdFilter) TypedName() fwkplugin.TypedName {
+	return f.typedName
+}
+
+// WithName sets the name of the filter.
+func (f *KVCacheThresholdFilter) WithName(name string) *KVCacheThresholdFilter {
+	f.typedName.Name = name
+	return f
+}
+
+// Filter returns the endpoints with a KV cache utilization within the threshold.
+func (f *KVCacheThresholdFilter) Filter(_ context.Context, _ *framework.CycleState, _ *framework.LLMRequest, endpoints []framework.Endpoint) []framework.Endpoint {
+	return filterByLoad(endpoints, func(endpoint framework.Endpoint) float64 {
+		return endpoint.GetMetrics().KVCacheUsagePercent
+	}, f.threshold, f.fallbackLeastLoaded)
+}
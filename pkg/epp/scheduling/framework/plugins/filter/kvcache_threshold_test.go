@@ -0,0 +1,98 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/assert"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datalayer"
+	types "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/scheduling"
+)
+
+func TestKVCacheThresholdFilter(t *testing.T) {
+	low := newTestEndpoint("10.0.0.1", nil, datalayer.Metrics{KVCacheUsagePercent: 0.2})
+	high := newTestEndpoint("10.0.0.2", nil, datalayer.Metrics{KVCacheUsagePercent: 0.85})
+	full := newTestEndpoint("10.0.0.3", nil, datalayer.Metrics{KVCacheUsagePercent: 0.99})
+
+	tests := []struct {
+		name   string
+		filter *KVCacheThresholdFilter
+		input  []types.Endpoint
+		output []types.Endpoint
+	}{
+		{
+			name:   "endpoints within the threshold are kept",
+			filter: NewKVCacheThresholdFilter(0.8, 0),
+			input:  []types.Endpoint{full, low, high},
+			output: []types.Endpoint{low},
+		},
+		{
+			name:   "no endpoint kept without fallback",
+			filter: NewKVCacheThresholdFilter(0.8, 0),
+			input:  []types.Endpoint{full, high},
+			output: []types.Endpoint{},
+		},
+		{
+			name:   "least loaded endpoint kept with fallback",
+			filter: NewKVCacheThresholdFilter(0.8, 1),
+			input:  []types.Endpoint{full, high},
+			output: []types.Endpoint{high},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.filter.Filter(context.Background(), types.NewCycleState(), &types.LLMRequest{}, test.input)
+			if diff := cmp.Diff(test.output, got); diff != "" {
+				t.Errorf("Unexpected output (-want +got): %v", diff)
+			}
+		})
+	}
+}
+
+func TestKVCacheThresholdFilterFactory(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  string
+		wantErr bool
+	}{
+		{name: "defaults", params: ""},
+		{name: "custom", params: `{"threshold": 0.9, "fallbackLeastLoaded": 2}`},
+		{name: "threshold out of range", params: `{"threshold": 1.2}`, wantErr: true},
+		{name: "negative fallback", params: `{"fallbackLeastLoaded": -1}`, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var raw json.RawMessage
+			if test.params != "" {
+				raw = json.RawMessage(test.params)
+			}
+			_, err := KVCacheThresholdFilterFactory("kv", raw, nil)
+			if test.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
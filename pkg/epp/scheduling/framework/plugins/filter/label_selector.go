@@ -0,0 +1,172 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/common/util/logging"
+	fwkplugin "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/plugin"
+	framework "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/scheduling"
+)
+
+const (
+	LabelSelectorFilterType = "label-selector-filter"
+
+	// DefaultSelectorHeader is the default request header carrying a dynamic label selector, in the Kubernetes
+	// label selector syntax (e.g. "gpu=h100,zone in (us-east1-b,us-east1-c)").
+	DefaultSelectorHeader = "x-gateway-endpoint-selector"
+	// DefaultPreferredTopologyHeader is the default request header carrying the preferred value of the topology label.
+	DefaultPreferredTopologyHeader = "x-gateway-endpoint-topology"
+)
+
+// compile-time type assertion
+var _ framework.Filter = &LabelSelectorFilter{}
+
+// labelSelectorFilterParameters defines the parameters of the LabelSelectorFilter.
+type labelSelectorFilterParameters struct {
+	// Selector is a static label selector that endpoints must match.
+	Selector *metav1.LabelSelector `json:"selector"`
+	// SelectorHeader is the request header carrying a dynamic label selector that endpoints must also match.
+	SelectorHeader string `json:"selectorHeader"`
+	// TopologyKey is the label used for the "prefer same topology, else any" semantics, e.g. topology.kubernetes.io/zone.
+	TopologyKey string `json:"topologyKey"`
+	// PreferredTopology is the static preferred value of the TopologyKey label.
+	PreferredTopology string `json:"preferredTopology"`
+	// PreferredTopologyHeader is the request header overriding PreferredTopology.
+	PreferredTopologyHeader string `json:"preferredTopologyHeader"`
+}
+
+// LabelSelectorFilterFactory defines the factory function for LabelSelectorFilter.
+func LabelSelectorFilterFactory(name string, rawParameters json.RawMessage, _ fwkplugin.Handle) (fwkplugin.Plugin, error) {
+	parameters := labelSelectorFilterParameters{
+		SelectorHeader:          DefaultSelectorHeader,
+		PreferredTopologyHeader: DefaultPreferredTopologyHeader,
+	}
+	if rawParameters != nil {
+		if err := json.Unmarshal(rawParameters, &parameters); err != nil {
+			return nil, fmt.Errorf("failed to parse the parameters of the '%s' filter - %w", LabelSelectorFilterType, err)
+		}
+	}
+
+	selector := labels.Everything()
+	if parameters.Selector != nil {
+		var err error
+		if selector, err = metav1.LabelSelectorAsSelector(parameters.Selector); err != nil {
+			return nil, fmt.Errorf("invalid selector in the parameters of the '%s' filter - %w", LabelSelectorFilterType, err)
+		}
+	}
+	if parameters.TopologyKey == "" && parameters.PreferredTopology != "" {
+		return nil, errors.New("preferredTopology requires topologyKey to be set")
+	}
+
+	return NewLabelSelectorFilter(selector, parameters.SelectorHeader, parameters.TopologyKey, parameters.PreferredTopology,
+		parameters.PreferredTopologyHeader).WithName(name), nil
+}
+
+// NewLabelSelectorFilter initializes a new LabelSelectorFilter and returns its pointer.
+// Empty header names disable the corresponding dynamic selection, and an empty topologyKey disables the
+// topology preference.
+func NewLabelSelectorFilter(selector labels.Selector, selectorHeader, topologyKey, preferredTopology,
+	preferredTopologyHeader string) *LabelSelectorFilter {
+	return &LabelSelectorFilter{
+		typedName:               fwkplugin.TypedName{Type: LabelSelectorFilterType, Name: LabelSelectorFilterType},
+		selector:                selector,
+		selectorHeader:          selectorHeader,
+		topologyKey:             topologyKey,
+		preferredTopology:       preferredTopology,
+		preferredTopologyHeader: preferredTopologyHeader,
+	}
+}
+
+// LabelSelectorFilter keeps the endpoints whose labels match both the static selector and the dynamic selector
+// sent in the request header. When a topology key is configured, endpoints in the preferred topology are kept
+// if there are any, and otherwise all matching endpoints are kept.
+type LabelSelectorFilter struct {
+	typedName               fwkplugin.TypedName
+	selector                labels.Selector
+	selectorHeader          string
+	topologyKey             string
+	preferredTopology       string
+	preferredTopologyHeader string
+}
+
+// TypedName returns the type and name tuple of this plugin instance.
+func (f *LabelSelectorFilter) TypedName() fwkplugin.TypedName {
+	return f.typedName
+}
+
+// WithName sets the name of the filter.
+func (f *LabelSelectorFilter) WithName(name string) *LabelSelectorFilter {
+	f.typedName.Name = name
+	return f
+}
+
+// Filter returns the endpoints matching the selectors, narrowed to the preferred topology when possible.
+// A malformed selector in the request header is ignored.
+func (f *LabelSelectorFilter) Filter(ctx context.Context, _ *framework.CycleState, request *framework.LLMRequest, endpoints []framework.Endpoint) []framework.Endpoint {
+	selector := f.selector
+	if value := f.headerValue(request, f.selectorHeader); value != "" {
+		dynamicSelector, err := labels.Parse(value)
+		if err != nil {
+			log.FromContext(ctx).V(logutil.DEFAULT).Info("Ignoring invalid endpoint selector header", "header", f.selectorHeader,
+				"value", value, "error", err)
+		} else if requirements, selectable := dynamicSelector.Requirements(); selectable {
+			selector = selector.Add(requirements...)
+		}
+	}
+
+	selected := []framework.Endpoint{}
+	for _, endpoint := range endpoints {
+		if selector.Matches(labels.Set(endpoint.GetMetadata().Labels)) {
+			selected = append(selected, endpoint)
+		}
+	}
+
+	preferredTopology := f.preferredTopology
+	if value := f.headerValue(request, f.preferredTopologyHeader); value != "" {
+		preferredTopology = value
+	}
+	if f.topologyKey == "" || preferredTopology == "" {
+		return selected
+	}
+
+	sameTopology := []framework.Endpoint{}
+	for _, endpoint := range selected {
+		if endpoint.GetMetadata().Labels[f.topologyKey] == preferredTopology {
+			sameTopology = append(sameTopology, endpoint)
+		}
+	}
+	if len(sameTopology) == 0 {
+		return selected
+	}
+	return sameTopology
+}
+
+func (f *LabelSelectorFilter) headerValue(request *framework.LLMRequest, header string) string {
+	if header == "" || request == nil {
+		return ""
+	}
+	return request.Headers[header]
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datalayer"
+	types "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/scheduling"
+)
+
+const testZoneKey = "topology.kubernetes.io/zone"
+
+func TestLabelSelectorFilter(t *testing.T) {
+	h100ZoneA := newTestEndpoint("10.0.0.1", map[string]string{"gpu": "h100", testZoneKey: "a"}, datalayer.Metrics{})
+	h100ZoneB := newTestEndpoint("10.0.0.2", map[string]string{"gpu": "h100", testZoneKey: "b"}, datalayer.Metrics{})
+	a100ZoneA := newTestEndpoint("10.0.0.3", map[string]string{"gpu": "a100", testZoneKey: "a"}, datalayer.Metrics{})
+	all := []types.Endpoint{h100ZoneA, h100ZoneB, a100ZoneA}
+
+	tests := []struct {
+		name    string
+		filter  *LabelSelectorFilter
+		headers map[string]string
+		output  []types.Endpoint
+	}{
+		{
+			name:   "no selector keeps all endpoints",
+			filter: NewLabelSelectorFilter(labels.Everything(), DefaultSelectorHeader, "", "", ""),
+			output: all,
+		},
+		{
+			name:   "static selector",
+			filter: NewLabelSelectorFilter(labels.SelectorFromSet(labels.Set{"gpu": "h100"}), DefaultSelectorHeader, "", "", ""),
+			output: []types.Endpoint{h100ZoneA, h100ZoneB},
+		},
+		{
+			name:    "dynamic selector is combined with the static selector",
+			filter:  NewLabelSelectorFilter(labels.SelectorFromSet(labels.Set{"gpu": "h100"}), DefaultSelectorHeader, "", "", ""),
+			headers: map[string]string{DefaultSelectorHeader: testZoneKey + " in (b, c)"},
+			output:  []types.Endpoint{h100ZoneB},
+		},
+		{
+			name:    "invalid dynamic selector is ignored",
+			filter:  NewLabelSelectorFilter(labels.SelectorFromSet(labels.Set{"gpu": "h100"}), DefaultSelectorHeader, "", "", ""),
+			headers: map[string]string{DefaultSelectorHeader: "gpu in ("},
+			output:  []types.Endpoint{h100ZoneA, h100ZoneB},
+		},
+		{
+			name:   "static preferred topology",
+			filter: NewLabelSelectorFilter(labels.Everything(), DefaultSelectorHeader, testZoneKey, "a", DefaultPreferredTopologyHeader),
+			output: []types.Endpoint{h100ZoneA, a100ZoneA},
+		},
+		{
+			name:    "preferred topology from header",
+			filter:  NewLabelSelectorFilter(labels.Everything(), DefaultSelectorHeader, testZoneKey, "a", DefaultPreferredTopologyHeader),
+			headers: map[string]string{DefaultPreferredTopologyHeader: "b"},
+			output:  []types.Endpoint{h100ZoneB},
+		},
+		{
+			name:    "falls back to any topology",
+			filter:  NewLabelSelectorFilter(labels.Everything(), DefaultSelectorHeader, testZoneKey, "a", DefaultPreferredTopologyHeader),
+			headers: map[string]string{DefaultSelectorHeader: "gpu=h100", DefaultPreferredTopologyHeader: "c"},
+			output:  []types.Endpoint{h100ZoneA, h100ZoneB},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request := &types.LLMRequest{Headers: test.headers}
+			got := test.filter.Filter(context.Background(), types.NewCycleState(), request, all)
+			if diff := cmp.Diff(test.output, got); diff != "" {
+				t.Errorf("Unexpected output (-want +got): %v", diff)
+			}
+		})
+	}
+}
+
+func TestLabelSelectorFilterFactory(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  string
+		wantErr bool
+	}{
+		{name: "defaults", params: ""},
+		{name: "custom", params: `{"selector": {"matchLabels": {"gpu": "h100"}}, "topologyKey": "zone", "preferredTopology": "a"}`},
+		{name: "invalid selector", params: `{"selector": {"matchExpressions": [{"key": "gpu", "operator": "Bad"}]}}`, wantErr: true},
+		{name: "preferred topology without key", params: `{"preferredTopology": "a"}`, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var raw json.RawMessage
+			if test.params != "" {
+				raw = json.RawMessage(test.params)
+			}
+			_, err := LabelSelectorFilterFactory("labels", raw, nil)
+			if test.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/common/util/logging"
+	fwkplugin "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/plugin"
+	framework "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/scheduling"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metrics"
+)
+
+const (
+	LoraAffinityFilterType = "lora-affinity-filter"
+
+	// DefaultLoraAffinityThreshold is the default probability of keeping only the endpoints that already have the
+	// adapter active or waiting, over the endpoints that can load it. A value below 1 lets the adapter spread
+	// to more endpoints over time.
+	DefaultLoraAffinityThreshold = 0.999
+)
+
+// compile-time type assertion
+var _ framework.Filter = &LoraAffinityFilter{}
+
+// loraAffinityFilterParameters defines the parameters of the LoraAffinityFilter.
+type loraAffinityFilterParameters struct {
+	LoraAffinityThreshold *float64 `json:"loraAffinityThreshold"`
+}
+
+// LoraAffinityFilterFactory defines the factory function for LoraAffinityFilter.
+func LoraAffinityFilterFactory(name string, rawParameters json.RawMessage, _ fwkplugin.Handle) (fwkplugin.Plugin, error) {
+	parameters := loraAffinityFilterParameters{}
+	if rawParameters != nil {
+		if err := json.Unmarshal(rawParameters, &parameters); err != nil {
+			return nil, fmt.Errorf("failed to parse the parameters of the '%s' filter - %w", LoraAffinityFilterType, err)
+		}
+	}
+
+	threshold := DefaultLoraAffinityThreshold
+	if parameters.LoraAffinityThreshold != nil {
+		threshold = *parameters.LoraAffinityThreshold
+		if threshold < 0 || threshold > 1 {
+			return nil, fmt.Errorf("'%s' filter loraAffinityThreshold must be between 0 and 1, got %v", LoraAffinityFilterType, threshold)
+		}
+	}
+
+	return NewLoraAffinityFilter(threshold).WithName(name), nil
+}
+
+// NewLoraAffinityFilter initializes a new LoraAffinityFilter and returns its pointer.
+func NewLoraAffinityFilter(loraAffinityThreshold float64) *LoraAffinityFilter {
+	return &LoraAffinityFilter{
+		typedName:             fwkplugin.TypedName{Type: LoraAffinityFilterType, Name: LoraAffinityFilterType},
+		loraAffinityThreshold: loraAffinityThreshold,
+	}
+}
+
+// LoraAffinityFilter keeps the endpoints that can serve the requested LoRA adapter without evicting another one.
+// Endpoints that have the adapter active or waiting to be loaded are preferred, with probability loraAffinityThreshold,
+// over endpoints that have capacity to load it. When no endpoint qualifies, all endpoints are kept.
+type LoraAffinityFilter struct {
+	typedName             fwkplugin.TypedName
+	loraAffinityThreshold float64
+}
+
+// TypedName returns the type and name tuple of this plugin instance.
+func (f *LoraAffinityFilter) TypedName() fwkplugin.TypedName {
+	return f.typedName
+}
+
+// WithName sets the name of the filter.
+func (f *LoraAffinityFilter) WithName(name string) *LoraAffinityFilter {
+	f.typedName.Name = name
+	return f
+}
+
+// Consumes returns the list of data that is consumed by the plugin.
+func (f *LoraAffinityFilter) Consumes() map[string]any {
+	return map[string]any{
+		metrics.ActiveModelsKey:  map[string]int{},
+		metrics.WaitingModelsKey: map[string]int{},
+	}
+}
+
+// Filter returns the endpoints that have the adapter, or else the endpoints that can load it.
+func (f *LoraAffinityFilter) Filter(ctx context.Context, _ *framework.CycleState, request *framework.LLMRequest, endpoints []framework.Endpoint) []framework.Endpoint {
+	withAffinity := []framework.Endpoint{}
+	withCapacity := []framework.Endpoint{}
+
+	for _, endpoint := range endpoints {
+		endpointMetrics := endpoint.GetMetrics()
+		_, active := endpointMetrics.ActiveModels[request.TargetModel]
+		_, waiting := endpointMetrics.WaitingModels[request.TargetModel]
+
+		switch {
+		case active || waiting:
+			withAffinity = append(withAffinity, endpoint)
+		case len(endpointMetrics.ActiveModels)+len(endpointMetrics.WaitingModels) < endpointMetrics.MaxActiveModels:
+			withCapacity = append(withCapacity, endpoint)
+		}
+	}
+
+	switch {
+	case len(withAffinity) > 0 && (len(withCapacity) == 0 || rand.Float64() < f.loraAffinityThreshold):
+		return withAffinity
+	case len(withCapacity) > 0:
+		return withCapacity
+	default:
+		log.FromContext(ctx).V(logutil.DEBUG).Info("No endpoint can serve the adapter without eviction, keeping all endpoints",
+			"targetModel", request.TargetModel)
+		return endpoints
+	}
+}
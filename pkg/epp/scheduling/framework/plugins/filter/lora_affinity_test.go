@@ -0,0 +1,141 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/assert"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datalayer"
+	types "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/scheduling"
+)
+
+func TestLoraAffinityFilter(t *testing.T) {
+	active := &types.PodMetrics{
+		EndpointMetadata: &datalayer.EndpointMetadata{Address: "10.0.0.1"},
+		Metrics: &datalayer.Metrics{
+			ActiveModels:    map[string]int{"adapter-1": 1},
+			WaitingModels:   map[string]int{},
+			MaxActiveModels: 2,
+		},
+	}
+	waiting := &types.PodMetrics{
+		EndpointMetadata: &datalayer.EndpointMetadata{Address: "10.0.0.2"},
+		Metrics: &datalayer.Metrics{
+			ActiveModels:    map[string]int{"adapter-2": 1},
+			WaitingModels:   map[string]int{"adapter-1": 1},
+			MaxActiveModels: 2,
+		},
+	}
+	available := &types.PodMetrics{
+		EndpointMetadata: &datalayer.EndpointMetadata{Address: "10.0.0.3"},
+		Metrics: &datalayer.Metrics{
+			ActiveModels:    map[string]int{"adapter-2": 1},
+			WaitingModels:   map[string]int{},
+			MaxActiveModels: 2,
+		},
+	}
+	full := &types.PodMetrics{
+		EndpointMetadata: &datalayer.EndpointMetadata{Address: "10.0.0.4"},
+		Metrics: &datalayer.Metrics{
+			ActiveModels:    map[string]int{"adapter-2": 1, "adapter-3": 1},
+			WaitingModels:   map[string]int{},
+			MaxActiveModels: 2,
+		},
+	}
+
+	tests := []struct {
+		name      string
+		threshold float64
+		input     []types.Endpoint
+		output    []types.Endpoint
+	}{
+		{
+			name:      "endpoints with the adapter are preferred",
+			threshold: 1,
+			input:     []types.Endpoint{active, waiting, available, full},
+			output:    []types.Endpoint{active, waiting},
+		},
+		{
+			name:      "endpoints that can load the adapter are picked below the threshold",
+			threshold: 0,
+			input:     []types.Endpoint{active, waiting, available, full},
+			output:    []types.Endpoint{available},
+		},
+		{
+			name:      "endpoints with the adapter are kept when none can load it",
+			threshold: 0,
+			input:     []types.Endpoint{waiting, full},
+			output:    []types.Endpoint{waiting},
+		},
+		{
+			name:      "endpoints that can load the adapter when none has it",
+			threshold: 1,
+			input:     []types.Endpoint{available, full},
+			output:    []types.Endpoint{available},
+		},
+		{
+			name:      "all endpoints are kept when none qualifies",
+			threshold: 1,
+			input:     []types.Endpoint{full},
+			output:    []types.Endpoint{full},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			filter := NewLoraAffinityFilter(test.threshold)
+			got := filter.Filter(context.Background(), types.NewCycleState(), &types.LLMRequest{TargetModel: "adapter-1"}, test.input)
+
+			if diff := cmp.Diff(test.output, got); diff != "" {
+				t.Errorf("Unexpected output (-want +got): %v", diff)
+			}
+		})
+	}
+}
+
+func TestLoraAffinityFilterFactory(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  string
+		wantErr bool
+	}{
+		{name: "defaults", params: ""},
+		{name: "custom threshold", params: `{"loraAffinityThreshold": 0.5}`},
+		{name: "threshold out of range", params: `{"loraAffinityThreshold": 1.5}`, wantErr: true},
+		{name: "invalid json", params: `{`, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var raw json.RawMessage
+			if test.params != "" {
+				raw = json.RawMessage(test.params)
+			}
+			_, err := LoraAffinityFilterFactory("lora", raw, nil)
+			if test.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	fwkplugin "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/plugin"
+	framework "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/scheduling"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/saturationdetector/framework/plugins/utilizationdetector"
+)
+
+const (
+	LowQueueFilterType = "low-queue-filter"
+)
+
+// compile-time type assertion
+var _ framework.Filter = &LowQueueFilter{}
+
+// LowQueueFilterFactory defines the factory function for LowQueueFilter.
+func LowQueueFilterFactory(name string, rawParameters json.RawMessage, _ fwkplugin.Handle) (fwkplugin.Plugin, error) {
+	parameters := thresholdFilterParameters[int]{}
+	if rawParameters != nil {
+		if err := json.Unmarshal(rawParameters, &parameters); err != nil {
+			return nil, fmt.Errorf("failed to parse the parameters of the '%s' filter - %w", LowQueueFilterType, err)
+		}
+	}
+
+	threshold := utilizationdetector.DefaultQueueDepthThreshold
+	if parameters.Threshold != nil {
+		threshold = *parameters.Threshold
+		if threshold < 0 {
+			return nil, fmt.Errorf("'%s' filter threshold must not be negative, got %d", LowQueueFilterType, threshold)
+		}
+	}
+	if parameters.FallbackLeastLoaded < 0 {
+		return nil, fmt.Errorf("'%s' filter fallbackLeastLoaded must not be negative, got %d", LowQueueFilterType,
+			parameters.FallbackLeastLoaded)
+	}
+
+	return NewLowQueueFilter(threshold, parameters.FallbackLeastLoaded).WithName(name), nil
+}
+
+// NewLowQueueFilter initializes a new LowQueueFilter and returns its pointer.
+func NewLowQueueFilter(threshold int, fallbackLeastLoaded int) *LowQueueFilter {
+	return &LowQueueFilter{
+		typedName:           fwkplugin.TypedName{Type: LowQueueFilterType, Name: LowQueueFilterType},
+		threshold:           threshold,
+		fallbackLeastLoaded: fallbackLeastLoaded,
+	}
+}
+
+// LowQueueFilter keeps the endpoints whose waiting queue size does not exceed the threshold.
+// When all endpoints exceed the threshold, it optionally keeps the endpoints with the shortest queues.
+type LowQueueFilter struct {
+	typedName           fwkplugin.TypedName
+	threshold           int
+	fallbackLeastLoaded int
+}
+
+// TypedName returns the type and name tuple of this plugin instance.
+func (f *LowQueueFilter) TypedName() fwkplugin.TypedName {
+	return f.typedName
+}
+
+// WithName sets the name of the filter.
+func (f *LowQueueFilter) WithName(name string) *LowQueueFilter {
+	f.typedName.Name = name
+	return f
+}
+
+// Filter returns the endpoints with a waiting queue size within the threshold.
+func (f *LowQueueFilter) Filter(_ context.Context, _ *framework.CycleState, _ *framework.LLMRequest, endpoints []framework.Endpoint) []framework.Endpoint {
+	return filterByLoad(endpoints, func(endpoint framework.Endpoint) int {
+		return endpoint.GetMetrics().WaitingQueueSize
+	}, f.threshold, f.fallbackLeastLoaded)
+}
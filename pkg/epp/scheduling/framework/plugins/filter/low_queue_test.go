@@ -0,0 +1,105 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/assert"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datalayer"
+	types "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/scheduling"
+)
+
+func TestLowQueueFilter(t *testing.T) {
+	short := newTestEndpoint("10.0.0.1", nil, datalayer.Metrics{WaitingQueueSize: 2})
+	medium := newTestEndpoint("10.0.0.2", nil, datalayer.Metrics{WaitingQueueSize: 8})
+	long := newTestEndpoint("10.0.0.3", nil, datalayer.Metrics{WaitingQueueSize: 12})
+	longest := newTestEndpoint("10.0.0.4", nil, datalayer.Metrics{WaitingQueueSize: 20})
+
+	tests := []struct {
+		name   string
+		filter *LowQueueFilter
+		input  []types.Endpoint
+		output []types.Endpoint
+	}{
+		{
+			name:   "endpoints within the threshold are kept",
+			filter: NewLowQueueFilter(8, 0),
+			input:  []types.Endpoint{long, short, medium},
+			output: []types.Endpoint{short, medium},
+		},
+		{
+			name:   "no endpoint kept without fallback",
+			filter: NewLowQueueFilter(1, 0),
+			input:  []types.Endpoint{long, short, medium},
+			output: []types.Endpoint{},
+		},
+		{
+			name:   "least loaded endpoints kept with fallback",
+			filter: NewLowQueueFilter(1, 2),
+			input:  []types.Endpoint{longest, long, short, medium},
+			output: []types.Endpoint{short, medium},
+		},
+		{
+			name:   "fallback larger than the number of endpoints",
+			filter: NewLowQueueFilter(1, 5),
+			input:  []types.Endpoint{long, short},
+			output: []types.Endpoint{short, long},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.filter.Filter(context.Background(), types.NewCycleState(), &types.LLMRequest{}, test.input)
+			if diff := cmp.Diff(test.output, got); diff != "" {
+				t.Errorf("Unexpected output (-want +got): %v", diff)
+			}
+		})
+	}
+}
+
+func TestLowQueueFilterFactory(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  string
+		wantErr bool
+	}{
+		{name: "defaults", params: ""},
+		{name: "custom", params: `{"threshold": 10, "fallbackLeastLoaded": 2}`},
+		{name: "negative threshold", params: `{"threshold": -1}`, wantErr: true},
+		{name: "negative fallback", params: `{"fallbackLeastLoaded": -1}`, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var raw json.RawMessage
+			if test.params != "" {
+				raw = json.RawMessage(test.params)
+			}
+			_, err := LowQueueFilterFactory("queue", raw, nil)
+			if test.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -29,6 +29,7 @@ import (
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metrics"
 
 	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/common/util/logging"
+	schedulingtypes "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/scheduling"
 	requtil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/request"
 	latencypredictor "sigs.k8s.io/gateway-api-inference-extension/sidecars/latencypredictorasync"
 )
@@ -85,7 +86,7 @@ func processPreRequestForLatencyPrediction(
 
 	in := latencypredictor.PredictionRequest{
 		KVCachePercentage:  m.KVCacheUsagePercent,
-		InputTokenLength:   len(strings.Fields(predictedLatencyCtx.schedulingRequest.Body.Completions.Prompt)),
+		InputTokenLength:   inputTokenLength(&predictedLatencyCtx.schedulingRequest),
 		NumRequestWaiting:  m.WaitingQueueSize,
 		NumRequestRunning:  m.RunningRequestsSize,
 		NumTokensGenerated: 0,
@@ -172,7 +173,7 @@ func recordTTFTTrainingData(
 	// Train TTFT
 	entry := latencypredictor.TrainingEntry{
 		KVCachePercentage:  m.KVCacheUsagePercent,
-		InputTokenLength:   len(strings.Fields(predictedLatencyCtx.schedulingRequest.Body.Completions.Prompt)),
+		InputTokenLength:   inputTokenLength(&predictedLatencyCtx.schedulingRequest),
 		ActualTTFT:         predictedLatencyCtx.ttft,
 		ActualTPOT:         0,
 		Timestamp:          now,
@@ -202,7 +203,7 @@ func predictFirstTPOT(
 	// Predict first TPOT
 	in := latencypredictor.PredictionRequest{
 		KVCachePercentage:  m.KVCacheUsagePercent,
-		InputTokenLength:   len(strings.Fields(predictedLatencyCtx.schedulingRequest.Body.Completions.Prompt)),
+		InputTokenLength:   inputTokenLength(&predictedLatencyCtx.schedulingRequest),
 		NumRequestWaiting:  m.WaitingQueueSize,
 		NumRequestRunning:  m.RunningRequestsSize,
 		NumTokensGenerated: predictedLatencyCtx.generatedTokenCount,
@@ -224,11 +225,13 @@ func predictFirstTPOT(
 }
 
 // processTokenForLatencyPrediction records actual inter-token latency, trains, predicts sampled TPOT, updates predictedLatencyCtx, and advances timestamp.
+// tokens is the number of tokens received at now, over which the latency since the previous token is spread.
 func processTokenForLatencyPrediction(
 	ctx context.Context,
 	predictor latencypredictor.PredictorInterface,
 	predictedLatencyCtx *predictedLatencyCtx,
 	now time.Time,
+	tokens int,
 	samplingMean float64,
 	maxSampledTokens int,
 ) {
@@ -241,8 +244,8 @@ func processTokenForLatencyPrediction(
 		logger.V(logutil.DEBUG).Info("Initialized token sampler for subsequent tokens", "request_id", requestID, "next_prediction_token", predictedLatencyCtx.tokenSampler.getNextSampleToken())
 	}
 
-	// Inter-token latency
-	latencyMs := float64(now.Sub(predictedLatencyCtx.lastTokenTimestamp).Milliseconds())
+	// Inter-token latency, spread over the tokens received at once.
+	latencyMs := float64(now.Sub(predictedLatencyCtx.lastTokenTimestamp).Milliseconds()) / float64(tokens)
 	predictedLatencyCtx.generatedTokenCount++
 
 	// log the inter-token latency for predicted samples
@@ -260,7 +263,7 @@ func processTokenForLatencyPrediction(
 	// Record actual TPOT
 	entry := latencypredictor.TrainingEntry{
 		KVCachePercentage:  m.KVCacheUsagePercent,
-		InputTokenLength:   len(strings.Fields(predictedLatencyCtx.schedulingRequest.Body.Completions.Prompt)),
+		InputTokenLength:   inputTokenLength(&predictedLatencyCtx.schedulingRequest),
 		ActualTTFT:         0,
 		ActualTPOT:         latencyMs,
 		Timestamp:          now,
@@ -277,7 +280,7 @@ func processTokenForLatencyPrediction(
 	if predictedLatencyCtx.tokenSampler.shouldPredict(predictedLatencyCtx.generatedTokenCount) {
 		in := latencypredictor.PredictionRequest{
 			KVCachePercentage:  m.KVCacheUsagePercent,
-			InputTokenLength:   len(strings.Fields(predictedLatencyCtx.schedulingRequest.Body.Completions.Prompt)),
+			InputTokenLength:   inputTokenLength(&predictedLatencyCtx.schedulingRequest),
 			NumRequestWaiting:  m.WaitingQueueSize,
 			NumRequestRunning:  m.RunningRequestsSize,
 			NumTokensGenerated: predictedLatencyCtx.generatedTokenCount,
@@ -306,22 +309,31 @@ func processTokenForLatencyPrediction(
 	refreshLastSeenMetrics(ctx, predictedLatencyCtx)
 }
 
+// inputTokenLength returns the number of input tokens of the request: exact if the prompt was sent as token IDs,
+// and estimated from its number of words otherwise.
+func inputTokenLength(request *schedulingtypes.LLMRequest) int {
+	if count := request.Body.TokenCount(); count > 0 {
+		return count
+	}
+	return len(strings.Fields(request.Body.PlainText()))
+}
+
 // bulkPredictWithMetrics performs bulk predictions for multiple pods using their metrics states.
 // Returns predictions in the same order as the input slices.
 func bulkPredictWithMetrics(
 	ctx context.Context,
 	predictor latencypredictor.PredictorInterface,
 	metricsStates []*datalayer.Metrics,
-	prompts []string,
+	inputTokenLengths []int,
 	generatedTokenCounts []int,
 	prefixCacheScores []float64,
 ) ([]*latencypredictor.PredictionResponse, error) {
 	logger := log.FromContext(ctx)
 
 	// Validate input lengths
-	if len(metricsStates) != len(prompts) || len(prompts) != len(generatedTokenCounts) || len(generatedTokenCounts) != len(prefixCacheScores) {
-		return nil, fmt.Errorf("input slice lengths must match: metrics=%d, prompts=%d, tokenCounts=%d, prefixScores=%d",
-			len(metricsStates), len(prompts), len(generatedTokenCounts), len(prefixCacheScores))
+	if len(metricsStates) != len(inputTokenLengths) || len(inputTokenLengths) != len(generatedTokenCounts) || len(generatedTokenCounts) != len(prefixCacheScores) {
+		return nil, fmt.Errorf("input slice lengths must match: metrics=%d, inputTokenLengths=%d, tokenCounts=%d, prefixScores=%d",
+			len(metricsStates), len(inputTokenLengths), len(generatedTokenCounts), len(prefixCacheScores))
 	}
 
 	if len(metricsStates) == 0 {
@@ -340,7 +352,7 @@ func bulkPredictWithMetrics(
 	for i := range metricsStates {
 		bulkRequests[i] = latencypredictor.PredictionRequest{
 			KVCachePercentage:  metricsStates[i].KVCacheUsagePercent,
-			InputTokenLength:   len(strings.Fields(prompts[i])),
+			InputTokenLength:   inputTokenLengths[i],
 			NumRequestWaiting:  metricsStates[i].WaitingQueueSize,
 			NumRequestRunning:  metricsStates[i].RunningRequestsSize,
 			NumTokensGenerated: generatedTokenCounts[i],
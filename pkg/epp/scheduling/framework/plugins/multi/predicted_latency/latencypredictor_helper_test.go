@@ -24,9 +24,22 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datalayer"
+	schedulingtypes "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/scheduling"
 	latencypredictor "sigs.k8s.io/gateway-api-inference-extension/sidecars/latencypredictorasync"
 )
 
+func TestInputTokenLength(t *testing.T) {
+	text := &schedulingtypes.LLMRequest{Body: &schedulingtypes.LLMRequestBody{
+		Completions: &schedulingtypes.CompletionsRequest{Prompt: schedulingtypes.Prompt{Raw: "a short test prompt"}},
+	}}
+	assert.Equal(t, 4, inputTokenLength(text), "text prompts are estimated from their words")
+
+	tokens := &schedulingtypes.LLMRequest{Body: &schedulingtypes.LLMRequestBody{
+		Completions: &schedulingtypes.CompletionsRequest{Prompt: schedulingtypes.Prompt{Tokens: [][]uint32{{1, 2, 3, 4, 5, 6}}}},
+	}}
+	assert.Equal(t, 6, inputTokenLength(tokens), "token IDs are counted exactly")
+}
+
 func TestBulkPredictWithMetrics(t *testing.T) {
 	mockPredictor := &mockPredictor{
 		predictions: map[string]*latencypredictor.PredictionResponse{
@@ -39,11 +52,11 @@ func TestBulkPredictWithMetrics(t *testing.T) {
 		{KVCacheUsagePercent: 0.5},
 		{KVCacheUsagePercent: 0.6},
 	}
-	prompts := []string{"prompt1", "prompt2"}
+	inputTokenLengths := []int{1, 1}
 	generatedTokenCounts := []int{1, 1}
 	prefixCacheScores := []float64{0.0, 0.0}
 
-	results, err := bulkPredictWithMetrics(context.Background(), mockPredictor, metricsStates, prompts, generatedTokenCounts, prefixCacheScores)
+	results, err := bulkPredictWithMetrics(context.Background(), mockPredictor, metricsStates, inputTokenLengths, generatedTokenCounts, prefixCacheScores)
 
 	assert.NoError(t, err)
 	assert.Len(t, results, 2)
@@ -61,11 +74,11 @@ func TestBulkPredictWithMetrics_Error(t *testing.T) {
 	metricsStates := []*datalayer.Metrics{
 		{KVCacheUsagePercent: 0.5},
 	}
-	prompts := []string{"prompt1"}
+	inputTokenLengths := []int{1}
 	generatedTokenCounts := []int{1}
 	prefixCacheScores := []float64{0.0}
 
-	results, err := bulkPredictWithMetrics(context.Background(), mockPredictor, metricsStates, prompts, generatedTokenCounts, prefixCacheScores)
+	results, err := bulkPredictWithMetrics(context.Background(), mockPredictor, metricsStates, inputTokenLengths, generatedTokenCounts, prefixCacheScores)
 
 	assert.Error(t, err)
 	assert.Nil(t, results)
@@ -74,11 +87,11 @@ func TestBulkPredictWithMetrics_Error(t *testing.T) {
 func TestBulkPredictWithMetrics_InputMismatch(t *testing.T) {
 	mockPredictor := &mockPredictor{}
 	metricsStates := []*datalayer.Metrics{{}}
-	prompts := []string{"prompt1", "prompt2"} // Mismatch length
+	inputTokenLengths := []int{1, 1} // Mismatch length
 	generatedTokenCounts := []int{1}
 	prefixCacheScores := []float64{0.0}
 
-	results, err := bulkPredictWithMetrics(context.Background(), mockPredictor, metricsStates, prompts, generatedTokenCounts, prefixCacheScores)
+	results, err := bulkPredictWithMetrics(context.Background(), mockPredictor, metricsStates, inputTokenLengths, generatedTokenCounts, prefixCacheScores)
 
 	assert.Error(t, err)
 	assert.Nil(t, results)
@@ -88,11 +101,11 @@ func TestBulkPredictWithMetrics_InputMismatch(t *testing.T) {
 func TestBulkPredictWithMetrics_NilMetricsState(t *testing.T) {
 	mockPredictor := &mockPredictor{}
 	metricsStates := []*datalayer.Metrics{nil} // Nil metrics state
-	prompts := []string{"prompt1"}
+	inputTokenLengths := []int{1}
 	generatedTokenCounts := []int{1}
 	prefixCacheScores := []float64{0.0}
 
-	results, err := bulkPredictWithMetrics(context.Background(), mockPredictor, metricsStates, prompts, generatedTokenCounts, prefixCacheScores)
+	results, err := bulkPredictWithMetrics(context.Background(), mockPredictor, metricsStates, inputTokenLengths, generatedTokenCounts, prefixCacheScores)
 
 	assert.Error(t, err)
 	assert.Nil(t, results)
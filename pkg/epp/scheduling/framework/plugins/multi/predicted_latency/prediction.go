@@ -47,7 +47,7 @@ func (s *PredictedLatency) generatePredictions(ctx context.Context, request *sch
 
 	// Prepare inputs for bulk prediction
 	metricsStates := make([]*datalayer.Metrics, len(candidateEndpoints))
-	prompts := make([]string, len(candidateEndpoints))
+	inputTokenLengths := make([]int, len(candidateEndpoints))
 	generatedTokenCounts := make([]int, len(candidateEndpoints))
 	prefixCacheScores := make([]float64, len(candidateEndpoints))
 
@@ -60,13 +60,13 @@ func (s *PredictedLatency) generatePredictions(ctx context.Context, request *sch
 		logger.V(logutil.DEBUG).Info("Prefix cache score for pod", "pod", endpoint.GetMetadata().String(), "prefixCacheScore", prefixCacheScore)
 
 		metricsStates[i] = endpoint.GetMetrics()
-		prompts[i] = request.Body.Completions.Prompt
+		inputTokenLengths[i] = inputTokenLength(request)
 		generatedTokenCounts[i] = 1
 		prefixCacheScores[i] = prefixCacheScore
 	}
 
 	// Bulk predict
-	bulkPredictions, err := bulkPredictWithMetrics(ctx, s.latencypredictor, metricsStates, prompts, generatedTokenCounts, prefixCacheScores)
+	bulkPredictions, err := bulkPredictWithMetrics(ctx, s.latencypredictor, metricsStates, inputTokenLengths, generatedTokenCounts, prefixCacheScores)
 	if err != nil {
 		logger.V(logutil.DEBUG).Error(err, "Bulk prediction failed")
 		return nil, err
@@ -122,7 +122,7 @@ func (s *PredictedLatency) validatePrediction(
 	tpotOk = true
 	headroom = 0.0
 
-	if s.config.StreamingMode {
+	if s.predictsTPOT(&predictedLatencyCtx.schedulingRequest) {
 		bufferedTPOT := predictedLatencyCtx.avgTPOTSLO * s.config.SLOBufferFactor
 		// a podMinTPOTSLO of 0 means no either no requests, or no TPOT SLOs specified on running requests
 		if podMinTPOTSLO > 0 {
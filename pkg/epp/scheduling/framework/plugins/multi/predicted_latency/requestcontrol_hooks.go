@@ -174,11 +174,15 @@ func (t *PredictedLatency) ResponseStreaming(ctx context.Context, request *sched
 		logger.V(logutil.DEBUG).Info("PredictedLatency.ResponseStreaming: request is nil, skipping")
 		return
 	}
-	if !t.checkPredictor(logger, targetMetadata) || response.EndOfStream || !t.config.StreamingMode {
+	if !t.checkPredictor(logger, targetMetadata) || !generatesTokens(response) || !t.predictsTPOT(request) {
 		return
 	}
 
-	now := time.Now()
+	// The chunk is timed when the EPP received it rather than when the plugin runs.
+	now := response.ReceivedTimestamp
+	if now.IsZero() {
+		now = time.Now()
+	}
 	predictedLatencyCtx, err := t.getPredictedLatencyContextForRequest(request)
 	if err != nil {
 		id := request.Headers[requtil.RequestIdHeaderKey]
@@ -187,13 +191,24 @@ func (t *PredictedLatency) ResponseStreaming(ctx context.Context, request *sched
 	}
 
 	if predictedLatencyCtx.ttft == 0 {
-		processFirstTokenForLatencyPrediction(ctx, t.latencypredictor, t.config.StreamingMode, predictedLatencyCtx, now, t.config.SamplingMean, t.config.MaxSampledTokens)
+		processFirstTokenForLatencyPrediction(ctx, t.latencypredictor, true, predictedLatencyCtx, now, t.config.SamplingMean, t.config.MaxSampledTokens)
 	} else {
-		processTokenForLatencyPrediction(ctx, t.latencypredictor, predictedLatencyCtx, now, t.config.SamplingMean, t.config.MaxSampledTokens)
+		processTokenForLatencyPrediction(ctx, t.latencypredictor, predictedLatencyCtx, now, max(response.ChunkTokens, 1), t.config.SamplingMean, t.config.MaxSampledTokens)
 	}
 
 }
 
+// generatesTokens returns true if the chunk of a streamed response carries generated text. The chunks carrying no
+// token, such as role-only deltas, usage or stream end events, are not token arrivals.
+func generatesTokens(response *requestcontrol.Response) bool {
+	for _, event := range response.Events {
+		if event.Delta != "" {
+			return true
+		}
+	}
+	return false
+}
+
 func (t *PredictedLatency) ResponseComplete(ctx context.Context, request *schedulingtypes.LLMRequest, response *requestcontrol.Response, metadata *datalayer.EndpointMetadata) {
 	logger := log.FromContext(ctx)
 	if request == nil {
@@ -212,8 +227,8 @@ func (t *PredictedLatency) ResponseComplete(ctx context.Context, request *schedu
 		return
 	}
 	now := time.Now()
-	if !t.config.StreamingMode {
-		processFirstTokenForLatencyPrediction(ctx, t.latencypredictor, t.config.StreamingMode, predictedLatencyCtx, now, t.config.SamplingMean, t.config.MaxSampledTokens)
+	if !t.predictsTPOT(request) {
+		processFirstTokenForLatencyPrediction(ctx, t.latencypredictor, false, predictedLatencyCtx, now, t.config.SamplingMean, t.config.MaxSampledTokens)
 	}
 
 	if predictedLatencyCtx.ttft > 0 {
@@ -31,6 +31,7 @@ import (
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datalayer"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/requestcontrol"
 	schedulingtypes "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/scheduling"
+	handlerstypes "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/handlers/types"
 	requtil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/request"
 )
 
@@ -325,7 +326,7 @@ func TestPredictedLatency_ResponseStreaming_FirstToken(t *testing.T) {
 	ctx := context.Background()
 	endpoint := createTestEndpoint("test-pod", 1, 1, 1)
 	request := createTestLLMRequest("test", 100, 50)
-	response := &requestcontrol.Response{}
+	response := &requestcontrol.Response{Events: []handlerstypes.StreamEvent{{Delta: "Hello"}}}
 	schedulingResult := createTestSchedulingResult(endpoint.GetMetadata())
 
 	predictedLatencyCtx := newPredictedLatencyContext(request)
@@ -378,7 +379,7 @@ func TestPredictedLatency_ResponseStreaming_SubsequentTokens(t *testing.T) {
 	ctx := context.Background()
 	endpoint := createTestEndpoint("test-pod", 1, 1, 1)
 	request := createTestLLMRequest("test", 100, 50)
-	response := &requestcontrol.Response{}
+	response := &requestcontrol.Response{Events: []handlerstypes.StreamEvent{{Delta: "Hello"}}}
 	schedulingResult := createTestSchedulingResult(endpoint.GetMetadata())
 
 	predictedLatencyCtx := newPredictedLatencyContext(request)
@@ -419,6 +420,69 @@ func TestPredictedLatency_ResponseStreaming_SubsequentTokens(t *testing.T) {
 	assert.True(t, retrievedCtx.lastTokenTimestamp.After(firstTokenTime))
 }
 
+func TestPredictedLatency_ResponseStreaming_ReceivedTimestamp(t *testing.T) {
+	router := createTestRouter()
+	mockPredictor := new(mockPredictor)
+	router.latencypredictor = mockPredictor
+
+	ctx := context.Background()
+	endpoint := createTestEndpoint("test-pod", 1, 1, 1)
+	request := createTestLLMRequest("test", 100, 50)
+
+	predictedLatencyCtx := newPredictedLatencyContext(request)
+	predictedLatencyCtx.targetMetadata = endpoint.GetMetadata()
+	predictedLatencyCtx.schedulingRequest = *request
+	predictedLatencyCtx.ttft = 100
+	predictedLatencyCtx.generatedTokenCount = 1
+	predictedLatencyCtx.lastTokenTimestamp = time.Now().Add(-time.Second)
+	predictedLatencyCtx.schedulingResult = createTestSchedulingResult(endpoint.GetMetadata())
+	predictedLatencyCtx.lastSeenMetrics["default"] = &datalayer.Metrics{KVCacheUsagePercent: 0.5, WaitingQueueSize: 1, RunningRequestsSize: 1}
+	router.setPredictedLatencyContextForRequest(request, predictedLatencyCtx)
+
+	// Two tokens received at once 100ms after the previous one are 50ms apart each.
+	received := predictedLatencyCtx.lastTokenTimestamp.Add(100 * time.Millisecond)
+	response := &requestcontrol.Response{
+		Events:            []handlerstypes.StreamEvent{{Delta: "Hel"}, {Delta: "lo"}},
+		ChunkTokens:       2,
+		ReceivedTimestamp: received,
+	}
+	router.ResponseStreaming(ctx, request, response, endpoint.GetMetadata())
+
+	retrievedCtx, err := router.getPredictedLatencyContextForRequest(request)
+	require.NoError(t, err)
+	assert.Equal(t, received, retrievedCtx.lastTokenTimestamp)
+	assert.Equal(t, []float64{50}, retrievedCtx.tpotObservations)
+}
+
+func TestPredictedLatency_ResponseStreaming_ChunkWithoutTokens(t *testing.T) {
+	router := createTestRouter()
+	mockPredictor := new(mockPredictor)
+	router.latencypredictor = mockPredictor
+
+	ctx := context.Background()
+	endpoint := createTestEndpoint("test-pod", 1, 1, 1)
+	request := createTestLLMRequest("test", 100, 50)
+	// A role-only delta followed by the usage chunk and the end of the stream carries no generated token.
+	response := &requestcontrol.Response{Events: []handlerstypes.StreamEvent{
+		{Data: `{"choices":[{"delta":{"role":"assistant"}}]}`},
+		{Data: `{"choices":[],"usage":{"prompt_tokens":7,"completion_tokens":10,"total_tokens":17}}`, Usage: &handlerstypes.Usage{PromptTokens: 7, CompletionTokens: 10, TotalTokens: 17}},
+		{Data: "[DONE]", End: true},
+	}}
+
+	predictedLatencyCtx := newPredictedLatencyContext(request)
+	predictedLatencyCtx.targetMetadata = endpoint.GetMetadata()
+	predictedLatencyCtx.requestReceivedTimestamp = time.Now()
+	predictedLatencyCtx.schedulingRequest = *request
+	router.setPredictedLatencyContextForRequest(request, predictedLatencyCtx)
+
+	router.ResponseStreaming(ctx, request, response, endpoint.GetMetadata())
+
+	retrievedCtx, err := router.getPredictedLatencyContextForRequest(request)
+	require.NoError(t, err)
+	assert.Zero(t, retrievedCtx.ttft, "a chunk without generated text should not be taken as the first token")
+	assert.True(t, retrievedCtx.lastTokenTimestamp.IsZero())
+}
+
 func TestPredictedLatency_ResponseComplete_QueueNotFound(t *testing.T) {
 	router := createTestRouter()
 	mockPredictor := new(mockPredictor)
@@ -452,7 +516,7 @@ func TestPredictedLatency_ResponseStreaming_NoContext(t *testing.T) {
 	ctx := context.Background()
 	endpoint := createTestEndpoint("test-pod", 1, 1, 1)
 	request := createTestLLMRequest("test", 100, 50)
-	response := &requestcontrol.Response{}
+	response := &requestcontrol.Response{Events: []handlerstypes.StreamEvent{{Delta: "Hello"}}}
 
 	// Don't set SLO context - should handle gracefully
 	router.ResponseStreaming(ctx, request, response, endpoint.GetMetadata())
@@ -544,7 +608,7 @@ func TestPredictedLatency_ResponseComplete_NoContext(t *testing.T) {
 	ctx := context.Background()
 	endpoint := createTestEndpoint("test-pod", 1, 1, 1)
 	request := createTestLLMRequest("test", 100, 50)
-	response := &requestcontrol.Response{}
+	response := &requestcontrol.Response{Events: []handlerstypes.StreamEvent{{Delta: "Hello"}}}
 
 	// Don't set SLO context - should handle gracefully
 	router.ResponseComplete(ctx, request, response, endpoint.GetMetadata())
@@ -782,7 +846,7 @@ func TestPredictedLatency_RequestLifecycle_Complete(t *testing.T) {
 	ctx := context.Background()
 	endpoint := createTestEndpoint("test-pod", 1, 1, 1)
 	request := createTestLLMRequest("test", 100, 50)
-	response := &requestcontrol.Response{}
+	response := &requestcontrol.Response{Events: []handlerstypes.StreamEvent{{Delta: "Hello"}}}
 	schedulingResult := createTestSchedulingResult(endpoint.GetMetadata())
 
 	// Create initial context
@@ -181,6 +181,13 @@ func startPredictor(handle plugin.Handle) (latencypredictor.PredictorInterface,
 	return predictor, nil
 }
 
+// predictsTPOT returns true if the time per output token of the request is predicted, which is the case in
+// streaming mode for all requests except prefill-only ones, such as embeddings, that generate no tokens.
+// The latency of other requests is modeled by their TTFT, recorded when the response completes.
+func (s *PredictedLatency) predictsTPOT(request *framework.LLMRequest) bool {
+	return s.config.StreamingMode && !request.Kind().IsPrefillOnly()
+}
+
 func (s *PredictedLatency) TypedName() plugin.TypedName {
 	return s.typedName
 }
@@ -302,7 +309,7 @@ func (s *PredictedLatency) Score(ctx context.Context, state *framework.CycleStat
 	allPreds, sticky := s.epsilonGreedyAffinityGate(ctx, allPreds, rng, "overall", s.config.AffinityGateTauGlobal)
 
 	// Check if all pods are invalid and all have running requests
-	allEndpointsInvalid := (sloCtx.ttftSLO > 0 && (sloCtx.avgTPOTSLO > 0 || !s.config.StreamingMode))
+	allEndpointsInvalid := (sloCtx.ttftSLO > 0 && (sloCtx.avgTPOTSLO > 0 || !s.predictsTPOT(request)))
 	allEndpointsHaveRunningRequests := true
 
 	for _, pred := range allPreds {
@@ -132,7 +132,7 @@ func createTestLLMRequest(reqID string, ttftSLO, tpotSLO float64) *schedulingtyp
 		Headers: headers,
 		Body: &schedulingtypes.LLMRequestBody{
 			Completions: &schedulingtypes.CompletionsRequest{
-				Prompt: "test prompt",
+				Prompt: schedulingtypes.Prompt{Raw: "test prompt"},
 			},
 		},
 	}
@@ -730,3 +730,28 @@ func TestPredictedLatencyFactoryInvalidJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestPredictedLatency_ValidatePredictionPrefillOnly(t *testing.T) {
+	router := createTestRouter()
+	router.config.StreamingMode = true
+	prediction := &latencypredictor.PredictionResponse{TTFT: 50, TPOT: 80}
+
+	completions := newPredictedLatencyContext(createTestLLMRequest("completions", 100, 50))
+	completions.ttftSLO, completions.avgTPOTSLO = 100, 50
+	ttftOk, tpotOk, isValid, _, _ := router.validatePrediction(prediction, completions, 0)
+	assert.True(t, ttftOk)
+	assert.False(t, tpotOk, "TPOT above the SLO should be invalid")
+	assert.False(t, isValid)
+
+	embeddingsRequest := createTestLLMRequest("embeddings", 100, 50)
+	embeddingsRequest.Body = &schedulingtypes.LLMRequestBody{
+		Embeddings: &schedulingtypes.EmbeddingsRequest{Input: schedulingtypes.Prompt{Raw: "test input"}},
+	}
+	embeddings := newPredictedLatencyContext(embeddingsRequest)
+	embeddings.ttftSLO, embeddings.avgTPOTSLO = 100, 50
+	ttftOk, tpotOk, isValid, headroom, _ := router.validatePrediction(prediction, embeddings, 0)
+	assert.True(t, ttftOk)
+	assert.True(t, tpotOk, "TPOT should not be validated for prefill-only requests")
+	assert.True(t, isValid)
+	assert.Zero(t, headroom)
+}
@@ -0,0 +1,243 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prefix
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"github.com/cespare/xxhash/v2"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/common/util/logging"
+	framework "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/scheduling"
+)
+
+const (
+	// ChatHashModeFlat hashes the JSON encoding of all the chat messages as a single prompt.
+	ChatHashModeFlat = "flat"
+	// ChatHashModeMessage hashes the chat messages one by one, in conversation order, so that block boundaries
+	// are aligned to message boundaries.
+	ChatHashModeMessage = "message"
+
+	// segmentSeparator separates the fields of a chat segment.
+	segmentSeparator = 0
+)
+
+// chatSegments returns the chat-completions request as a list of segments in the order a chat template renders
+// them: the tools definition first, then every message. Each message is encoded as its role, content and tool calls.
+// Media content is replaced by a digest of its URL or data, so that large inline media don't take many blocks.
+func chatSegments(request *framework.ChatCompletionsRequest) ([][]byte, error) {
+	return messagesSegments(request.Tools, framework.Content{}, request.Messages)
+}
+
+// anthropicSegments returns the Anthropic messages request as a list of segments, like chatSegments does for a
+// chat-completions request, with the top-level system prompt as a system message.
+func anthropicSegments(request *framework.AnthropicMessagesRequest) ([][]byte, error) {
+	return messagesSegments(request.Tools, request.System, request.Messages)
+}
+
+// messagesSegments returns the tools definition, the system prompt if not empty and every message as segments.
+func messagesSegments(tools []interface{}, system framework.Content, messages []framework.Message) ([][]byte, error) {
+	segments := make([][]byte, 0, len(messages)+2)
+	if len(tools) > 0 {
+		encodedTools, err := json.Marshal(tools)
+		if err != nil {
+			return nil, err
+		}
+		segments = append(segments, append([]byte("tools\x00"), encodedTools...))
+	}
+	if system.Raw != "" || len(system.Structured) > 0 {
+		messages = append([]framework.Message{{Role: "system", Content: system}}, messages...)
+	}
+
+	for _, message := range messages {
+		var buf bytes.Buffer
+		buf.WriteString(message.Role)
+		buf.WriteByte(segmentSeparator)
+		if err := writeContent(&buf, message.Content); err != nil {
+			return nil, err
+		}
+		if len(message.ToolCalls) > 0 {
+			toolCalls, err := json.Marshal(message.ToolCalls)
+			if err != nil {
+				return nil, err
+			}
+			buf.WriteByte(segmentSeparator)
+			buf.Write(toolCalls)
+		}
+		if message.ToolCallID != "" {
+			buf.WriteByte(segmentSeparator)
+			buf.WriteString(message.ToolCallID)
+		}
+		segments = append(segments, buf.Bytes())
+	}
+	return segments, nil
+}
+
+// writeContent writes the content of a message, in the OpenAI or in the Anthropic format, to the buffer.
+func writeContent(buf *bytes.Buffer, content framework.Content) error {
+	buf.WriteString(content.Raw)
+	for _, block := range content.Structured {
+		switch block.Type {
+		case "text":
+			buf.WriteString(block.Text)
+		case "image_url":
+			buf.WriteString("image_url:" + digest(block.ImageURL.Url))
+		case "input_audio":
+			buf.WriteString("input_audio:" + block.InputAudio.Format + ":" + digest(block.InputAudio.Data))
+		case "video_url":
+			buf.WriteString("video_url:" + digest(block.VideoURL.Url))
+		case "image", "document":
+			if block.Source != nil {
+				buf.WriteString(block.Type + ":" + digest(block.Source.Data+block.Source.Url))
+			}
+		case "tool_use":
+			input, err := json.Marshal(block.Input)
+			if err != nil {
+				return err
+			}
+			buf.WriteString("tool_use:" + block.ID + ":" + block.Name + ":")
+			buf.Write(input)
+		case "tool_result":
+			buf.WriteString("tool_result:" + block.ToolUseID + ":")
+			if block.Content != nil {
+				if err := writeContent(buf, *block.Content); err != nil {
+					return err
+				}
+			}
+		default:
+			buf.WriteString(block.Type)
+		}
+		buf.WriteByte(segmentSeparator)
+	}
+	return nil
+}
+
+// responsesSegments returns the responses request as a list of segments, like chatSegments does for a
+// chat-completions request: the tools definition first, then the instructions as a system message, then every input
+// item. Function calls and their outputs are encoded as their own segments.
+func responsesSegments(request *framework.ResponsesRequest) ([][]byte, error) {
+	segments := make([][]byte, 0, len(request.Input.Items)+2)
+	if len(request.Tools) > 0 {
+		tools, err := json.Marshal(request.Tools)
+		if err != nil {
+			return nil, err
+		}
+		segments = append(segments, append([]byte("tools\x00"), tools...))
+	}
+	if request.Instructions != "" {
+		segments = append(segments, append([]byte("system\x00"), request.Instructions...))
+	}
+	if request.Input.Raw != "" {
+		segments = append(segments, append([]byte("user\x00"), request.Input.Raw...))
+	}
+
+	for _, item := range request.Input.Items {
+		var buf bytes.Buffer
+		if item.Role != "" {
+			buf.WriteString(item.Role)
+		} else {
+			buf.WriteString(item.Type)
+		}
+		buf.WriteByte(segmentSeparator)
+		buf.WriteString(item.Content.Raw)
+		for _, part := range item.Content.Parts {
+			switch part.Type {
+			case "input_image":
+				buf.WriteString("input_image:" + digest(part.ImageURL+part.FileID))
+			case "input_file":
+				buf.WriteString("input_file:" + digest(part.FileData+part.FileID))
+			default:
+				if part.Text != "" {
+					buf.WriteString(part.Text)
+				} else {
+					buf.WriteString(part.Type)
+				}
+			}
+			buf.WriteByte(segmentSeparator)
+		}
+		for _, field := range []string{item.CallID, item.Name, item.Arguments, item.Output} {
+			if field != "" {
+				buf.WriteByte(segmentSeparator)
+				buf.WriteString(field)
+			}
+		}
+		segments = append(segments, buf.Bytes())
+	}
+	return segments, nil
+}
+
+// hasMessageSegments returns true if the request body is made of messages that can be hashed one by one.
+func hasMessageSegments(body *framework.LLMRequestBody) bool {
+	return body != nil && (body.ChatCompletions != nil || body.Responses != nil || body.AnthropicMessages != nil)
+}
+
+// messageSegments returns the segments of a chat-completions, responses or Anthropic messages request body.
+func messageSegments(body *framework.LLMRequestBody) ([][]byte, error) {
+	switch {
+	case body.Responses != nil:
+		return responsesSegments(body.Responses)
+	case body.AnthropicMessages != nil:
+		return anthropicSegments(body.AnthropicMessages)
+	}
+	return chatSegments(body.ChatCompletions)
+}
+
+// digest returns a short digest of media content.
+func digest(content string) string {
+	return strconv.FormatUint(xxhash.Sum64String(content), 16)
+}
+
+// hashChatMessages divides every segment of the chat-completions or responses request into blocks and calculates the prefix cache
+// for each block. Every segment starts a new block, and the last block of a segment may be smaller than
+// cacheBlockSize. Blocks are chained like in hashPrompt, so a block hash depends on all the preceding segments,
+// but appending a message to a conversation doesn't change the hashes of the previous messages.
+func hashChatMessages(ctx context.Context, request *framework.LLMRequest, cacheBlockSize int, maxPrefixBlocks int) []BlockHash {
+	loggerDebug := log.FromContext(ctx).V(logutil.DEBUG)
+	if request == nil || !hasMessageSegments(request.Body) {
+		loggerDebug.Info("Request has no chat messages, skipping hashing")
+		return nil
+	}
+
+	segments, err := messageSegments(request.Body)
+	if err != nil {
+		loggerDebug.Error(err, "Failed to get chat segments")
+		return nil
+	}
+
+	res := make([]BlockHash, 0, len(segments))
+	h := xxhash.New()
+	prevBlockHash := initialBlockHash(request)
+	for _, segment := range segments {
+		for i := 0; i < len(segment); i += cacheBlockSize {
+			if len(res) == maxPrefixBlocks {
+				loggerDebug.Info("Truncating chat messages", "max prefix blocks", maxPrefixBlocks, "block size", cacheBlockSize)
+				return res
+			}
+			h.Reset()
+			_, _ = h.Write(segment[i:min(i+cacheBlockSize, len(segment))])
+			_, _ = h.Write(toBytes(prevBlockHash))
+			res = append(res, BlockHash(h.Sum64()))
+
+			prevBlockHash = res[len(res)-1]
+		}
+	}
+	return res
+}
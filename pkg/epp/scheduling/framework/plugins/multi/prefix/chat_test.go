@@ -0,0 +1,173 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prefix
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	types "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/scheduling"
+)
+
+func chatRequest(tools []interface{}, messages ...types.Message) *types.LLMRequest {
+	return &types.LLMRequest{
+		TargetModel: "test-model",
+		Body:        &types.LLMRequestBody{ChatCompletions: &types.ChatCompletionsRequest{Messages: messages, Tools: tools}},
+	}
+}
+
+func TestHashChatMessages(t *testing.T) {
+	ctx := context.Background()
+	system := types.Message{Role: "system", Content: types.Content{Raw: "You are a helpful agent."}}
+	user := types.Message{Role: "user", Content: types.Content{Raw: "List the files."}}
+	assistant := types.Message{Role: "assistant", ToolCalls: []interface{}{
+		map[string]any{"id": "call-1", "type": "function", "function": map[string]any{"name": "ls", "arguments": "{}"}},
+	}}
+	tool := types.Message{Role: "tool", ToolCallID: "call-1", Content: types.Content{Raw: "a.go b.go"}}
+
+	turn1 := hashChatMessages(ctx, chatRequest(nil, system, user), 8, DefaultMaxPrefixBlocks)
+	turn2 := hashChatMessages(ctx, chatRequest(nil, system, user, assistant, tool), 8, DefaultMaxPrefixBlocks)
+	assert.Greater(t, len(turn2), len(turn1))
+	assert.Equal(t, turn1, turn2[:len(turn1)], "appending turns should keep the hashes of the previous messages")
+
+	// Every message starts a new block: "system\x00" + 24 characters take 4 blocks of 8, "user\x00" + 15 characters 3.
+	assert.Len(t, turn1, 7)
+
+	// The tool calls are part of the message.
+	otherCall := assistant
+	otherCall.ToolCalls = []interface{}{map[string]any{"id": "call-2"}}
+	other := hashChatMessages(ctx, chatRequest(nil, system, user, otherCall, tool), 8, DefaultMaxPrefixBlocks)
+	assert.Equal(t, turn1, other[:len(turn1)])
+	assert.NotEqual(t, turn2[len(turn2)-1], other[len(other)-1])
+
+	// The tools are hashed first, so changing them changes all the hashes.
+	withTools := hashChatMessages(ctx, chatRequest([]interface{}{map[string]any{"type": "function"}}, system, user), 8, DefaultMaxPrefixBlocks)
+	assert.NotContains(t, withTools, turn1[0])
+
+	// Hashing stops at the maximal number of blocks.
+	assert.Equal(t, turn2[:5], hashChatMessages(ctx, chatRequest(nil, system, user, assistant, tool), 8, 5))
+}
+
+func TestHashChatMessagesMediaDigest(t *testing.T) {
+	ctx := context.Background()
+	image := func(url string) types.Message {
+		return types.Message{Role: "user", Content: types.Content{Structured: []types.ContentBlock{
+			{Type: "text", Text: "Describe"},
+			{Type: "image_url", ImageURL: types.ImageBlock{Url: url}},
+		}}}
+	}
+	largeImage := "data:image/png;base64," + strings.Repeat("A", 100000)
+
+	hashes := hashChatMessages(ctx, chatRequest(nil, image(largeImage)), 64, DefaultMaxPrefixBlocks)
+	assert.Len(t, hashes, 1, "inline image data should be replaced by its digest")
+	assert.Equal(t, hashes, hashChatMessages(ctx, chatRequest(nil, image(largeImage)), 64, DefaultMaxPrefixBlocks))
+	assert.NotEqual(t, hashes, hashChatMessages(ctx, chatRequest(nil, image(largeImage+"B")), 64, DefaultMaxPrefixBlocks))
+	assert.NotEqual(t, hashes, hashChatMessages(ctx, chatRequest(nil, image("https://example.com/cat.png")), 64, DefaultMaxPrefixBlocks))
+}
+
+func TestPrefixPluginChatHashMode(t *testing.T) {
+	config := DefaultConfig
+	config.AutoTune = false
+	config.BlockSize = 8
+
+	request := chatRequest(nil, types.Message{Role: "user", Content: types.Content{Raw: "hello world"}})
+	flat := New(context.Background(), config).hashRequest(context.Background(), request, nil)
+	config.ChatHashMode = ChatHashModeMessage
+	message := New(context.Background(), config).hashRequest(context.Background(), request, nil)
+	assert.Equal(t, hashChatMessages(context.Background(), request, 8, DefaultMaxPrefixBlocks), message)
+	assert.NotEqual(t, flat, message)
+
+	config.ChatHashMode = "unknown"
+	assert.Equal(t, ChatHashModeFlat, New(context.Background(), config).config.ChatHashMode)
+}
+
+func TestHashResponsesInput(t *testing.T) {
+	ctx := context.Background()
+	responsesRequest := func(instructions string, items ...types.ResponsesInputItem) *types.LLMRequest {
+		return &types.LLMRequest{
+			TargetModel: "test-model",
+			Body: &types.LLMRequestBody{Responses: &types.ResponsesRequest{
+				Instructions: instructions,
+				Input:        types.ResponsesInput{Items: items},
+			}},
+		}
+	}
+	user := types.ResponsesInputItem{Role: "user", Content: types.ResponsesContent{Raw: "What is the weather?"}}
+	call := types.ResponsesInputItem{Type: "function_call", CallID: "call_1", Name: "get_weather", Arguments: `{"city":"Paris"}`}
+	output := types.ResponsesInputItem{Type: "function_call_output", CallID: "call_1", Output: "sunny"}
+
+	turn1 := hashChatMessages(ctx, responsesRequest("Be concise.", user), 8, DefaultMaxPrefixBlocks)
+	turn2 := hashChatMessages(ctx, responsesRequest("Be concise.", user, call, output), 8, DefaultMaxPrefixBlocks)
+	// "system\x00" + 11 characters take 3 blocks of 8, "user\x00" + 20 characters 4.
+	assert.Len(t, turn1, 7)
+	assert.Equal(t, turn1, turn2[:len(turn1)], "appending input items should keep the hashes of the previous items")
+
+	// The instructions are hashed before the input items.
+	other := hashChatMessages(ctx, responsesRequest("Be verbose.", user), 8, DefaultMaxPrefixBlocks)
+	assert.NotEqual(t, turn1[len(turn1)-1], other[len(other)-1])
+
+	// Images are replaced by a digest of their URL.
+	image := types.ResponsesInputItem{Role: "user", Content: types.ResponsesContent{Parts: []types.ResponsesContentPart{
+		{Type: "input_image", ImageURL: "data:image/png;base64," + strings.Repeat("A", 4096)},
+	}}}
+	assert.Less(t, len(hashChatMessages(ctx, responsesRequest("", image), 8, DefaultMaxPrefixBlocks)), 8)
+}
+
+func TestHashAnthropicMessages(t *testing.T) {
+	ctx := context.Background()
+	anthropicRequest := func(system string, messages ...types.Message) *types.LLMRequest {
+		return &types.LLMRequest{
+			TargetModel: "test-model",
+			Body: &types.LLMRequestBody{AnthropicMessages: &types.AnthropicMessagesRequest{
+				System:   types.Content{Raw: system},
+				Messages: messages,
+			}},
+		}
+	}
+	user := types.Message{Role: "user", Content: types.Content{Raw: "List the files."}}
+	toolUse := types.Message{Role: "assistant", Content: types.Content{Structured: []types.ContentBlock{
+		{Type: "tool_use", ID: "toolu_1", Name: "ls", Input: map[string]any{}},
+	}}}
+	toolResult := types.Message{Role: "user", Content: types.Content{Structured: []types.ContentBlock{
+		{Type: "tool_result", ToolUseID: "toolu_1", Content: &types.Content{Raw: "a.go b.go"}},
+	}}}
+
+	// The top-level system prompt is hashed like a chat-completions system message.
+	system := types.Message{Role: "system", Content: types.Content{Raw: "You are a helpful agent."}}
+	turn1 := hashChatMessages(ctx, anthropicRequest("You are a helpful agent.", user), 8, DefaultMaxPrefixBlocks)
+	assert.Equal(t, hashChatMessages(ctx, chatRequest(nil, system, user), 8, DefaultMaxPrefixBlocks), turn1)
+
+	turn2 := hashChatMessages(ctx, anthropicRequest("You are a helpful agent.", user, toolUse, toolResult), 8, DefaultMaxPrefixBlocks)
+	assert.Equal(t, turn1, turn2[:len(turn1)], "appending turns should keep the hashes of the previous messages")
+
+	// The tool results are part of the message.
+	otherResult := toolResult
+	otherResult.Content = types.Content{Structured: []types.ContentBlock{
+		{Type: "tool_result", ToolUseID: "toolu_1", Content: &types.Content{Raw: "c.go"}},
+	}}
+	other := hashChatMessages(ctx, anthropicRequest("You are a helpful agent.", user, toolUse, otherResult), 8, DefaultMaxPrefixBlocks)
+	assert.NotEqual(t, turn2[len(turn2)-1], other[len(other)-1])
+
+	// Inline images are replaced by a digest of their data.
+	image := types.Message{Role: "user", Content: types.Content{Structured: []types.ContentBlock{
+		{Type: "image", Source: &types.MediaSource{Type: "base64", MediaType: "image/png", Data: strings.Repeat("A", 4096)}},
+	}}}
+	assert.Less(t, len(hashChatMessages(ctx, anthropicRequest("", image), 8, DefaultMaxPrefixBlocks)), 8)
+}
@@ -20,7 +20,9 @@ import (
 	"context"
 	"encoding/binary"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -54,6 +56,8 @@ const (
 	// token is about 128KB in size, so we can cache 500K tokens. Using the default block size of 16
 	// in vLLM, we will have 250K / 16 = 31.25K blocks.
 	DefaultLRUCapacityPerServer = 31250
+	// vLLM default token block size, used to hash tokenized prompts when the block size is not auto tuned.
+	DefaultTokenBlockSize = 16
 	// In P/D disaggregation mode, the prefill and decode are usually represented as two different scheduling profiles to pick
 	// the prefill and decode endpoints. This constant defines the prefill profile name to ensure that the index is updated
 	// for the prefill endpoint and not only for the primary endpoint that will initially handle the request.
@@ -76,6 +80,8 @@ var DefaultConfig = Config{
 	BlockSize:              DefaultBlockSize,
 	MaxPrefixBlocksToMatch: DefaultMaxPrefixBlocks,
 	LRUCapacityPerServer:   DefaultLRUCapacityPerServer,
+	TokenBlockSize:         DefaultTokenBlockSize,
+	ChatHashMode:           ChatHashModeFlat,
 }
 
 type Config struct {
@@ -90,6 +96,21 @@ type Config struct {
 	MaxPrefixBlocksToMatch int `json:"maxPrefixBlocksToMatch"`
 	// Max capacity size of the LRU indexer in number of entries per server (pod).
 	LRUCapacityPerServer int `json:"lruCapacityPerServer"`
+	// TokenizerPaths maps a model name to the path of its HuggingFace tokenizer.json file. Completions prompts of a
+	// model with a configured tokenizer are tokenized locally and hashed in blocks of tokens, so that the block
+	// boundaries line up with the model server's KV cache blocks. Other requests, whose prompt the model server renders
+	// with a chat template, are hashed in blocks of characters.
+	TokenizerPaths map[string]string `json:"tokenizerPaths"`
+	// TokenBlockSize is the number of tokens per block when hashing tokenized prompts. If AutoTune is enabled,
+	// the CacheBlockSize of the model servers takes precedence.
+	TokenBlockSize int `json:"tokenBlockSize"`
+	// IndexerSource is the name of a data source plugin providing the Indexer, e.g. an exact index built from
+	// the model servers' KV cache events. If not set, the plugin approximates the model servers' caches with
+	// an LRU index of the requests it scheduled. The KV cache events carry no cache_salt, so with an indexer
+	// source the blocks of tokenized prompts are chained to the model name only, and a tokenizer is required.
+	IndexerSource string `json:"indexerSource"`
+	// ChatHashMode defines how chat-completions messages are hashed, either ChatHashModeFlat or ChatHashModeMessage.
+	ChatHashMode string `json:"chatHashMode"`
 }
 
 type Plugin struct {
@@ -97,19 +118,26 @@ type Plugin struct {
 	config      Config
 	pluginState *plugin.PluginState
 	indexer     Indexer
+	tokenizers  map[string]Tokenizer // key is the model name
 	wg          sync.WaitGroup
 }
 
-// podSet holds an pods servers that may have a specific prefix hash.
-type podSet map[ServerID]struct{}
+// PodSet holds an pods servers that may have a specific prefix hash.
+type PodSet map[ServerID]struct{}
 
 type Indexer interface {
-	Get(hash BlockHash) podSet
+	Get(hash BlockHash) PodSet
 	Add(hashes []BlockHash, server Server)
 	RemovePod(server ServerID)
 	Pods() []ServerID
 }
 
+// IndexerProvider is implemented by plugins that provide an Indexer to the prefix plugin, see Config.IndexerSource.
+type IndexerProvider interface {
+	plugin.Plugin
+	Indexer() Indexer
+}
+
 // BlockHash is a hash of the block of request body.
 type BlockHash uint64
 
@@ -165,7 +193,24 @@ func PrefixCachePluginFactory(name string, rawParameters json.RawMessage, handle
 		}
 	}
 
-	p := New(handle.Context(), parameters).WithName(name)
+	tokenizers, err := loadTokenizers(parameters.TokenizerPaths)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the %s plugin - %w", PrefixCachePluginType, err)
+	}
+	if parameters.IndexerSource != "" && len(tokenizers) == 0 {
+		return nil, fmt.Errorf("failed to create the %s plugin - indexerSource '%s' requires tokenizerPaths, since the index is built from token IDs",
+			PrefixCachePluginType, parameters.IndexerSource)
+	}
+
+	p := New(handle.Context(), parameters).WithName(name).WithTokenizers(tokenizers)
+	if parameters.IndexerSource != "" {
+		provider, ok := handle.Plugin(parameters.IndexerSource).(IndexerProvider)
+		if !ok {
+			return nil, fmt.Errorf("failed to create the %s plugin - '%s' is not a plugin providing an indexer",
+				PrefixCachePluginType, parameters.IndexerSource)
+		}
+		p.WithIndexer(provider.Indexer())
+	}
 	go p.CleanUpInactivePods(handle.Context(), handle)
 	return p, nil
 }
@@ -192,6 +237,18 @@ func New(ctx context.Context, config Config) *Plugin {
 			"default", DefaultMaxPrefixBlocks)
 	}
 
+	if config.TokenBlockSize <= 0 {
+		config.TokenBlockSize = DefaultTokenBlockSize
+		log.FromContext(ctx).V(logutil.DEFAULT).Info("TokenBlockSize is not positive, using default value",
+			"default", DefaultTokenBlockSize)
+	}
+
+	if config.ChatHashMode != ChatHashModeFlat && config.ChatHashMode != ChatHashModeMessage {
+		log.FromContext(ctx).V(logutil.DEFAULT).Info("ChatHashMode is not valid, using default value",
+			"chatHashMode", config.ChatHashMode, "default", ChatHashModeFlat)
+		config.ChatHashMode = ChatHashModeFlat
+	}
+
 	log.FromContext(ctx).V(logutil.DEFAULT).Info("PrefixCachePlugin initialized", "config", config)
 	return &Plugin{
 		typedName:   plugin.TypedName{Type: PrefixCachePluginType, Name: PrefixCachePluginType},
@@ -217,6 +274,18 @@ func (p *Plugin) WithName(name string) *Plugin {
 	return p
 }
 
+// WithIndexer replaces the approximate LRU indexer of the plugin with the given indexer.
+func (p *Plugin) WithIndexer(indexer Indexer) *Plugin {
+	p.indexer = indexer
+	return p
+}
+
+// WithTokenizers sets the tokenizers used to hash the prompts of the given models in blocks of tokens.
+func (p *Plugin) WithTokenizers(tokenizers map[string]Tokenizer) *Plugin {
+	p.tokenizers = tokenizers
+	return p
+}
+
 func (p *Plugin) Produces() map[string]any {
 	return map[string]any{approximateprefix.PrefixCacheMatchInfoKey: approximateprefix.PrefixCacheMatchInfo{}}
 }
@@ -227,7 +296,7 @@ func (p *Plugin) Consumes() map[string]any {
 
 // PrepareRequestData hashes prompt, finds longest prefix match and stores it in endpoint as attribute.
 func (p *Plugin) PrepareRequestData(ctx context.Context, request *framework.LLMRequest, endpoints []framework.Endpoint) error {
-	hashes := hashPrompt(ctx, request, getBlockSize(endpoints, p.config), p.config.MaxPrefixBlocksToMatch)
+	hashes := p.hashRequest(ctx, request, endpoints)
 	state := &SchedulingContextState{
 		PrefixHashes:       hashes,
 		PrefixCacheServers: p.matchLongestPrefix(ctx, hashes),
@@ -245,8 +314,14 @@ func (p *Plugin) PrepareRequestData(ctx context.Context, request *framework.LLMR
 
 // Score returns the scoring result for the given list of pods based on context.
 func (p *Plugin) Score(ctx context.Context, cycleState *framework.CycleState, request *framework.LLMRequest, endpoints []framework.Endpoint) map[framework.Endpoint]float64 {
-	// pre score step, hashing prompt and find longest prefix match.
-	hashes := hashPrompt(ctx, request, getBlockSize(endpoints, p.config), p.config.MaxPrefixBlocksToMatch)
+	// pre score step, hashing prompt and find longest prefix match. The prompt is hashed only once per request, the
+	// hashes computed by PrepareRequestData or by the scoring of another profile are reused.
+	var hashes []BlockHash
+	if prev, err := plugin.ReadPluginStateKey[*SchedulingContextState](p.pluginState, request.RequestId, plugin.StateKey(p.TypedName().String())); err == nil {
+		hashes = prev.PrefixHashes
+	} else {
+		hashes = p.hashRequest(ctx, request, endpoints)
+	}
 	state := &SchedulingContextState{
 		PrefixHashes:       hashes,
 		PrefixCacheServers: p.matchLongestPrefix(ctx, hashes),
@@ -308,6 +383,10 @@ func (p *Plugin) PreRequest(ctx context.Context, request *framework.LLMRequest,
 	matchLen := state.PrefixCacheServers[ServerID(targetEndpoint.GetMetadata().NamespacedName)]
 
 	blockSize := getBlockSize(primaryProfileResult.TargetEndpoints, p.config)
+	if p.tokenizerFor(request) != nil || promptTokens(request) != nil {
+		// The match length is reported in bytes, estimate it from the number of tokens.
+		blockSize = getTokenBlockSize(primaryProfileResult.TargetEndpoints, p.config) * averageCharactersPerToken
+	}
 	metrics.RecordPrefixCacheMatch(matchLen*blockSize, total*blockSize)
 }
 
@@ -371,6 +450,63 @@ func (m *Plugin) CleanUpInactivePods(ctx context.Context, handle plugin.Handle)
 	}
 }
 
+// hashRequest hashes the request prompt, in blocks of tokens if the prompt was sent as token IDs or is a completions
+// prompt of a model with a configured tokenizer, and in blocks of characters otherwise. Chat messages are hashed one
+// by one when the message chat hash mode is set.
+func (p *Plugin) hashRequest(ctx context.Context, request *framework.LLMRequest, endpoints []framework.Endpoint) []BlockHash {
+	if tokens := promptTokens(request); tokens != nil {
+		return hashTokens(ctx, p.initialTokenBlockHash(request), tokens, getTokenBlockSize(endpoints, p.config),
+			p.config.MaxPrefixBlocksToMatch)
+	}
+	if tokenizer := p.tokenizerFor(request); tokenizer != nil {
+		return hashPromptTokens(ctx, request, tokenizer, p.initialTokenBlockHash(request), getTokenBlockSize(endpoints, p.config),
+			p.config.MaxPrefixBlocksToMatch)
+	}
+	if p.config.ChatHashMode == ChatHashModeMessage && request != nil && hasMessageSegments(request.Body) {
+		return hashChatMessages(ctx, request, getBlockSize(endpoints, p.config), p.config.MaxPrefixBlocksToMatch)
+	}
+	return hashPrompt(ctx, request, getBlockSize(endpoints, p.config), p.config.MaxPrefixBlocksToMatch)
+}
+
+// initialTokenBlockHash returns the hash the first block of a tokenized prompt is chained to. An indexer source
+// builds its index from the model servers' KV cache events, which do not carry the cache_salt of the requests, so the
+// blocks are then chained to the model name only, like the indexer does.
+func (p *Plugin) initialTokenBlockHash(request *framework.LLMRequest) BlockHash {
+	if p.config.IndexerSource != "" {
+		return InitialBlockHash(request.TargetModel, "")
+	}
+	return initialBlockHash(request)
+}
+
+// tokenizerFor returns the tokenizer of the request target model, or nil if there is none or the request is not a
+// completions request. The prompt of other requests is rendered by the model server with the model chat template,
+// which the plugin does not reproduce, so tokenizing it locally would not match the model server tokens.
+func (p *Plugin) tokenizerFor(request *framework.LLMRequest) Tokenizer {
+	if request == nil || request.Body == nil || request.Body.Completions == nil {
+		return nil
+	}
+	return p.tokenizers[request.TargetModel]
+}
+
+// promptTokens returns the token IDs of a prompt sent tokenized, or nil if the request prompt is not tokenized.
+// A batch of prompts is hashed by its first prompt, since the model server caches each prompt as its own sequence.
+func promptTokens(request *framework.LLMRequest) []uint32 {
+	if request == nil || request.Body == nil {
+		return nil
+	}
+	var prompt framework.Prompt
+	switch {
+	case request.Body.Completions != nil:
+		prompt = request.Body.Completions.Prompt
+	case request.Body.Embeddings != nil:
+		prompt = request.Body.Embeddings.Input
+	}
+	if len(prompt.Tokens) == 0 {
+		return nil
+	}
+	return prompt.Tokens[0]
+}
+
 // hashPrompt divides the prompt into blocks and calculate the prefix cache for each block.
 // hash[0] is calculated including the model name and cache_salt(if provided), since different models generally don't share prefix cache.
 // For block i, hash(i) = hash(block i content, hash(i-1)).
@@ -398,14 +534,8 @@ func hashPrompt(ctx context.Context, request *framework.LLMRequest, cacheBlockSi
 	// Split the body into blocks of size cacheBlockSize.
 	// If the last block is smaller than cacheBlockSize, it will be ignored.
 	res := make([]BlockHash, 0, len(userInput)/cacheBlockSize)
-	// Add the model to the first block hash so that different models have different hashes even with the same body.
 	h := xxhash.New()
-	_, _ = h.Write([]byte(request.TargetModel))
-	if cacheSalt := request.Body.CacheSalt(); cacheSalt != "" {
-		_, _ = h.Write([]byte(cacheSalt))
-	}
-
-	prevBlockHash := BlockHash(h.Sum64())
+	prevBlockHash := initialBlockHash(request)
 	for i := 0; i+cacheBlockSize <= len(userInput); i += cacheBlockSize {
 		h.Reset()
 		_, _ = h.Write(userInput[i : i+cacheBlockSize])
@@ -417,6 +547,82 @@ func hashPrompt(ctx context.Context, request *framework.LLMRequest, cacheBlockSi
 	return res
 }
 
+// hashPromptTokens tokenizes the completions prompt, with the special tokens the model server adds, and divides the
+// token IDs into blocks of cacheBlockSize tokens, matching the KV cache blocks of the model server. The blocks are
+// hashed with the same chained scheme as hashPrompt.
+func hashPromptTokens(ctx context.Context, request *framework.LLMRequest, tokenizer Tokenizer, initialHash BlockHash, cacheBlockSize int,
+	maxPrefixBlocks int) []BlockHash {
+	loggerDebug := log.FromContext(ctx).V(logutil.DEBUG)
+	if request == nil || request.Body == nil || request.Body.Completions == nil {
+		loggerDebug.Info("Request or completions request is nil, skipping hashing")
+		return nil
+	}
+
+	// Input beyond the prefix blocks to match is not tokenized. The bound assumes averageCharactersPerToken characters
+	// per token, and the prompt is cut at a word boundary, so that the tokens of the last word are not altered.
+	prompt := request.Body.Completions.Prompt.PlainText()
+	if maxInput := maxPrefixBlocks * cacheBlockSize * averageCharactersPerToken; len(prompt) > maxInput {
+		loggerDebug.Info("Truncating input", "size", len(prompt), "max prefix blocks", maxPrefixBlocks, "block size", cacheBlockSize)
+		prompt = prompt[:maxInput]
+		if end := strings.LastIndexAny(prompt, " \n"); end > 0 {
+			prompt = prompt[:end]
+		}
+	}
+
+	return hashTokens(ctx, initialHash, tokenizer.Encode(prompt, true), cacheBlockSize, maxPrefixBlocks)
+}
+
+// hashTokens divides the token IDs into blocks of cacheBlockSize tokens and calculates the prefix cache for each block.
+// Like in hashPrompt, hash(i) = hash(block i tokens, hash(i-1)), where hash(-1) is initialHash.
+func hashTokens(ctx context.Context, initialHash BlockHash, tokens []uint32, cacheBlockSize int, maxPrefixBlocks int) []BlockHash {
+	loggerDebug := log.FromContext(ctx).V(logutil.DEBUG)
+	if len(tokens) < cacheBlockSize {
+		loggerDebug.Info("Request prompt too small for prefix cache", "tokens", len(tokens), "block size", cacheBlockSize)
+		return nil
+	}
+	if len(tokens) > cacheBlockSize*maxPrefixBlocks {
+		loggerDebug.Info("Truncating prompt tokens", "tokens", len(tokens), "max prefix blocks", maxPrefixBlocks, "block size", cacheBlockSize)
+		tokens = tokens[:maxPrefixBlocks*cacheBlockSize]
+	}
+	// If the last block has less than cacheBlockSize tokens, it will be ignored.
+	res := make([]BlockHash, 0, len(tokens)/cacheBlockSize)
+	prevBlockHash := initialHash
+	for i := 0; i+cacheBlockSize <= len(tokens); i += cacheBlockSize {
+		res = append(res, HashTokenBlock(prevBlockHash, tokens[i:i+cacheBlockSize]))
+		prevBlockHash = res[len(res)-1]
+	}
+	return res
+}
+
+// initialBlockHash returns the hash the first block of the request prompt is chained to.
+func initialBlockHash(request *framework.LLMRequest) BlockHash {
+	return InitialBlockHash(request.TargetModel, request.Body.CacheSalt())
+}
+
+// InitialBlockHash returns the hash the first block is chained to. It includes the model name and cache_salt
+// (if provided), so that different models have different hashes even with the same body.
+func InitialBlockHash(model string, cacheSalt string) BlockHash {
+	h := xxhash.New()
+	_, _ = h.Write([]byte(model))
+	if cacheSalt != "" {
+		_, _ = h.Write([]byte(cacheSalt))
+	}
+	return BlockHash(h.Sum64())
+}
+
+// HashTokenBlock returns the hash of a block of token IDs chained to the hash of the previous block.
+// Indexers built outside of this plugin must use it to produce hashes matching the tokenized prompts.
+func HashTokenBlock(prevBlockHash BlockHash, tokens []uint32) BlockHash {
+	block := make([]byte, 4*len(tokens))
+	for i, token := range tokens {
+		binary.LittleEndian.PutUint32(block[4*i:], token)
+	}
+	h := xxhash.New()
+	_, _ = h.Write(block)
+	_, _ = h.Write(toBytes(prevBlockHash))
+	return BlockHash(h.Sum64())
+}
+
 func toBytes(i BlockHash) []byte {
 	bytes := make([]byte, 8)
 	binary.LittleEndian.PutUint64(bytes, uint64(i))
@@ -424,12 +630,26 @@ func toBytes(i BlockHash) []byte {
 }
 
 func getUserInputBytes(request *framework.LLMRequest) ([]byte, error) {
-	if request.Body.Completions != nil { // assumed to be valid if not nil
-		return []byte(request.Body.Completions.Prompt), nil
+	switch {
+	case request.Body.Completions != nil: // assumed to be valid if not nil
+		return []byte(request.Body.Completions.Prompt.PlainText()), nil
+	case request.Body.Responses != nil: // return bytes of the instructions followed by the entire input
+		input, err := json.Marshal(request.Body.Responses.Input)
+		if err != nil {
+			return nil, err
+		}
+		return append([]byte(request.Body.Responses.Instructions), input...), nil
+	case request.Body.Embeddings != nil, request.Body.Rerank != nil, request.Body.OpenInference != nil:
+		return []byte(request.Body.PlainText()), nil
+	case request.Body.ChatCompletions != nil: // return bytes of entire messages
+		return json.Marshal(request.Body.ChatCompletions.Messages)
+	case request.Body.AnthropicMessages != nil: // return bytes of the system prompt followed by the entire messages
+		return json.Marshal(struct {
+			System   framework.Content   `json:"system"`
+			Messages []framework.Message `json:"messages"`
+		}{System: request.Body.AnthropicMessages.System, Messages: request.Body.AnthropicMessages.Messages})
 	}
-
-	// must be chat-completions request at this point, return bytes of entire messages
-	return json.Marshal(request.Body.ChatCompletions.Messages)
+	return nil, errors.New("request body has no user input")
 }
 
 func getBlockSize(endpoints []framework.Endpoint, config Config) int {
@@ -452,3 +672,13 @@ func getBlockSize(endpoints []framework.Endpoint, config Config) int {
 	}
 	return config.BlockSize
 }
+
+// getTokenBlockSize returns the number of tokens per block when hashing tokenized prompts.
+func getTokenBlockSize(endpoints []framework.Endpoint, config Config) int {
+	if config.AutoTune && len(endpoints) > 0 {
+		if metrics := endpoints[0].GetMetrics(); metrics != nil && metrics.CacheBlockSize > 0 {
+			return metrics.CacheBlockSize
+		}
+	}
+	return config.TokenBlockSize
+}
@@ -18,13 +18,17 @@ package prefix
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"math/rand"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	k8stypes "k8s.io/apimachinery/pkg/types"
 
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datalayer"
@@ -56,7 +60,7 @@ func TestPrefixPluginCompletion(t *testing.T) {
 		TargetModel: "test-model1",
 		Body: &types.LLMRequestBody{
 			Completions: &types.CompletionsRequest{
-				Prompt: "aaaaaa",
+				Prompt: types.Prompt{Raw: "aaaaaa"},
 			},
 		},
 	}
@@ -89,7 +93,7 @@ func TestPrefixPluginCompletion(t *testing.T) {
 		TargetModel: "test-model2",
 		Body: &types.LLMRequestBody{
 			Completions: &types.CompletionsRequest{
-				Prompt: "bbbbbb",
+				Prompt: types.Prompt{Raw: "bbbbbb"},
 			},
 		},
 	}
@@ -120,7 +124,7 @@ func TestPrefixPluginCompletion(t *testing.T) {
 		TargetModel: "test-model1",
 		Body: &types.LLMRequestBody{
 			Completions: &types.CompletionsRequest{
-				Prompt: "aaaabbbb",
+				Prompt: types.Prompt{Raw: "aaaabbbb"},
 			},
 		},
 	}
@@ -151,7 +155,7 @@ func TestPrefixPluginCompletion(t *testing.T) {
 		TargetModel: "test-model-new",
 		Body: &types.LLMRequestBody{
 			Completions: &types.CompletionsRequest{
-				Prompt: "aaaabbbb",
+				Prompt: types.Prompt{Raw: "aaaabbbb"},
 			},
 		},
 	}
@@ -181,7 +185,7 @@ func TestPrefixPluginCompletion(t *testing.T) {
 		TargetModel: "test-model1",
 		Body: &types.LLMRequestBody{
 			Completions: &types.CompletionsRequest{
-				Prompt: "aaaabbbbcccc",
+				Prompt: types.Prompt{Raw: "aaaabbbbcccc"},
 			},
 		},
 	}
@@ -388,7 +392,7 @@ func BenchmarkPrefixPluginStress(b *testing.B) {
 				TargetModel: "model-stress",
 				Body: &types.LLMRequestBody{
 					Completions: &types.CompletionsRequest{
-						Prompt: prompt,
+						Prompt: types.Prompt{Raw: prompt},
 					},
 				},
 			}
@@ -493,7 +497,7 @@ func TestPrefixPluginAutoTune(t *testing.T) {
 				// Length 128 chars.
 				// If AutoTune=true (block size 64): 2 blocks
 				// If AutoTune=false (block size 32): 4 blocks
-				Prompt: strings.Repeat("a", 128),
+				Prompt: types.Prompt{Raw: strings.Repeat("a", 128)},
 			},
 		},
 	}
@@ -596,7 +600,7 @@ func TestPrepareRequestData(t *testing.T) {
 		TargetModel: "test-model1",
 		Body: &types.LLMRequestBody{
 			Completions: &types.CompletionsRequest{
-				Prompt: "aaaabbbb",
+				Prompt: types.Prompt{Raw: "aaaabbbb"},
 			},
 		},
 	}
@@ -616,7 +620,7 @@ func TestPrepareRequestData(t *testing.T) {
 		TargetModel: "test-model1",
 		Body: &types.LLMRequestBody{
 			Completions: &types.CompletionsRequest{
-				Prompt: "aaaacccc",
+				Prompt: types.Prompt{Raw: "aaaacccc"},
 			},
 		},
 	}
@@ -710,3 +714,196 @@ func BenchmarkPrefixPluginChatCompletionsStress(b *testing.B) {
 		})
 	}
 }
+
+func TestPrefixPluginTokenizer(t *testing.T) {
+	tokenizer, err := parseTokenizer([]byte(byteLevelTokenizerJSON))
+	assert.NoError(t, err)
+
+	config := Config{
+		BlockSize:              4,
+		MaxPrefixBlocksToMatch: DefaultMaxPrefixBlocks,
+		LRUCapacityPerServer:   DefaultLRUCapacityPerServer,
+		TokenBlockSize:         2,
+	}
+	plugin := New(context.Background(), config).WithTokenizers(map[string]Tokenizer{"tokenized-model": tokenizer})
+
+	endpoint1 := &types.PodMetrics{EndpointMetadata: &datalayer.EndpointMetadata{NamespacedName: k8stypes.NamespacedName{Name: "pod1"}}, Metrics: datalayer.NewMetrics()}
+	endpoint2 := &types.PodMetrics{EndpointMetadata: &datalayer.EndpointMetadata{NamespacedName: k8stypes.NamespacedName{Name: "pod2"}}, Metrics: datalayer.NewMetrics()}
+	endpoints := []types.Endpoint{endpoint1, endpoint2}
+
+	newRequest := func(model, prompt string) *types.LLMRequest {
+		return &types.LLMRequest{
+			RequestId:   uuid.NewString(),
+			TargetModel: model,
+			Body:        &types.LLMRequestBody{Completions: &types.CompletionsRequest{Prompt: types.Prompt{Raw: prompt}}},
+		}
+	}
+	readState := func(request *types.LLMRequest) *SchedulingContextState {
+		state, err := fwkplugin.ReadPluginStateKey[*SchedulingContextState](plugin.pluginState, request.RequestId, fwkplugin.StateKey(plugin.TypedName().String()))
+		assert.NoError(t, err)
+		return state
+	}
+
+	// "hello world hello world" is tokenized to [hello, Ġworld, Ġ, hello, Ġworld], the last token is ignored.
+	req1 := newRequest("tokenized-model", "hello world hello world")
+	plugin.Score(context.Background(), types.NewCycleState(), req1, endpoints)
+	assert.Len(t, readState(req1).PrefixHashes, 2, "prompt should be hashed in blocks of 2 tokens")
+	plugin.PreRequest(context.Background(), req1, &types.SchedulingResult{
+		PrimaryProfileName: "default",
+		ProfileResults:     map[string]*types.ProfileRunResult{"default": {TargetEndpoints: []types.Endpoint{endpoint1}}},
+	})
+	plugin.wg.Wait()
+
+	// "hello world held" is tokenized to [hello, Ġworld, Ġ, he, l, d], only the first block of tokens is shared.
+	req2 := newRequest("tokenized-model", "hello world held")
+	scores := plugin.Score(context.Background(), types.NewCycleState(), req2, endpoints)
+	assert.Len(t, readState(req2).PrefixHashes, 3)
+	assert.Equal(t, 1.0/3, scores[endpoint1], "score for endpoint1")
+	assert.Equal(t, float64(0), scores[endpoint2], "score for endpoint2")
+
+	// Models without a tokenizer are still hashed in blocks of characters.
+	req3 := newRequest("other-model", "hello world hello world")
+	plugin.Score(context.Background(), types.NewCycleState(), req3, endpoints)
+	assert.Len(t, readState(req3).PrefixHashes, 5, "prompt should be hashed in blocks of 4 characters")
+
+	// Chat messages are rendered by the model server's chat template, so they are hashed in blocks of characters
+	// even with a tokenizer.
+	req4 := &types.LLMRequest{
+		RequestId:   uuid.NewString(),
+		TargetModel: "tokenized-model",
+		Body: &types.LLMRequestBody{ChatCompletions: &types.ChatCompletionsRequest{
+			Messages: []types.Message{{Role: "user", Content: types.Content{Raw: "hello world"}}},
+		}},
+	}
+	plugin.Score(context.Background(), types.NewCycleState(), req4, endpoints)
+	userInput, err := getUserInputBytes(req4)
+	assert.NoError(t, err)
+	assert.Len(t, readState(req4).PrefixHashes, len(userInput)/config.BlockSize, "chat should be hashed in blocks of 4 characters")
+}
+
+// countingTokenizer counts the prompts it encodes.
+type countingTokenizer struct {
+	Tokenizer
+	calls int
+}
+
+func (c *countingTokenizer) Encode(text string, addSpecialTokens bool) []uint32 {
+	c.calls++
+	return c.Tokenizer.Encode(text, addSpecialTokens)
+}
+
+func TestPrefixPluginTokenizesOnce(t *testing.T) {
+	hf, err := parseTokenizer([]byte(byteLevelTokenizerJSON))
+	assert.NoError(t, err)
+	tokenizer := &countingTokenizer{Tokenizer: hf}
+
+	config := Config{
+		BlockSize:              4,
+		MaxPrefixBlocksToMatch: 2,
+		LRUCapacityPerServer:   DefaultLRUCapacityPerServer,
+		TokenBlockSize:         2,
+	}
+	plugin := New(context.Background(), config).WithTokenizers(map[string]Tokenizer{"tokenized-model": tokenizer})
+	endpoint := &types.PodMetrics{EndpointMetadata: &datalayer.EndpointMetadata{NamespacedName: k8stypes.NamespacedName{Name: "pod1"}}, Metrics: datalayer.NewMetrics(), AttributeMap: datalayer.NewAttributes()}
+	endpoints := []types.Endpoint{endpoint}
+
+	request := &types.LLMRequest{
+		RequestId:   uuid.NewString(),
+		TargetModel: "tokenized-model",
+		Body:        &types.LLMRequestBody{Completions: &types.CompletionsRequest{Prompt: types.Prompt{Raw: "hello world hello world"}}},
+	}
+	assert.NoError(t, plugin.PrepareRequestData(context.Background(), request, endpoints))
+	plugin.Score(context.Background(), types.NewCycleState(), request, endpoints)
+	plugin.Score(context.Background(), types.NewCycleState(), request, endpoints)
+	assert.Equal(t, 1, tokenizer.calls, "the prompt should be tokenized once per request")
+
+	// Only the beginning of a long prompt is tokenized, cut at a word boundary.
+	hashes := hashPromptTokens(context.Background(), request, tokenizer, 0, config.TokenBlockSize, config.MaxPrefixBlocksToMatch)
+	request.Body.Completions.Prompt.Raw += strings.Repeat(" hello world", 1000)
+	assert.Equal(t, hashes, hashPromptTokens(context.Background(), request, tokenizer, 0, config.TokenBlockSize, config.MaxPrefixBlocksToMatch))
+}
+
+func TestPrefixPluginTokenIDs(t *testing.T) {
+	tokenizer, err := parseTokenizer([]byte(byteLevelTokenizerJSON))
+	assert.NoError(t, err)
+
+	config := Config{
+		BlockSize:              4,
+		MaxPrefixBlocksToMatch: DefaultMaxPrefixBlocks,
+		LRUCapacityPerServer:   DefaultLRUCapacityPerServer,
+		TokenBlockSize:         2,
+	}
+	plugin := New(context.Background(), config).WithTokenizers(map[string]Tokenizer{"tokenized-model": tokenizer})
+
+	endpoint1 := &types.PodMetrics{EndpointMetadata: &datalayer.EndpointMetadata{NamespacedName: k8stypes.NamespacedName{Name: "pod1"}}, Metrics: datalayer.NewMetrics()}
+	endpoints := []types.Endpoint{endpoint1}
+
+	newRequest := func(model string, prompt types.Prompt) *types.LLMRequest {
+		return &types.LLMRequest{
+			RequestId:   uuid.NewString(),
+			TargetModel: model,
+			Body:        &types.LLMRequestBody{Completions: &types.CompletionsRequest{Prompt: prompt}},
+		}
+	}
+	hashes := func(request *types.LLMRequest) []BlockHash {
+		plugin.Score(context.Background(), types.NewCycleState(), request, endpoints)
+		state, err := fwkplugin.ReadPluginStateKey[*SchedulingContextState](plugin.pluginState, request.RequestId, fwkplugin.StateKey(plugin.TypedName().String()))
+		assert.NoError(t, err)
+		return state.PrefixHashes
+	}
+
+	// Token IDs are hashed directly, in blocks of tokens, even without a tokenizer. The last token is ignored.
+	tokens := types.Prompt{Tokens: [][]uint32{{1, 2, 3, 4, 5}}}
+	assert.Len(t, hashes(newRequest("other-model", tokens)), 2, "token IDs should be hashed in blocks of 2 tokens")
+
+	// A batch of prompts is hashed by its first prompt.
+	batch := types.Prompt{Tokens: [][]uint32{{1, 2, 3, 4, 5}, {6, 7, 8, 9}}}
+	assert.Equal(t, hashes(newRequest("other-model", tokens)), hashes(newRequest("other-model", batch)))
+
+	// Token IDs hash like the same prompt tokenized locally.
+	text := "hello world hello world"
+	encoded := types.Prompt{Tokens: [][]uint32{tokenizer.Encode(text, true)}}
+	assert.Equal(t, hashes(newRequest("tokenized-model", types.Prompt{Raw: text})), hashes(newRequest("tokenized-model", encoded)))
+}
+
+// fakeIndexerProvider is a plugin providing an indexer to the prefix plugin.
+type fakeIndexerProvider struct {
+	indexer Indexer
+}
+
+func (f *fakeIndexerProvider) TypedName() fwkplugin.TypedName {
+	return fwkplugin.TypedName{Type: "fake-indexer-provider", Name: "fake-indexer-provider"}
+}
+
+func (f *fakeIndexerProvider) Indexer() Indexer {
+	return f.indexer
+}
+
+func TestPrefixCachePluginFactoryIndexerSource(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	handle := fwkplugin.NewEppHandle(ctx, func() []k8stypes.NamespacedName { return nil })
+	provider := &fakeIndexerProvider{indexer: newIndexer(ctx, DefaultLRUCapacityPerServer)}
+	handle.AddPlugin("exact-indexer", provider)
+	handle.AddPlugin("not-a-provider", New(ctx, DefaultConfig))
+
+	tokenizerPath := filepath.Join(t.TempDir(), "tokenizer.json")
+	require.NoError(t, os.WriteFile(tokenizerPath, []byte(byteLevelTokenizerJSON), 0o600))
+	withSource := func(source string) json.RawMessage {
+		return json.RawMessage(fmt.Sprintf(`{"indexerSource": %q, "tokenizerPaths": {"model": %q}}`, source, tokenizerPath))
+	}
+
+	p, err := PrefixCachePluginFactory("prefix", withSource("exact-indexer"), handle)
+	assert.NoError(t, err)
+	assert.Same(t, provider.indexer, p.(*Plugin).indexer, "indexer should be taken from the data source")
+
+	_, err = PrefixCachePluginFactory("prefix", json.RawMessage(`{"indexerSource": "exact-indexer"}`), handle)
+	assert.ErrorContains(t, err, "requires tokenizerPaths")
+
+	_, err = PrefixCachePluginFactory("prefix", withSource("not-a-provider"), handle)
+	assert.ErrorContains(t, err, "'not-a-provider' is not a plugin providing an indexer")
+
+	_, err = PrefixCachePluginFactory("prefix", withSource("missing"), handle)
+	assert.Error(t, err)
+}
@@ -0,0 +1,514 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prefix
+
+import (
+	"container/heap"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Tokenizer converts a prompt into the token IDs of a model.
+type Tokenizer interface {
+	// Encode returns the token IDs of the text. If addSpecialTokens is set, the special tokens the model server adds
+	// to a prompt, such as the BOS token, are added as well.
+	Encode(text string, addSpecialTokens bool) []uint32
+}
+
+// compile-time type assertion
+var _ Tokenizer = &hfTokenizer{}
+
+// hfTokenizer is a BPE tokenizer loaded from a HuggingFace tokenizer.json file.
+// It supports the byte-level variant (GPT-2, Llama 3, Qwen) and the metaspace variant (SentencePiece based models
+// such as Llama 2 and Mistral), which cover the tokenizers of most LLMs.
+// Byte-level pre-tokenization follows the GPT-2 split pattern, which closely approximates the patterns of newer
+// models. Token block boundaries therefore line up with the model server's KV blocks for the vast majority of prompts.
+type hfTokenizer struct {
+	vocab        map[string]uint32
+	mergeRanks   map[mergePair]int
+	addedTokens  map[string]uint32
+	addedPattern *regexp.Regexp // matches the added (special) tokens, nil if there are none
+	unkID        *uint32
+	byteFallback bool
+	byteLevel    bool
+	// metaspace is the replacement of spaces in the metaspace variant, empty for byte-level tokenizers.
+	metaspace string
+	// prependMetaspace adds a metaspace at the beginning of the text, as SentencePiece does.
+	prependMetaspace bool
+	// prefixTokens and suffixTokens are the special tokens the post-processor adds around a single sequence.
+	prefixTokens []uint32
+	suffixTokens []uint32
+}
+
+// mergePair is a pair of adjacent symbols that BPE may merge.
+type mergePair struct {
+	left, right string
+}
+
+// tokenizerFile is the subset of the HuggingFace tokenizer.json format used by hfTokenizer.
+type tokenizerFile struct {
+	AddedTokens []struct {
+		ID      uint32 `json:"id"`
+		Content string `json:"content"`
+	} `json:"added_tokens"`
+	Normalizer    *tokenizerComponent `json:"normalizer"`
+	PreTokenizer  *tokenizerComponent `json:"pre_tokenizer"`
+	PostProcessor *postProcessor      `json:"post_processor"`
+	Model         struct {
+		Type         string            `json:"type"`
+		Vocab        map[string]uint32 `json:"vocab"`
+		Merges       []json.RawMessage `json:"merges"`
+		ByteFallback bool              `json:"byte_fallback"`
+		UnkToken     *string           `json:"unk_token"`
+	} `json:"model"`
+}
+
+// tokenizerComponent is a normalizer or a pre-tokenizer of a tokenizer.json file.
+type tokenizerComponent struct {
+	Type           string               `json:"type"`
+	Normalizers    []tokenizerComponent `json:"normalizers"`
+	PreTokenizers  []tokenizerComponent `json:"pretokenizers"`
+	Prepend        string               `json:"prepend"`
+	Replacement    string               `json:"replacement"`
+	PrependScheme  string               `json:"prepend_scheme"`
+	AddPrefixSpace *bool                `json:"add_prefix_space"`
+	Content        string               `json:"content"`
+	Pattern        struct {
+		String string `json:"String"`
+	} `json:"pattern"`
+}
+
+// postProcessor is the post-processor of a tokenizer.json file. Only the template processing, which adds the special
+// tokens, is relevant to the token IDs.
+type postProcessor struct {
+	Type       string          `json:"type"`
+	Processors []postProcessor `json:"processors"`
+	Single     []struct {
+		SpecialToken *struct {
+			ID string `json:"id"`
+		} `json:"SpecialToken"`
+		Sequence *struct{} `json:"Sequence"`
+	} `json:"single"`
+	SpecialTokens map[string]struct {
+		IDs []uint32 `json:"ids"`
+	} `json:"special_tokens"`
+}
+
+// specialTokens returns the special tokens added before and after a single sequence.
+func (p *postProcessor) specialTokens() (prefix, suffix []uint32) {
+	if p == nil {
+		return nil, nil
+	}
+	for i := range p.Processors {
+		if prefix, suffix = p.Processors[i].specialTokens(); prefix != nil || suffix != nil {
+			return prefix, suffix
+		}
+	}
+	if p.Type != "TemplateProcessing" {
+		return nil, nil
+	}
+	inSequence := false
+	for _, piece := range p.Single {
+		switch {
+		case piece.Sequence != nil:
+			inSequence = true
+		case piece.SpecialToken != nil && inSequence:
+			suffix = append(suffix, p.SpecialTokens[piece.SpecialToken.ID].IDs...)
+		case piece.SpecialToken != nil:
+			prefix = append(prefix, p.SpecialTokens[piece.SpecialToken.ID].IDs...)
+		}
+	}
+	return prefix, suffix
+}
+
+// flatten returns the component and all of its nested components.
+func (c *tokenizerComponent) flatten() []tokenizerComponent {
+	if c == nil {
+		return nil
+	}
+	res := []tokenizerComponent{*c}
+	for _, nested := range append(slices.Clone(c.Normalizers), c.PreTokenizers...) {
+		res = append(res, nested.flatten()...)
+	}
+	return res
+}
+
+// loadTokenizers loads the tokenizer of each model from the given tokenizer.json paths.
+func loadTokenizers(paths map[string]string) (map[string]Tokenizer, error) {
+	tokenizers := make(map[string]Tokenizer, len(paths))
+	for model, path := range paths {
+		tokenizer, err := loadTokenizer(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the tokenizer of model '%s' - %w", model, err)
+		}
+		tokenizers[model] = tokenizer
+	}
+	return tokenizers, nil
+}
+
+// loadTokenizer loads a HuggingFace tokenizer.json file.
+func loadTokenizer(path string) (*hfTokenizer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseTokenizer(data)
+}
+
+// parseTokenizer parses the content of a HuggingFace tokenizer.json file.
+func parseTokenizer(data []byte) (*hfTokenizer, error) {
+	var file tokenizerFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse tokenizer - %w", err)
+	}
+	if file.Model.Type != "" && file.Model.Type != "BPE" {
+		return nil, fmt.Errorf("unsupported tokenizer model type '%s', only BPE is supported", file.Model.Type)
+	}
+	if len(file.Model.Vocab) == 0 {
+		return nil, fmt.Errorf("tokenizer has an empty vocabulary")
+	}
+
+	t := &hfTokenizer{
+		vocab:        file.Model.Vocab,
+		mergeRanks:   make(map[mergePair]int, len(file.Model.Merges)),
+		addedTokens:  make(map[string]uint32, len(file.AddedTokens)),
+		byteFallback: file.Model.ByteFallback,
+	}
+	for rank, raw := range file.Model.Merges {
+		pair, err := parseMerge(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid merge at index %d - %w", rank, err)
+		}
+		if _, exists := t.mergeRanks[pair]; !exists {
+			t.mergeRanks[pair] = rank
+		}
+	}
+	if file.Model.UnkToken != nil {
+		if id, ok := t.vocab[*file.Model.UnkToken]; ok {
+			t.unkID = &id
+		}
+	}
+
+	contents := make([]string, 0, len(file.AddedTokens))
+	for _, added := range file.AddedTokens {
+		t.addedTokens[added.Content] = added.ID
+		contents = append(contents, regexp.QuoteMeta(added.Content))
+	}
+	if len(contents) > 0 {
+		// Go regexp alternation is leftmost-first, so the longest tokens must come first.
+		slices.SortFunc(contents, func(a, b string) int { return len(b) - len(a) })
+		t.addedPattern = regexp.MustCompile(strings.Join(contents, "|"))
+	}
+
+	for _, component := range append(file.Normalizer.flatten(), file.PreTokenizer.flatten()...) {
+		switch component.Type {
+		case "ByteLevel":
+			t.byteLevel = true
+		case "Metaspace":
+			t.metaspace = component.Replacement
+			t.prependMetaspace = component.PrependScheme != "never" && (component.AddPrefixSpace == nil || *component.AddPrefixSpace)
+		case "Replace":
+			if component.Pattern.String == " " {
+				t.metaspace = component.Content
+			}
+		case "Prepend":
+			t.prependMetaspace = component.Prepend != ""
+		}
+	}
+	t.prefixTokens, t.suffixTokens = file.PostProcessor.specialTokens()
+	if !t.byteLevel && t.metaspace == "" {
+		return nil, fmt.Errorf("unsupported tokenizer, neither byte-level nor metaspace pre-tokenization is configured")
+	}
+	return t, nil
+}
+
+// parseMerge parses a merge rule, either in the legacy "left right" format or in the ["left", "right"] format.
+func parseMerge(raw json.RawMessage) (mergePair, error) {
+	var legacy string
+	if err := json.Unmarshal(raw, &legacy); err == nil {
+		left, right, found := strings.Cut(legacy, " ")
+		if !found {
+			return mergePair{}, fmt.Errorf("merge '%s' is not a pair", legacy)
+		}
+		return mergePair{left: left, right: right}, nil
+	}
+	var pair []string
+	if err := json.Unmarshal(raw, &pair); err != nil {
+		return mergePair{}, err
+	}
+	if len(pair) != 2 {
+		return mergePair{}, fmt.Errorf("merge %v is not a pair", pair)
+	}
+	return mergePair{left: pair[0], right: pair[1]}, nil
+}
+
+// Encode returns the token IDs of the given text.
+func (t *hfTokenizer) Encode(text string, addSpecialTokens bool) []uint32 {
+	ids := make([]uint32, 0, len(text)/averageCharactersPerToken+len(t.prefixTokens)+len(t.suffixTokens))
+	if addSpecialTokens {
+		ids = append(ids, t.prefixTokens...)
+	}
+	start := 0
+	if t.addedPattern != nil {
+		for _, loc := range t.addedPattern.FindAllStringIndex(text, -1) {
+			ids = t.encodeSegment(ids, text[start:loc[0]], start == 0)
+			ids = append(ids, t.addedTokens[text[loc[0]:loc[1]]])
+			start = loc[1]
+		}
+	}
+	ids = t.encodeSegment(ids, text[start:], start == 0)
+	if addSpecialTokens {
+		ids = append(ids, t.suffixTokens...)
+	}
+	return ids
+}
+
+// encodeSegment appends the token IDs of a text segment that contains no added tokens.
+func (t *hfTokenizer) encodeSegment(ids []uint32, segment string, isFirst bool) []uint32 {
+	if segment == "" {
+		return ids
+	}
+	if t.byteLevel {
+		for _, word := range splitWords(segment) {
+			ids = t.encodeWord(ids, byteLevelEncode(word))
+		}
+		return ids
+	}
+
+	segment = strings.ReplaceAll(segment, " ", t.metaspace)
+	if isFirst && t.prependMetaspace && !strings.HasPrefix(segment, t.metaspace) {
+		segment = t.metaspace + segment
+	}
+	// Merges never cross a metaspace boundary in practice, so each metaspace-prefixed word is encoded separately.
+	for len(segment) > 0 {
+		end := strings.Index(segment[len(t.metaspace):], t.metaspace)
+		if end < 0 {
+			end = len(segment)
+		} else {
+			end += len(t.metaspace)
+		}
+		ids = t.encodeWord(ids, segment[:end])
+		segment = segment[end:]
+	}
+	return ids
+}
+
+// encodeWord appends the token IDs of a single pre-tokenized word by applying the BPE merges.
+// The symbols of the word are kept in a linked list and the candidate merges in a heap ordered by rank, so that
+// encoding is O(n log n) in the length of the word.
+func (t *hfTokenizer) encodeWord(ids []uint32, word string) []uint32 {
+	if word == "" {
+		return ids
+	}
+	if id, ok := t.vocab[word]; ok {
+		return append(ids, id)
+	}
+
+	symbols := make([]bpeSymbol, 0, utf8.RuneCountInString(word))
+	for i, r := range word {
+		symbols = append(symbols, bpeSymbol{start: i, end: i + utf8.RuneLen(r), prev: len(symbols) - 1, next: len(symbols) + 1})
+	}
+	symbols[len(symbols)-1].next = -1
+
+	merges := &bpeMergeHeap{}
+	pushMerge := func(left int) {
+		if left < 0 || symbols[left].next < 0 {
+			return
+		}
+		right := symbols[left].next
+		pair := mergePair{left: word[symbols[left].start:symbols[left].end], right: word[symbols[right].start:symbols[right].end]}
+		if rank, ok := t.mergeRanks[pair]; ok {
+			heap.Push(merges, bpeMerge{rank: rank, left: left, right: right, end: symbols[right].end})
+		}
+	}
+	for i := range len(symbols) - 1 {
+		pushMerge(i)
+	}
+	for merges.Len() > 0 {
+		merge := heap.Pop(merges).(bpeMerge)
+		left, right := &symbols[merge.left], &symbols[merge.right]
+		// A merge is stale if either symbol was merged with another one since it was pushed.
+		if left.start < 0 || left.end != right.start || right.end != merge.end {
+			continue
+		}
+		left.end, left.next = right.end, right.next
+		if right.next >= 0 {
+			symbols[right.next].prev = merge.left
+		}
+		right.start = -1 // removed from the list
+		pushMerge(left.prev)
+		pushMerge(merge.left)
+	}
+
+	for i := 0; i >= 0; i = symbols[i].next {
+		symbol := word[symbols[i].start:symbols[i].end]
+		if id, ok := t.vocab[symbol]; ok {
+			ids = append(ids, id)
+			continue
+		}
+		if t.byteFallback {
+			for _, b := range []byte(symbol) {
+				if id, ok := t.vocab[fmt.Sprintf("<0x%02X>", b)]; ok {
+					ids = append(ids, id)
+				}
+			}
+			continue
+		}
+		if t.unkID != nil {
+			ids = append(ids, *t.unkID)
+		}
+	}
+	return ids
+}
+
+// bpeSymbol is a symbol of a word being encoded, spanning word[start:end], in a linked list of the word symbols.
+type bpeSymbol struct {
+	start, end int
+	prev, next int // -1 at the ends of the list
+}
+
+// bpeMerge is a candidate merge of two adjacent symbols. end is the end of the right symbol when the merge was
+// pushed, used to discard merges invalidated by the merges applied since.
+type bpeMerge struct {
+	rank        int
+	left, right int
+	end         int
+}
+
+// bpeMergeHeap orders the candidate merges by rank, then by position in the word, like HuggingFace tokenizers.
+type bpeMergeHeap []bpeMerge
+
+func (h bpeMergeHeap) Len() int { return len(h) }
+func (h bpeMergeHeap) Less(i, j int) bool {
+	if h[i].rank != h[j].rank {
+		return h[i].rank < h[j].rank
+	}
+	return h[i].left < h[j].left
+}
+func (h bpeMergeHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h *bpeMergeHeap) Push(x any)   { *h = append(*h, x.(bpeMerge)) }
+func (h *bpeMergeHeap) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// splitWords splits a text the way the GPT-2 pre-tokenization pattern does:
+// contractions, letters, numbers and other symbols optionally preceded by a space, and whitespace.
+func splitWords(text string) []string {
+	words := make([]string, 0, len(text)/averageCharactersPerToken)
+	for i := 0; i < len(text); {
+		if contraction := matchContraction(text[i:]); contraction > 0 {
+			words = append(words, text[i:i+contraction])
+			i += contraction
+			continue
+		}
+
+		start := i
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if r == ' ' && i+size < len(text) {
+			if next, nextSize := utf8.DecodeRuneInString(text[i+size:]); !unicode.IsSpace(next) {
+				i += size
+				r, size = next, nextSize
+			}
+		}
+
+		var class func(rune) bool
+		switch {
+		case unicode.IsLetter(r):
+			class = unicode.IsLetter
+		case unicode.IsNumber(r):
+			class = unicode.IsNumber
+		case !unicode.IsSpace(r):
+			class = func(r rune) bool { return !unicode.IsSpace(r) && !unicode.IsLetter(r) && !unicode.IsNumber(r) }
+		}
+		if class != nil {
+			i += size
+			for i < len(text) {
+				next, nextSize := utf8.DecodeRuneInString(text[i:])
+				if !class(next) {
+					break
+				}
+				i += nextSize
+			}
+			words = append(words, text[start:i])
+			continue
+		}
+
+		// A whitespace run followed by a word leaves its last character to the word (or on its own if not a space).
+		end, last := i, 0
+		for end < len(text) {
+			next, nextSize := utf8.DecodeRuneInString(text[end:])
+			if !unicode.IsSpace(next) {
+				break
+			}
+			end += nextSize
+			last = nextSize
+		}
+		if end < len(text) && end-i > last {
+			end -= last
+		}
+		words = append(words, text[i:end])
+		i = end
+	}
+	return words
+}
+
+// matchContraction returns the length of the English contraction at the beginning of the text, or 0.
+func matchContraction(text string) int {
+	if len(text) < 2 || text[0] != '\'' {
+		return 0
+	}
+	for _, suffix := range []string{"re", "ve", "ll", "s", "t", "m", "d"} {
+		if len(text) > len(suffix) && strings.EqualFold(text[1:1+len(suffix)], suffix) {
+			return 1 + len(suffix)
+		}
+	}
+	return 0
+}
+
+// byteToRune is the GPT-2 byte-level mapping of bytes to printable characters.
+var byteToRune = func() [256]rune {
+	var table [256]rune
+	next := rune(256)
+	for b := range 256 {
+		if (b >= '!' && b <= '~') || (b >= 0xA1 && b <= 0xAC) || (b >= 0xAE && b <= 0xFF) {
+			table[b] = rune(b)
+		} else {
+			table[b] = next
+			next++
+		}
+	}
+	return table
+}()
+
+// byteLevelEncode maps each byte of the word to its byte-level character.
+func byteLevelEncode(word string) string {
+	var sb strings.Builder
+	sb.Grow(len(word) * 2)
+	for i := 0; i < len(word); i++ {
+		sb.WriteRune(byteToRune[word[i]])
+	}
+	return sb.String()
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prefix
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// byteLevelTokenizerJSON is a minimal byte-level BPE tokenizer, in the format of GPT-2 and Llama 3 tokenizers.
+const byteLevelTokenizerJSON = `{
+	"added_tokens": [{"id": 100, "content": "<|eot|>", "special": true}],
+	"normalizer": null,
+	"pre_tokenizer": {"type": "Sequence", "pretokenizers": [{"type": "ByteLevel", "add_prefix_space": false}]},
+	"model": {
+		"type": "BPE",
+		"vocab": {"h": 0, "e": 1, "l": 2, "o": 3, "Ġ": 4, "w": 5, "r": 6, "d": 7, "he": 8, "ll": 9, "hell": 10,
+			"hello": 11, "Ġw": 12, "or": 13, "Ġwor": 14, "Ġworl": 15, "Ġworld": 16},
+		"merges": ["h e", "l l", "he ll", "hell o", "Ġ w", "o r", "Ġw or", "Ġwor l", "Ġworl d"]
+	}
+}`
+
+// metaspaceTokenizerJSON is a minimal metaspace BPE tokenizer with byte fallback, in the format of Mistral tokenizers.
+const metaspaceTokenizerJSON = `{
+	"pre_tokenizer": {"type": "Metaspace", "replacement": "▁", "prepend_scheme": "first", "split": false},
+	"model": {
+		"type": "BPE",
+		"byte_fallback": true,
+		"unk_token": "<unk>",
+		"vocab": {"<unk>": 0, "▁": 1, "a": 2, "b": 3, "▁a": 4, "▁ab": 5, "<0x21>": 6},
+		"merges": [["▁", "a"], ["▁a", "b"]]
+	}
+}`
+
+func TestTokenizerEncode(t *testing.T) {
+	byteLevel, err := parseTokenizer([]byte(byteLevelTokenizerJSON))
+	require.NoError(t, err)
+	metaspace, err := parseTokenizer([]byte(metaspaceTokenizerJSON))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		tokenizer Tokenizer
+		input     string
+		want      []uint32
+	}{
+		{name: "byte-level words", tokenizer: byteLevel, input: "hello world", want: []uint32{11, 16}},
+		{name: "byte-level added token", tokenizer: byteLevel, input: "hello<|eot|>hello", want: []uint32{11, 100, 11}},
+		{name: "byte-level partial merges", tokenizer: byteLevel, input: "held", want: []uint32{8, 2, 7}},
+		{name: "byte-level unknown bytes are dropped", tokenizer: byteLevel, input: "hello!", want: []uint32{11}},
+		{name: "metaspace prepends and replaces spaces", tokenizer: metaspace, input: "ab ab", want: []uint32{5, 5}},
+		{name: "metaspace byte fallback", tokenizer: metaspace, input: "ab!", want: []uint32{5, 6}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, test.tokenizer.Encode(test.input, false))
+		})
+	}
+}
+
+func TestTokenizerEncodeSpecialTokens(t *testing.T) {
+	// A Llama 3 style post-processor, adding a BOS token before the prompt.
+	withBOS := strings.Replace(byteLevelTokenizerJSON, `"normalizer": null,`, `"normalizer": null,
+	"post_processor": {"type": "Sequence", "processors": [
+		{"type": "ByteLevel"},
+		{"type": "TemplateProcessing",
+			"single": [{"SpecialToken": {"id": "<|bos|>", "type_id": 0}}, {"Sequence": {"id": "A", "type_id": 0}}],
+			"special_tokens": {"<|bos|>": {"id": "<|bos|>", "ids": [101], "tokens": ["<|bos|>"]}}}
+	]},`, 1)
+	tokenizer, err := parseTokenizer([]byte(withBOS))
+	require.NoError(t, err)
+
+	assert.Equal(t, []uint32{101, 11, 16}, tokenizer.Encode("hello world", true))
+	assert.Equal(t, []uint32{11, 16}, tokenizer.Encode("hello world", false))
+}
+
+// repeatedTokenizerJSON merges runs of "a" into tokens of up to four characters.
+const repeatedTokenizerJSON = `{
+	"pre_tokenizer": {"type": "ByteLevel", "add_prefix_space": false},
+	"model": {
+		"type": "BPE",
+		"vocab": {"a": 0, "aa": 1, "aaaa": 2},
+		"merges": ["a a", "aa aa"]
+	}
+}`
+
+func TestTokenizerEncodeLongWord(t *testing.T) {
+	tokenizer, err := parseTokenizer([]byte(repeatedTokenizerJSON))
+	require.NoError(t, err)
+
+	// Merges of equal rank are applied from left to right.
+	assert.Equal(t, []uint32{1, 0}, tokenizer.Encode("aaa", false))
+	assert.Equal(t, []uint32{2, 1, 0}, tokenizer.Encode("aaaaaaa", false))
+
+	// Long words, e.g. a base64 payload in the prompt, are fully merged.
+	tokens := tokenizer.Encode(strings.Repeat("a", 100_000), false)
+	assert.Len(t, tokens, 25_000)
+	assert.NotContains(t, tokens, uint32(0))
+	assert.NotContains(t, tokens, uint32(1))
+}
+
+func BenchmarkTokenizerEncodeLongWord(b *testing.B) {
+	tokenizer, err := parseTokenizer([]byte(repeatedTokenizerJSON))
+	require.NoError(b, err)
+	word := strings.Repeat("a", 100_000)
+
+	for b.Loop() {
+		tokenizer.Encode(word, false)
+	}
+}
+
+func TestSplitWords(t *testing.T) {
+	assert.Equal(t, []string{"Hello", " ", " world", "'s", " 123", "!", "\n", "\n", "ok", "  "},
+		splitWords("Hello  world's 123!\n\nok  "))
+	assert.Equal(t, []string{"你好", " 世界"}, splitWords("你好 世界"))
+}
+
+func TestLoadTokenizers(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "tokenizer.json")
+	require.NoError(t, os.WriteFile(valid, []byte(byteLevelTokenizerJSON), 0o600))
+	unsupported := filepath.Join(dir, "wordpiece.json")
+	require.NoError(t, os.WriteFile(unsupported, []byte(`{"model": {"type": "WordPiece", "vocab": {"a": 0}}}`), 0o600))
+
+	tokenizers, err := loadTokenizers(map[string]string{"model-a": valid})
+	require.NoError(t, err)
+	assert.Contains(t, tokenizers, "model-a")
+
+	_, err = loadTokenizers(map[string]string{"model-a": filepath.Join(dir, "missing.json")})
+	assert.ErrorContains(t, err, "model-a")
+
+	_, err = loadTokenizers(map[string]string{"model-b": unsupported})
+	assert.ErrorContains(t, err, "unsupported tokenizer model type 'WordPiece'")
+}
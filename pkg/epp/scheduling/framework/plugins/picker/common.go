@@ -18,6 +18,7 @@ package picker
 
 import (
 	"math/rand/v2"
+	"slices"
 	"time"
 
 	types "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/scheduling"
@@ -42,3 +43,19 @@ func shuffleScoredEndpoints(scoredEndpoints []*types.ScoredEndpoint) {
 		scoredEndpoints[i], scoredEndpoints[j] = scoredEndpoints[j], scoredEndpoints[i]
 	})
 }
+
+// sortScoredEndpointsByScore sorts the endpoints in-place by descending score, breaking ties randomly.
+func sortScoredEndpointsByScore(scoredEndpoints []*types.ScoredEndpoint) {
+	// Shuffle in-place - needed for random tie break when scores are equal
+	shuffleScoredEndpoints(scoredEndpoints)
+
+	slices.SortStableFunc(scoredEndpoints, func(i, j *types.ScoredEndpoint) int { // highest score first
+		if i.Score > j.Score {
+			return -1
+		}
+		if i.Score < j.Score {
+			return 1
+		}
+		return 0
+	})
+}
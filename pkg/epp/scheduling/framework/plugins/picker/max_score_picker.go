@@ -20,7 +20,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"slices"
 
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
@@ -82,18 +81,7 @@ func (p *MaxScorePicker) Pick(ctx context.Context, cycleState *framework.CycleSt
 	log.FromContext(ctx).V(logutil.DEBUG).Info("Selecting endpoints from candidates sorted by max score", "max-num-of-endpoints", p.maxNumOfEndpoints,
 		"num-of-candidates", len(scoredEndpoints), "scored-endpoints", scoredEndpoints)
 
-	// Shuffle in-place - needed for random tie break when scores are equal
-	shuffleScoredEndpoints(scoredEndpoints)
-
-	slices.SortStableFunc(scoredEndpoints, func(i, j *framework.ScoredEndpoint) int { // highest score first
-		if i.Score > j.Score {
-			return -1
-		}
-		if i.Score < j.Score {
-			return 1
-		}
-		return 0
-	})
+	sortScoredEndpointsByScore(scoredEndpoints)
 
 	// if we have enough endpoints to return keep only the "maxNumOfEndpoints" highest scored endpoints
 	if p.maxNumOfEndpoints < len(scoredEndpoints) {
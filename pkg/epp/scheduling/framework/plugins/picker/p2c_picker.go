@@ -0,0 +1,111 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package picker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/common/util/logging"
+	fwkplugin "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/plugin"
+	framework "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/scheduling"
+)
+
+const (
+	P2CPickerType = "p2c-picker"
+)
+
+// compile-time type validation
+var _ framework.Picker = &P2CPicker{}
+
+// P2CPickerFactory defines the factory function for P2CPicker.
+func P2CPickerFactory(name string, rawParameters json.RawMessage, _ fwkplugin.Handle) (fwkplugin.Plugin, error) {
+	parameters := pickerParameters{MaxNumOfEndpoints: DefaultMaxNumOfEndpoints}
+	if rawParameters != nil {
+		if err := json.Unmarshal(rawParameters, &parameters); err != nil {
+			return nil, fmt.Errorf("failed to parse the parameters of the '%s' picker - %w", P2CPickerType, err)
+		}
+	}
+
+	return NewP2CPicker(parameters.MaxNumOfEndpoints).WithName(name), nil
+}
+
+// NewP2CPicker initializes a new P2CPicker and returns its pointer.
+func NewP2CPicker(maxNumOfEndpoints int) *P2CPicker {
+	if maxNumOfEndpoints <= 0 {
+		maxNumOfEndpoints = DefaultMaxNumOfEndpoints // on invalid configuration value, fallback to default value
+	}
+
+	return &P2CPicker{
+		typedName:         fwkplugin.TypedName{Type: P2CPickerType, Name: P2CPickerType},
+		maxNumOfEndpoints: maxNumOfEndpoints,
+	}
+}
+
+// P2CPicker picks endpoint(s) using the power of two choices: it samples two random endpoints and picks the one with
+// the higher score. Compared to always picking the maximum score, this avoids herd effects where all requests go to
+// the same endpoint while its metrics are stale.
+// When more than one endpoint is required, the sampling is repeated on the endpoints that were not picked yet.
+type P2CPicker struct {
+	typedName         fwkplugin.TypedName
+	maxNumOfEndpoints int
+}
+
+// WithName sets the name of the picker.
+func (p *P2CPicker) WithName(name string) *P2CPicker {
+	p.typedName.Name = name
+	return p
+}
+
+// TypedName returns the type and name tuple of this plugin instance.
+func (p *P2CPicker) TypedName() fwkplugin.TypedName {
+	return p.typedName
+}
+
+// Pick selects the endpoint(s) with the power of two choices from the list of candidates.
+func (p *P2CPicker) Pick(ctx context.Context, _ *framework.CycleState, scoredEndpoints []*framework.ScoredEndpoint) *framework.ProfileRunResult {
+	log.FromContext(ctx).V(logutil.DEBUG).Info("Selecting endpoints from candidates by power of two choices", "max-num-of-endpoints", p.maxNumOfEndpoints,
+		"num-of-candidates", len(scoredEndpoints), "scored-endpoints", scoredEndpoints)
+
+	// Rand package is not safe for concurrent use, so we create a new instance.
+	randomGenerator := rand.New(rand.NewPCG(uint64(time.Now().UnixNano()), 0))
+
+	remaining := slices.Clone(scoredEndpoints)
+	targetEndpoints := make([]framework.Endpoint, 0, min(p.maxNumOfEndpoints, len(remaining)))
+	for len(targetEndpoints) < p.maxNumOfEndpoints && len(remaining) > 0 {
+		picked := randomGenerator.IntN(len(remaining))
+		if len(remaining) > 1 {
+			other := randomGenerator.IntN(len(remaining) - 1)
+			if other >= picked { // skip the first choice, so that the two choices are distinct
+				other++
+			}
+			if remaining[other].Score > remaining[picked].Score {
+				picked = other
+			}
+		}
+		targetEndpoints = append(targetEndpoints, remaining[picked])
+		remaining = slices.Delete(remaining, picked, picked+1)
+	}
+
+	return &framework.ProfileRunResult{TargetEndpoints: targetEndpoints}
+}
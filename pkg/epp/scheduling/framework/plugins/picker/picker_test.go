@@ -247,3 +247,146 @@ func TestPickWeightedRandomPicker(t *testing.T) {
 		})
 	}
 }
+
+func TestPickP2CPicker(t *testing.T) {
+	const (
+		testIterations = 10000
+		tolerance      = 0.05 // Verify within tolerance ±5%
+	)
+
+	endpoint1 := &framework.PodMetrics{EndpointMetadata: &datalayer.EndpointMetadata{NamespacedName: k8stypes.NamespacedName{Name: "pod1"}}}
+	endpoint2 := &framework.PodMetrics{EndpointMetadata: &datalayer.EndpointMetadata{NamespacedName: k8stypes.NamespacedName{Name: "pod2"}}}
+	endpoint3 := &framework.PodMetrics{EndpointMetadata: &datalayer.EndpointMetadata{NamespacedName: k8stypes.NamespacedName{Name: "pod3"}}}
+
+	input := []*framework.ScoredEndpoint{
+		{Endpoint: endpoint1, Score: 10},
+		{Endpoint: endpoint2, Score: 20},
+		{Endpoint: endpoint3, Score: 30},
+	}
+	// Each of the three pairs is sampled with equal probability and the higher scored endpoint of the pair wins,
+	// so the lowest scored endpoint is never picked.
+	expectedProbabilities := map[string]float64{"pod1": 0, "pod2": 1.0 / 3, "pod3": 2.0 / 3}
+
+	picker := NewP2CPicker(1)
+	selectionCounts := make(map[string]int)
+	for range testIterations {
+		result := picker.Pick(context.Background(), framework.NewCycleState(), input)
+		if len(result.TargetEndpoints) != 1 {
+			t.Fatalf("Expected a single endpoint, got %d", len(result.TargetEndpoints))
+		}
+		selectionCounts[result.TargetEndpoints[0].GetMetadata().NamespacedName.Name]++
+	}
+
+	for endpointName, expectedProb := range expectedProbabilities {
+		actualProb := float64(selectionCounts[endpointName]) / float64(testIterations)
+		if math.Abs(actualProb-expectedProb) > tolerance {
+			t.Errorf("Endpoint %s: expected probability %.3f ±%.1f%%, got %.3f", endpointName, expectedProb, tolerance*100, actualProb)
+		}
+	}
+
+	// When more endpoints than candidates are required, every candidate is returned exactly once.
+	result := NewP2CPicker(5).Pick(context.Background(), framework.NewCycleState(), input)
+	want := []framework.Endpoint{input[0], input[1], input[2]}
+	if diff := cmp.Diff(want, result.TargetEndpoints, cmpopts.SortSlices(func(a, b framework.Endpoint) bool {
+		return a.String() < b.String()
+	})); diff != "" {
+		t.Errorf("Unexpected output (-want +got): %v", diff)
+	}
+
+	// A single candidate is always picked.
+	result = NewP2CPicker(1).Pick(context.Background(), framework.NewCycleState(), input[:1])
+	if diff := cmp.Diff([]framework.Endpoint{input[0]}, result.TargetEndpoints); diff != "" {
+		t.Errorf("Unexpected output (-want +got): %v", diff)
+	}
+}
+
+func TestPickTopKOrderedPicker(t *testing.T) {
+	endpoint1 := &framework.PodMetrics{EndpointMetadata: &datalayer.EndpointMetadata{NamespacedName: k8stypes.NamespacedName{Name: "pod1"}}}
+	endpoint2 := &framework.PodMetrics{EndpointMetadata: &datalayer.EndpointMetadata{NamespacedName: k8stypes.NamespacedName{Name: "pod2"}}}
+	endpoint3 := &framework.PodMetrics{EndpointMetadata: &datalayer.EndpointMetadata{NamespacedName: k8stypes.NamespacedName{Name: "pod3"}}}
+	endpoint4 := &framework.PodMetrics{EndpointMetadata: &datalayer.EndpointMetadata{NamespacedName: k8stypes.NamespacedName{Name: "pod4"}}}
+
+	tests := []struct {
+		name   string
+		picker framework.Picker
+		input  []*framework.ScoredEndpoint
+		output []framework.Endpoint
+	}{
+		{
+			name:   "Top K in descending score order",
+			picker: NewTopKOrderedPicker(3, 0),
+			input: []*framework.ScoredEndpoint{
+				{Endpoint: endpoint1, Score: 10},
+				{Endpoint: endpoint2, Score: 40},
+				{Endpoint: endpoint3, Score: 20},
+				{Endpoint: endpoint4, Score: 30},
+			},
+			output: []framework.Endpoint{
+				&framework.ScoredEndpoint{Endpoint: endpoint2, Score: 40},
+				&framework.ScoredEndpoint{Endpoint: endpoint4, Score: 30},
+				&framework.ScoredEndpoint{Endpoint: endpoint3, Score: 20},
+			},
+		},
+		{
+			name:   "Less candidates than K",
+			picker: NewTopKOrderedPicker(3, 0),
+			input: []*framework.ScoredEndpoint{
+				{Endpoint: endpoint1, Score: 10},
+				{Endpoint: endpoint2, Score: 40},
+			},
+			output: []framework.Endpoint{
+				&framework.ScoredEndpoint{Endpoint: endpoint2, Score: 40},
+				&framework.ScoredEndpoint{Endpoint: endpoint1, Score: 10},
+			},
+		},
+		{
+			name:   "Fallbacks below the minimal score are omitted",
+			picker: NewTopKOrderedPicker(3, 25),
+			input: []*framework.ScoredEndpoint{
+				{Endpoint: endpoint1, Score: 10},
+				{Endpoint: endpoint2, Score: 40},
+				{Endpoint: endpoint3, Score: 20},
+				{Endpoint: endpoint4, Score: 30},
+			},
+			output: []framework.Endpoint{
+				&framework.ScoredEndpoint{Endpoint: endpoint2, Score: 40},
+				&framework.ScoredEndpoint{Endpoint: endpoint4, Score: 30},
+			},
+		},
+		{
+			name:   "Primary endpoint is kept below the minimal score",
+			picker: NewTopKOrderedPicker(3, 50),
+			input: []*framework.ScoredEndpoint{
+				{Endpoint: endpoint1, Score: 10},
+				{Endpoint: endpoint2, Score: 40},
+			},
+			output: []framework.Endpoint{
+				&framework.ScoredEndpoint{Endpoint: endpoint2, Score: 40},
+			},
+		},
+		{
+			name:   "Invalid K falls back to default",
+			picker: NewTopKOrderedPicker(0, 0),
+			input: []*framework.ScoredEndpoint{
+				{Endpoint: endpoint1, Score: 10},
+				{Endpoint: endpoint2, Score: 40},
+				{Endpoint: endpoint3, Score: 20},
+				{Endpoint: endpoint4, Score: 30},
+			},
+			output: []framework.Endpoint{
+				&framework.ScoredEndpoint{Endpoint: endpoint2, Score: 40},
+				&framework.ScoredEndpoint{Endpoint: endpoint4, Score: 30},
+				&framework.ScoredEndpoint{Endpoint: endpoint3, Score: 20},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := test.picker.Pick(context.Background(), framework.NewCycleState(), test.input)
+			if diff := cmp.Diff(test.output, result.TargetEndpoints); diff != "" {
+				t.Errorf("Unexpected output (-want +got): %v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package picker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/common/util/logging"
+	fwkplugin "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/plugin"
+	framework "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/scheduling"
+)
+
+const (
+	TopKOrderedPickerType = "topk-ordered-picker"
+
+	// DefaultTopKNumOfEndpoints is the default number of endpoints returned by the TopKOrderedPicker,
+	// i.e. the primary endpoint followed by two fallback endpoints.
+	DefaultTopKNumOfEndpoints = 3
+)
+
+// compile-time type validation
+var _ framework.Picker = &TopKOrderedPicker{}
+
+// topKOrderedPickerParameters defines the parameters of the TopKOrderedPicker.
+type topKOrderedPickerParameters struct {
+	pickerParameters
+	// MinFallbackScore is the minimal score of a fallback endpoint. Endpoints below it are not worth retrying on.
+	MinFallbackScore float64 `json:"minFallbackScore"`
+}
+
+// TopKOrderedPickerFactory defines the factory function for TopKOrderedPicker.
+func TopKOrderedPickerFactory(name string, rawParameters json.RawMessage, _ fwkplugin.Handle) (fwkplugin.Plugin, error) {
+	parameters := topKOrderedPickerParameters{pickerParameters: pickerParameters{MaxNumOfEndpoints: DefaultTopKNumOfEndpoints}}
+	if rawParameters != nil {
+		if err := json.Unmarshal(rawParameters, &parameters); err != nil {
+			return nil, fmt.Errorf("failed to parse the parameters of the '%s' picker - %w", TopKOrderedPickerType, err)
+		}
+	}
+
+	return NewTopKOrderedPicker(parameters.MaxNumOfEndpoints, parameters.MinFallbackScore).WithName(name), nil
+}
+
+// NewTopKOrderedPicker initializes a new TopKOrderedPicker and returns its pointer.
+func NewTopKOrderedPicker(maxNumOfEndpoints int, minFallbackScore float64) *TopKOrderedPicker {
+	if maxNumOfEndpoints <= 0 {
+		maxNumOfEndpoints = DefaultTopKNumOfEndpoints // on invalid configuration value, fallback to default value
+	}
+
+	return &TopKOrderedPicker{
+		typedName:         fwkplugin.TypedName{Type: TopKOrderedPickerType, Name: TopKOrderedPickerType},
+		maxNumOfEndpoints: maxNumOfEndpoints,
+		minFallbackScore:  minFallbackScore,
+	}
+}
+
+// TopKOrderedPicker picks up to maxNumOfEndpoints endpoints ordered by descending score.
+// The first endpoint is the primary destination and the next ones form the fallback order used by Envoy retries,
+// since the target endpoints are passed to Envoy as an ordered, comma separated list.
+// Fallback endpoints scored below minFallbackScore are omitted. The primary endpoint is always returned.
+type TopKOrderedPicker struct {
+	typedName         fwkplugin.TypedName
+	maxNumOfEndpoints int
+	minFallbackScore  float64
+}
+
+// WithName sets the name of the picker.
+func (p *TopKOrderedPicker) WithName(name string) *TopKOrderedPicker {
+	p.typedName.Name = name
+	return p
+}
+
+// TypedName returns the type and name tuple of this plugin instance.
+func (p *TopKOrderedPicker) TypedName() fwkplugin.TypedName {
+	return p.typedName
+}
+
+// Pick selects the highest scored endpoints from the list of candidates, in descending score order.
+func (p *TopKOrderedPicker) Pick(ctx context.Context, _ *framework.CycleState, scoredEndpoints []*framework.ScoredEndpoint) *framework.ProfileRunResult {
+	log.FromContext(ctx).V(logutil.DEBUG).Info("Selecting ordered endpoints from candidates sorted by score", "max-num-of-endpoints", p.maxNumOfEndpoints,
+		"min-fallback-score", p.minFallbackScore, "num-of-candidates", len(scoredEndpoints), "scored-endpoints", scoredEndpoints)
+
+	sortScoredEndpointsByScore(scoredEndpoints)
+
+	targetEndpoints := make([]framework.Endpoint, 0, min(p.maxNumOfEndpoints, len(scoredEndpoints)))
+	for i, scoredEndpoint := range scoredEndpoints {
+		if len(targetEndpoints) == p.maxNumOfEndpoints || (i > 0 && scoredEndpoint.Score < p.minFallbackScore) {
+			break
+		}
+		targetEndpoints = append(targetEndpoints, scoredEndpoint)
+	}
+
+	return &framework.ProfileRunResult{TargetEndpoints: targetEndpoints}
+}
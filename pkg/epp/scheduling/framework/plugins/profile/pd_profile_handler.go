@@ -0,0 +1,232 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profile
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/common/util/logging"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datalayer/plugins/approximateprefix"
+	fwkplugin "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/plugin"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/requestcontrol"
+	framework "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/scheduling"
+)
+
+const (
+	PDProfileHandlerType = "pd-profile-handler"
+
+	// DefaultPrefillProfile is the default name of the profile used to pick the prefill endpoint.
+	DefaultPrefillProfile = "prefill"
+	// DefaultDecodeProfile is the default name of the profile used to pick the decode endpoint.
+	DefaultDecodeProfile = "decode"
+	// PrefillEndpointHeader is the default header used to pass the selected prefill endpoint (host:port) to the
+	// decode model server, so that its KV-transfer connector can pull the KV cache from the prefill endpoint.
+	PrefillEndpointHeader = "x-prefiller-host-port"
+
+	// An estimated average characters per token, used since the request is not tokenized.
+	averageCharactersPerToken = 4
+)
+
+// compile-time type assertion
+var (
+	_ framework.ProfileHandler  = &PDProfileHandler{}
+	_ requestcontrol.PreRequest = &PDProfileHandler{}
+)
+
+// pdProfileHandlerParameters defines the parameters of the PDProfileHandler.
+type pdProfileHandlerParameters struct {
+	// PrefillProfile is the name of the profile used to pick the prefill endpoint.
+	PrefillProfile string `json:"prefillProfile"`
+	// DecodeProfile is the name of the profile used to pick the decode endpoint.
+	DecodeProfile string `json:"decodeProfile"`
+	// Threshold is the minimal number of uncached prompt tokens on the decode endpoint for which
+	// prefill is disaggregated. Requests below the threshold are served by the decode endpoint alone.
+	Threshold int `json:"threshold"`
+	// PrefillHeader is the header used to pass the selected prefill endpoint to the decode model server.
+	PrefillHeader string `json:"prefillHeader"`
+}
+
+// PDProfileHandlerFactory defines the factory function for PDProfileHandler.
+func PDProfileHandlerFactory(name string, rawParameters json.RawMessage, _ fwkplugin.Handle) (fwkplugin.Plugin, error) {
+	parameters := pdProfileHandlerParameters{
+		PrefillProfile: DefaultPrefillProfile,
+		DecodeProfile:  DefaultDecodeProfile,
+		PrefillHeader:  PrefillEndpointHeader,
+	}
+	if rawParameters != nil {
+		if err := json.Unmarshal(rawParameters, &parameters); err != nil {
+			return nil, fmt.Errorf("failed to parse the parameters of the '%s' profile handler - %w", PDProfileHandlerType, err)
+		}
+	}
+	if parameters.PrefillProfile == "" || parameters.DecodeProfile == "" {
+		return nil, fmt.Errorf("'%s' profile handler requires non empty prefill and decode profile names", PDProfileHandlerType)
+	}
+	if parameters.PrefillProfile == parameters.DecodeProfile {
+		return nil, fmt.Errorf("'%s' profile handler requires different prefill and decode profiles", PDProfileHandlerType)
+	}
+	if parameters.Threshold < 0 {
+		return nil, fmt.Errorf("'%s' profile handler threshold must not be negative", PDProfileHandlerType)
+	}
+	if parameters.PrefillHeader == "" {
+		parameters.PrefillHeader = PrefillEndpointHeader
+	}
+
+	return NewPDProfileHandler(parameters.PrefillProfile, parameters.DecodeProfile, parameters.PrefillHeader,
+		parameters.Threshold).WithName(name), nil
+}
+
+// NewPDProfileHandler initializes a new PDProfileHandler and returns its pointer.
+func NewPDProfileHandler(prefillProfile, decodeProfile, prefillHeader string, threshold int) *PDProfileHandler {
+	return &PDProfileHandler{
+		typedName:      fwkplugin.TypedName{Type: PDProfileHandlerType, Name: PDProfileHandlerType},
+		prefillProfile: prefillProfile,
+		decodeProfile:  decodeProfile,
+		prefillHeader:  prefillHeader,
+		threshold:      threshold,
+	}
+}
+
+// PDProfileHandler handles prefill/decode disaggregated scheduling.
+// The decode profile always runs first and is the primary profile. The prefill profile runs only when the
+// uncached part of the prompt on the selected decode endpoint is large enough for disaggregation to be worthwhile.
+// When a prefill endpoint is selected, it is passed to the decode model server in a request header.
+type PDProfileHandler struct {
+	typedName      fwkplugin.TypedName
+	prefillProfile string
+	decodeProfile  string
+	prefillHeader  string
+	threshold      int
+}
+
+// TypedName returns the type and name tuple of this plugin instance.
+func (h *PDProfileHandler) TypedName() fwkplugin.TypedName {
+	return h.typedName
+}
+
+// WithName sets the name of the profile handler.
+func (h *PDProfileHandler) WithName(name string) *PDProfileHandler {
+	h.typedName.Name = name
+	return h
+}
+
+// Pick selects the SchedulingProfiles to run from the list of candidate profiles, while taking into consideration the request properties and the
+// previously executed cycles along with their results.
+func (h *PDProfileHandler) Pick(ctx context.Context, _ *framework.CycleState, request *framework.LLMRequest, profiles map[string]*framework.SchedulerProfile,
+	profileResults map[string]*framework.ProfileRunResult) map[string]*framework.SchedulerProfile {
+	decodeResult, decodeExecuted := profileResults[h.decodeProfile]
+	if !decodeExecuted {
+		if profile, ok := profiles[h.decodeProfile]; ok {
+			return map[string]*framework.SchedulerProfile{h.decodeProfile: profile}
+		}
+		return map[string]*framework.SchedulerProfile{} // ProcessResults reports the missing decode profile
+	}
+
+	if _, prefillExecuted := profileResults[h.prefillProfile]; prefillExecuted {
+		return map[string]*framework.SchedulerProfile{}
+	}
+	profile, ok := profiles[h.prefillProfile]
+	if !ok || decodeResult == nil || len(decodeResult.TargetEndpoints) == 0 {
+		return map[string]*framework.SchedulerProfile{}
+	}
+
+	uncachedTokens := h.uncachedPromptTokens(request, decodeResult.TargetEndpoints[0])
+	if uncachedTokens < h.threshold || uncachedTokens == 0 {
+		log.FromContext(ctx).V(logutil.DEBUG).Info("Skipping disaggregated prefill", "uncachedTokens", uncachedTokens, "threshold", h.threshold)
+		return map[string]*framework.SchedulerProfile{}
+	}
+	return map[string]*framework.SchedulerProfile{h.prefillProfile: profile}
+}
+
+// ProcessResults handles the outcome of the profile runs after all profiles ran.
+// The decode profile is always the primary profile. A failed prefill profile run is dropped from the result,
+// in which case the decode endpoint serves the request alone.
+func (h *PDProfileHandler) ProcessResults(ctx context.Context, _ *framework.CycleState, _ *framework.LLMRequest,
+	profileResults map[string]*framework.ProfileRunResult) (*framework.SchedulingResult, error) {
+	decodeResult, ok := profileResults[h.decodeProfile]
+	if !ok {
+		return nil, errors.New("pd profile handler requires the decode profile to be executed")
+	}
+	if decodeResult == nil { // there was an error while running the profile
+		return nil, fmt.Errorf("failed to run scheduler profile '%s'", h.decodeProfile)
+	}
+
+	results := map[string]*framework.ProfileRunResult{h.decodeProfile: decodeResult}
+	if prefillResult, ok := profileResults[h.prefillProfile]; ok {
+		if prefillResult != nil {
+			results[h.prefillProfile] = prefillResult
+		} else {
+			log.FromContext(ctx).V(logutil.DEFAULT).Info("Prefill profile failed, serving the request without disaggregation",
+				"profile", h.prefillProfile)
+		}
+	}
+
+	return &framework.SchedulingResult{
+		ProfileResults:     results,
+		PrimaryProfileName: h.decodeProfile,
+	}, nil
+}
+
+// PreRequest sets the prefill endpoint header when a prefill endpoint was selected.
+// Any value of the header sent by the client is removed, so that it can't redirect the KV-transfer.
+func (h *PDProfileHandler) PreRequest(_ context.Context, request *framework.LLMRequest, schedulingResult *framework.SchedulingResult) {
+	if request.Headers == nil {
+		return
+	}
+	delete(request.Headers, h.prefillHeader)
+
+	prefillResult, ok := schedulingResult.ProfileResults[h.prefillProfile]
+	if !ok || prefillResult == nil || len(prefillResult.TargetEndpoints) == 0 {
+		return
+	}
+	metadata := prefillResult.TargetEndpoints[0].GetMetadata()
+	request.Headers[h.prefillHeader] = net.JoinHostPort(metadata.GetIPAddress(), metadata.GetPort())
+}
+
+// uncachedPromptTokens estimates the number of prompt tokens that are not in the KV cache of the given endpoint.
+// The prefix cache match information is produced by the prefix-cache-scorer. Without it, the whole prompt
+// is considered uncached.
+func (h *PDProfileHandler) uncachedPromptTokens(request *framework.LLMRequest, endpoint framework.Endpoint) int {
+	promptTokens := promptTokens(request)
+	raw, ok := endpoint.Get(approximateprefix.PrefixCacheMatchInfoKey)
+	if !ok {
+		return promptTokens
+	}
+	info, ok := raw.(*approximateprefix.PrefixCacheMatchInfo)
+	if !ok || info.TotalLength() == 0 {
+		return promptTokens
+	}
+	hitRatio := float64(info.MatchLength()) / float64(info.TotalLength())
+	return int(float64(promptTokens) * (1 - hitRatio))
+}
+
+// promptTokens returns the number of prompt tokens of the request: exact if the prompt was sent as token IDs, and
+// estimated from the length of its user input otherwise.
+func promptTokens(request *framework.LLMRequest) int {
+	if request == nil || request.Body == nil {
+		return 0
+	}
+	if count := request.Body.TokenCount(); count > 0 {
+		return count
+	}
+	return len(request.Body.PlainText()) / averageCharactersPerToken
+}
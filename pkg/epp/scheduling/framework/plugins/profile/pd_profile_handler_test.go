@@ -0,0 +1,269 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profile
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datalayer"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datalayer/plugins/approximateprefix"
+	types "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/scheduling"
+)
+
+func newTestEndpoint(address string, matchLen, total int) types.Endpoint {
+	endpoint := &types.PodMetrics{
+		EndpointMetadata: &datalayer.EndpointMetadata{Address: address, Port: "8000"},
+		Metrics:          &datalayer.Metrics{},
+		AttributeMap:     datalayer.NewAttributes(),
+	}
+	if total > 0 {
+		endpoint.Put(approximateprefix.PrefixCacheMatchInfoKey, approximateprefix.NewPrefixCacheMatchInfo(matchLen, total))
+	}
+	return endpoint
+}
+
+func newTestRequest(promptLen int) *types.LLMRequest {
+	return &types.LLMRequest{
+		RequestId: "test-request",
+		Body: &types.LLMRequestBody{
+			Completions: &types.CompletionsRequest{Prompt: types.Prompt{Raw: strings.Repeat("a", promptLen)}},
+		},
+		Headers: map[string]string{},
+	}
+}
+
+func TestPDProfileHandlerPick(t *testing.T) {
+	profiles := map[string]*types.SchedulerProfile{
+		DefaultPrefillProfile: types.NewSchedulerProfile(),
+		DefaultDecodeProfile:  types.NewSchedulerProfile(),
+	}
+
+	tests := []struct {
+		name           string
+		threshold      int
+		request        *types.LLMRequest
+		profileResults map[string]*types.ProfileRunResult
+		wantProfiles   []string
+	}{
+		{
+			name:           "decode runs first",
+			request:        newTestRequest(4000),
+			profileResults: map[string]*types.ProfileRunResult{},
+			wantProfiles:   []string{DefaultDecodeProfile},
+		},
+		{
+			name:      "prefill runs when the prompt is not cached",
+			threshold: 100,
+			request:   newTestRequest(4000), // ~1000 tokens
+			profileResults: map[string]*types.ProfileRunResult{
+				DefaultDecodeProfile: {TargetEndpoints: []types.Endpoint{newTestEndpoint("10.0.0.1", 0, 10)}},
+			},
+			wantProfiles: []string{DefaultPrefillProfile},
+		},
+		{
+			name:      "prefill skipped when most of the prompt is cached on the decode endpoint",
+			threshold: 100,
+			request:   newTestRequest(4000), // ~1000 tokens, 950 cached
+			profileResults: map[string]*types.ProfileRunResult{
+				DefaultDecodeProfile: {TargetEndpoints: []types.Endpoint{newTestEndpoint("10.0.0.1", 19, 20)}},
+			},
+			wantProfiles: []string{},
+		},
+		{
+			name:      "prefill skipped for short prompts",
+			threshold: 100,
+			request:   newTestRequest(40),
+			profileResults: map[string]*types.ProfileRunResult{
+				DefaultDecodeProfile: {TargetEndpoints: []types.Endpoint{newTestEndpoint("10.0.0.1", 0, 0)}},
+			},
+			wantProfiles: []string{},
+		},
+		{
+			name:    "prefill skipped when decode failed",
+			request: newTestRequest(4000),
+			profileResults: map[string]*types.ProfileRunResult{
+				DefaultDecodeProfile: nil,
+			},
+			wantProfiles: []string{},
+		},
+		{
+			name:    "nothing left to run after prefill",
+			request: newTestRequest(4000),
+			profileResults: map[string]*types.ProfileRunResult{
+				DefaultDecodeProfile:  {TargetEndpoints: []types.Endpoint{newTestEndpoint("10.0.0.1", 0, 0)}},
+				DefaultPrefillProfile: {TargetEndpoints: []types.Endpoint{newTestEndpoint("10.0.0.2", 0, 0)}},
+			},
+			wantProfiles: []string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			handler := NewPDProfileHandler(DefaultPrefillProfile, DefaultDecodeProfile, PrefillEndpointHeader, test.threshold)
+			got := handler.Pick(context.Background(), types.NewCycleState(), test.request, profiles, test.profileResults)
+
+			gotProfiles := make([]string, 0, len(got))
+			for name := range got {
+				gotProfiles = append(gotProfiles, name)
+			}
+			assert.ElementsMatch(t, test.wantProfiles, gotProfiles)
+		})
+	}
+}
+
+func TestPDProfileHandlerProcessResults(t *testing.T) {
+	decodeEndpoint := newTestEndpoint("10.0.0.1", 0, 0)
+	prefillEndpoint := newTestEndpoint("10.0.0.2", 0, 0)
+
+	tests := []struct {
+		name           string
+		profileResults map[string]*types.ProfileRunResult
+		wantErr        bool
+		wantProfiles   []string
+	}{
+		{
+			name: "disaggregated",
+			profileResults: map[string]*types.ProfileRunResult{
+				DefaultDecodeProfile:  {TargetEndpoints: []types.Endpoint{decodeEndpoint}},
+				DefaultPrefillProfile: {TargetEndpoints: []types.Endpoint{prefillEndpoint}},
+			},
+			wantProfiles: []string{DefaultDecodeProfile, DefaultPrefillProfile},
+		},
+		{
+			name: "decode only",
+			profileResults: map[string]*types.ProfileRunResult{
+				DefaultDecodeProfile: {TargetEndpoints: []types.Endpoint{decodeEndpoint}},
+			},
+			wantProfiles: []string{DefaultDecodeProfile},
+		},
+		{
+			name: "failed prefill is dropped",
+			profileResults: map[string]*types.ProfileRunResult{
+				DefaultDecodeProfile:  {TargetEndpoints: []types.Endpoint{decodeEndpoint}},
+				DefaultPrefillProfile: nil,
+			},
+			wantProfiles: []string{DefaultDecodeProfile},
+		},
+		{
+			name: "failed decode",
+			profileResults: map[string]*types.ProfileRunResult{
+				DefaultDecodeProfile: nil,
+			},
+			wantErr: true,
+		},
+		{
+			name:           "decode not executed",
+			profileResults: map[string]*types.ProfileRunResult{},
+			wantErr:        true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			handler := NewPDProfileHandler(DefaultPrefillProfile, DefaultDecodeProfile, PrefillEndpointHeader, 0)
+			result, err := handler.ProcessResults(context.Background(), types.NewCycleState(), newTestRequest(0), test.profileResults)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, DefaultDecodeProfile, result.PrimaryProfileName)
+
+			gotProfiles := make([]string, 0, len(result.ProfileResults))
+			for name := range result.ProfileResults {
+				gotProfiles = append(gotProfiles, name)
+			}
+			assert.ElementsMatch(t, test.wantProfiles, gotProfiles)
+		})
+	}
+}
+
+func TestPDProfileHandlerPreRequest(t *testing.T) {
+	handler := NewPDProfileHandler(DefaultPrefillProfile, DefaultDecodeProfile, PrefillEndpointHeader, 0)
+	decodeEndpoint := newTestEndpoint("10.0.0.1", 0, 0)
+	prefillEndpoint := newTestEndpoint("10.0.0.2", 0, 0)
+
+	request := newTestRequest(0)
+	handler.PreRequest(context.Background(), request, &types.SchedulingResult{
+		ProfileResults: map[string]*types.ProfileRunResult{
+			DefaultDecodeProfile:  {TargetEndpoints: []types.Endpoint{decodeEndpoint}},
+			DefaultPrefillProfile: {TargetEndpoints: []types.Endpoint{prefillEndpoint}},
+		},
+		PrimaryProfileName: DefaultDecodeProfile,
+	})
+	assert.Equal(t, "10.0.0.2:8000", request.Headers[PrefillEndpointHeader])
+
+	// A client provided header must not survive when no prefill endpoint was selected.
+	request = newTestRequest(0)
+	request.Headers[PrefillEndpointHeader] = "1.2.3.4:8000"
+	handler.PreRequest(context.Background(), request, &types.SchedulingResult{
+		ProfileResults: map[string]*types.ProfileRunResult{
+			DefaultDecodeProfile: {TargetEndpoints: []types.Endpoint{decodeEndpoint}},
+		},
+		PrimaryProfileName: DefaultDecodeProfile,
+	})
+	assert.NotContains(t, request.Headers, PrefillEndpointHeader)
+}
+
+func TestPromptTokens(t *testing.T) {
+	// Prompts sent as text are estimated from their length.
+	assert.Equal(t, 25, promptTokens(newTestRequest(100)))
+
+	// Prompts sent as token IDs are counted exactly.
+	request := &types.LLMRequest{Body: &types.LLMRequestBody{
+		Completions: &types.CompletionsRequest{Prompt: types.Prompt{Tokens: [][]uint32{{1, 2, 3}, {4, 5}}}},
+	}}
+	assert.Equal(t, 5, promptTokens(request))
+
+	assert.Equal(t, 0, promptTokens(nil))
+}
+
+func TestPDProfileHandlerFactory(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  string
+		wantErr bool
+	}{
+		{name: "defaults", params: ""},
+		{name: "custom", params: `{"prefillProfile": "p", "decodeProfile": "d", "threshold": 512, "prefillHeader": "x-prefill"}`},
+		{name: "same profiles", params: `{"prefillProfile": "p", "decodeProfile": "p"}`, wantErr: true},
+		{name: "negative threshold", params: `{"threshold": -1}`, wantErr: true},
+		{name: "invalid json", params: `{`, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var raw json.RawMessage
+			if test.params != "" {
+				raw = json.RawMessage(test.params)
+			}
+			p, err := PDProfileHandlerFactory("pd", raw, nil)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "pd", p.TypedName().Name)
+		})
+	}
+}
@@ -0,0 +1,234 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scorer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/cespare/xxhash/v2"
+	lru "github.com/hashicorp/golang-lru/v2"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datalayer"
+	fwkplugin "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/plugin"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/requestcontrol"
+	framework "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/scheduling"
+)
+
+const (
+	SessionAffinityScorerType = "session-affinity-scorer"
+
+	// DefaultSessionHeader is the default request header carrying the session key.
+	DefaultSessionHeader = "x-session-id"
+
+	// SessionKeySourceHeader takes the session key from the configured request header.
+	SessionKeySourceHeader = "header"
+	// SessionKeySourceCacheSalt takes the session key from the cache_salt field of the request body.
+	SessionKeySourceCacheSalt = "cacheSalt"
+	// SessionKeySourceUser takes the session key from the OpenAI user field of the request body.
+	SessionKeySourceUser = "user"
+	// SessionKeySourcePreviousResponseID selects the endpoint that generated the response referenced by the
+	// previous_response_id field of a Responses API request, since that endpoint holds the conversation state.
+	SessionKeySourcePreviousResponseID = "previousResponseId"
+
+	// responseCacheSize is the number of response ids whose generating endpoint is remembered.
+	responseCacheSize = 100000
+)
+
+// DefaultSessionKeySources is the default order in which the session key sources are tried.
+var DefaultSessionKeySources = []string{SessionKeySourcePreviousResponseID, SessionKeySourceHeader, SessionKeySourceCacheSalt,
+	SessionKeySourceUser}
+
+// compile-time type assertion
+var (
+	_ framework.Scorer                = &SessionAffinityScorer{}
+	_ requestcontrol.ResponseComplete = &SessionAffinityScorer{}
+)
+
+// sessionAffinityScorerParameters defines the parameters of the SessionAffinityScorer.
+type sessionAffinityScorerParameters struct {
+	// SessionHeader is the request header carrying the session key.
+	SessionHeader string `json:"sessionHeader"`
+	// KeySources is the ordered list of sources the session key is taken from. The first non empty key is used.
+	KeySources []string `json:"keySources"`
+}
+
+// SessionAffinityScorerFactory defines the factory function for SessionAffinityScorer.
+func SessionAffinityScorerFactory(name string, rawParameters json.RawMessage, _ fwkplugin.Handle) (fwkplugin.Plugin, error) {
+	parameters := sessionAffinityScorerParameters{
+		SessionHeader: DefaultSessionHeader,
+		KeySources:    DefaultSessionKeySources,
+	}
+	if rawParameters != nil {
+		if err := json.Unmarshal(rawParameters, &parameters); err != nil {
+			return nil, fmt.Errorf("failed to parse the parameters of the '%s' scorer - %w", SessionAffinityScorerType, err)
+		}
+	}
+	if len(parameters.KeySources) == 0 {
+		return nil, fmt.Errorf("'%s' scorer requires at least one key source", SessionAffinityScorerType)
+	}
+	for _, source := range parameters.KeySources {
+		switch source {
+		case SessionKeySourceHeader:
+			if parameters.SessionHeader == "" {
+				return nil, fmt.Errorf("'%s' scorer requires a session header for the '%s' key source", SessionAffinityScorerType, source)
+			}
+		case SessionKeySourceCacheSalt, SessionKeySourceUser, SessionKeySourcePreviousResponseID:
+		default:
+			return nil, fmt.Errorf("'%s' scorer has an unknown key source '%s'", SessionAffinityScorerType, source)
+		}
+	}
+
+	return NewSessionAffinityScorer(parameters.SessionHeader, parameters.KeySources).WithName(name), nil
+}
+
+// NewSessionAffinityScorer initializes a new SessionAffinityScorer and returns its pointer.
+func NewSessionAffinityScorer(sessionHeader string, keySources []string) *SessionAffinityScorer {
+	responseEndpoints, _ := lru.New[string, k8stypes.NamespacedName](responseCacheSize) // size is always positive
+	return &SessionAffinityScorer{
+		typedName:         fwkplugin.TypedName{Type: SessionAffinityScorerType, Name: SessionAffinityScorerType},
+		sessionHeader:     sessionHeader,
+		keySources:        keySources,
+		responseEndpoints: responseEndpoints,
+	}
+}
+
+// SessionAffinityScorer consistently maps a session key to one of the candidate endpoints.
+// It uses rendezvous (highest random weight) hashing over the candidate endpoints, so that endpoints joining
+// or leaving remap only the sessions that were mapped to them. The selected endpoint is scored 1 and all other
+// endpoints are scored 0. Requests without a session key score all endpoints 0.
+// Responses API requests that continue a previous response are instead mapped to the endpoint that generated it,
+// which is learned from the response ids of the completed requests.
+type SessionAffinityScorer struct {
+	typedName         fwkplugin.TypedName
+	sessionHeader     string
+	keySources        []string
+	responseEndpoints *lru.Cache[string, k8stypes.NamespacedName]
+}
+
+// TypedName returns the type and name tuple of this plugin instance.
+func (s *SessionAffinityScorer) TypedName() fwkplugin.TypedName {
+	return s.typedName
+}
+
+// Category returns the preference the scorer applies when scoring candidate endpoints.
+func (s *SessionAffinityScorer) Category() framework.ScorerCategory {
+	return framework.Affinity
+}
+
+// WithName sets the name of the scorer.
+func (s *SessionAffinityScorer) WithName(name string) *SessionAffinityScorer {
+	s.typedName.Name = name
+	return s
+}
+
+// Score returns the scoring result for the given list of endpoints based on context.
+func (s *SessionAffinityScorer) Score(_ context.Context, _ *framework.CycleState, request *framework.LLMRequest, endpoints []framework.Endpoint) map[framework.Endpoint]float64 {
+	scores := make(map[framework.Endpoint]float64, len(endpoints))
+	for _, endpoint := range endpoints {
+		scores[endpoint] = 0
+	}
+
+	if request == nil {
+		return scores
+	}
+	for _, source := range s.keySources {
+		if source == SessionKeySourcePreviousResponseID {
+			if endpoint := s.previousResponseEndpoint(request, endpoints); endpoint != nil {
+				scores[endpoint] = 1
+				return scores
+			}
+			continue
+		}
+
+		if sessionKey := s.sessionKey(request, source); sessionKey != "" {
+			if selected := rendezvousSelect(sessionKey, endpoints); selected != nil {
+				scores[selected] = 1
+			}
+			return scores
+		}
+	}
+	return scores
+}
+
+// ResponseComplete remembers the endpoint that generated the response, so that Responses API requests continuing it
+// can be sent to the same endpoint.
+func (s *SessionAffinityScorer) ResponseComplete(_ context.Context, _ *framework.LLMRequest, response *requestcontrol.Response,
+	targetEndpoint *datalayer.EndpointMetadata) {
+	if response == nil || response.ResponseID == "" || targetEndpoint == nil {
+		return
+	}
+	s.responseEndpoints.Add(response.ResponseID, targetEndpoint.NamespacedName)
+}
+
+// previousResponseEndpoint returns the candidate endpoint that generated the previous response of the request,
+// or nil if it is unknown or not a candidate.
+func (s *SessionAffinityScorer) previousResponseEndpoint(request *framework.LLMRequest, endpoints []framework.Endpoint) framework.Endpoint {
+	if request.Body == nil || request.Body.PreviousResponseID() == "" {
+		return nil
+	}
+	name, ok := s.responseEndpoints.Get(request.Body.PreviousResponseID())
+	if !ok {
+		return nil
+	}
+	for _, endpoint := range endpoints {
+		if endpoint.GetMetadata().NamespacedName == name {
+			return endpoint
+		}
+	}
+	return nil
+}
+
+// sessionKey returns the session key of the request from the given source.
+func (s *SessionAffinityScorer) sessionKey(request *framework.LLMRequest, source string) string {
+	switch source {
+	case SessionKeySourceHeader:
+		return request.Headers[s.sessionHeader]
+	case SessionKeySourceCacheSalt:
+		if request.Body != nil {
+			return request.Body.CacheSalt()
+		}
+	case SessionKeySourceUser:
+		if request.Body != nil {
+			return request.Body.User()
+		}
+	}
+	return ""
+}
+
+// rendezvousSelect returns the endpoint with the highest rendezvous weight for the given session key.
+func rendezvousSelect(sessionKey string, endpoints []framework.Endpoint) framework.Endpoint {
+	var selected framework.Endpoint
+	var selectedWeight uint64
+	for _, endpoint := range endpoints {
+		if weight := rendezvousWeight(sessionKey, endpoint); selected == nil || weight > selectedWeight {
+			selected, selectedWeight = endpoint, weight
+		}
+	}
+	return selected
+}
+
+// rendezvousWeight returns the weight of the endpoint for the given session key.
+func rendezvousWeight(sessionKey string, endpoint framework.Endpoint) uint64 {
+	h := xxhash.New()
+	_, _ = h.WriteString(sessionKey)
+	_, _ = h.WriteString("/")
+	_, _ = h.WriteString(endpoint.GetMetadata().NamespacedName.String())
+	return h.Sum64()
+}
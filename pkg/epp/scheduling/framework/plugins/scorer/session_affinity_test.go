@@ -0,0 +1,174 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scorer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datalayer"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/requestcontrol"
+	types "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/scheduling"
+)
+
+func newSessionTestEndpoints(n int) []types.Endpoint {
+	endpoints := make([]types.Endpoint, n)
+	for i := range endpoints {
+		endpoints[i] = &types.PodMetrics{
+			EndpointMetadata: &datalayer.EndpointMetadata{NamespacedName: k8stypes.NamespacedName{Namespace: "default", Name: fmt.Sprintf("pod%d", i)}},
+			Metrics:          &datalayer.Metrics{},
+		}
+	}
+	return endpoints
+}
+
+// selectedEndpoint returns the single endpoint with a score of 1, or nil if there is none.
+func selectedEndpoint(t *testing.T, scores map[types.Endpoint]float64) types.Endpoint {
+	var selected types.Endpoint
+	for endpoint, score := range scores {
+		if score == 1 {
+			require.Nil(t, selected, "expected a single selected endpoint")
+			selected = endpoint
+		} else {
+			require.Zero(t, score)
+		}
+	}
+	return selected
+}
+
+func TestSessionAffinityScorerKeySources(t *testing.T) {
+	endpoints := newSessionTestEndpoints(5)
+	scorer := NewSessionAffinityScorer(DefaultSessionHeader, DefaultSessionKeySources)
+	score := func(request *types.LLMRequest) types.Endpoint {
+		return selectedEndpoint(t, scorer.Score(context.Background(), types.NewCycleState(), request, endpoints))
+	}
+
+	chatBody := func(cacheSalt, user string) *types.LLMRequestBody {
+		return &types.LLMRequestBody{ChatCompletions: &types.ChatCompletionsRequest{CacheSalt: cacheSalt, User: user}}
+	}
+
+	assert.Nil(t, score(&types.LLMRequest{Body: chatBody("", "")}), "no session key should select no endpoint")
+
+	byHeader := score(&types.LLMRequest{Headers: map[string]string{DefaultSessionHeader: "session-1"}, Body: chatBody("salt", "user")})
+	assert.Equal(t, byHeader, score(&types.LLMRequest{Body: chatBody("session-1", "user")}),
+		"header should take precedence and map like the same cache_salt")
+	assert.Equal(t, byHeader, score(&types.LLMRequest{Body: chatBody("", "session-1")}),
+		"user field should be used when no other key is present")
+	assert.Equal(t, byHeader, score(&types.LLMRequest{Body: &types.LLMRequestBody{Completions: &types.CompletionsRequest{User: "session-1"}}}))
+}
+
+func TestSessionAffinityScorerPreviousResponseID(t *testing.T) {
+	endpoints := newSessionTestEndpoints(5)
+	scorer := NewSessionAffinityScorer(DefaultSessionHeader, DefaultSessionKeySources)
+	score := func(request *types.LLMRequest, candidates []types.Endpoint) types.Endpoint {
+		return selectedEndpoint(t, scorer.Score(context.Background(), types.NewCycleState(), request, candidates))
+	}
+	continuation := &types.LLMRequest{Body: &types.LLMRequestBody{Responses: &types.ResponsesRequest{
+		Input:              types.ResponsesInput{Raw: "and then?"},
+		PreviousResponseID: "resp_1",
+		User:               "user-1",
+	}}}
+	byUser := score(&types.LLMRequest{Body: &types.LLMRequestBody{Completions: &types.CompletionsRequest{User: "user-1"}}}, endpoints)
+
+	assert.Equal(t, byUser, score(continuation, endpoints), "an unknown previous response should fall back to the next key source")
+
+	// Pick an endpoint different from the one selected by the user key.
+	generating := endpoints[0]
+	if generating == byUser {
+		generating = endpoints[1]
+	}
+	scorer.ResponseComplete(context.Background(), nil, &requestcontrol.Response{ResponseID: "resp_1"}, generating.GetMetadata())
+	assert.Equal(t, generating, score(continuation, endpoints), "the endpoint that generated the previous response should be selected")
+
+	remaining := make([]types.Endpoint, 0, len(endpoints)-1)
+	for _, endpoint := range endpoints {
+		if endpoint != generating {
+			remaining = append(remaining, endpoint)
+		}
+	}
+	assert.Equal(t, byUser, score(continuation, remaining), "a generating endpoint that is not a candidate should be ignored")
+
+	headerOnly := NewSessionAffinityScorer(DefaultSessionHeader, []string{SessionKeySourceHeader})
+	headerOnly.ResponseComplete(context.Background(), nil, &requestcontrol.Response{ResponseID: "resp_1"}, generating.GetMetadata())
+	assert.Nil(t, selectedEndpoint(t, headerOnly.Score(context.Background(), types.NewCycleState(), continuation, endpoints)),
+		"the previous response should be ignored when it is not a key source")
+}
+
+func TestSessionAffinityScorerMinimalRemapping(t *testing.T) {
+	endpoints := newSessionTestEndpoints(10)
+	scorer := NewSessionAffinityScorer(DefaultSessionHeader, []string{SessionKeySourceHeader})
+
+	sessions := 1000
+	before := make(map[string]types.Endpoint, sessions)
+	counts := make(map[types.Endpoint]int, len(endpoints))
+	for i := 0; i < sessions; i++ {
+		key := fmt.Sprintf("session-%d", i)
+		request := &types.LLMRequest{Headers: map[string]string{DefaultSessionHeader: key}}
+		selected := selectedEndpoint(t, scorer.Score(context.Background(), types.NewCycleState(), request, endpoints))
+		require.NotNil(t, selected)
+		before[key] = selected
+		counts[selected]++
+	}
+	assert.Len(t, counts, len(endpoints), "sessions should be spread over all endpoints")
+
+	// Remove one endpoint: only the sessions mapped to it may move.
+	removed := endpoints[3]
+	remaining := append(append([]types.Endpoint{}, endpoints[:3]...), endpoints[4:]...)
+	for key, previous := range before {
+		request := &types.LLMRequest{Headers: map[string]string{DefaultSessionHeader: key}}
+		selected := selectedEndpoint(t, scorer.Score(context.Background(), types.NewCycleState(), request, remaining))
+		if previous != removed {
+			assert.Equal(t, previous, selected, "session %s should not be remapped", key)
+		}
+	}
+}
+
+func TestSessionAffinityScorerFactory(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  string
+		wantErr bool
+	}{
+		{name: "defaults", params: ""},
+		{name: "custom", params: `{"sessionHeader": "x-conversation", "keySources": ["user", "header"]}`},
+		{name: "previous response id", params: `{"keySources": ["previousResponseId"]}`},
+		{name: "unknown source", params: `{"keySources": ["cookie"]}`, wantErr: true},
+		{name: "empty sources", params: `{"keySources": []}`, wantErr: true},
+		{name: "header source without header", params: `{"sessionHeader": "", "keySources": ["header"]}`, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var raw json.RawMessage
+			if test.params != "" {
+				raw = json.RawMessage(test.params)
+			}
+			_, err := SessionAffinityScorerFactory("session", raw, nil)
+			if test.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -18,14 +18,43 @@ package error
 
 import (
 	"fmt"
+	"time"
 )
 
 // Error is an error struct for errors returned by the epp server.
 type Error struct {
 	Code string
 	Msg  string
+	// RetryAfter is how long the client should wait before retrying the request, sent in the Retry-After header of
+	// the response. It is ignored if zero.
+	RetryAfter time.Duration
+	// Queue describes the Flow Control queue of a request rejected or evicted from it. When set, the error is sent as
+	// a JSON body holding its details rather than as plain text.
+	Queue *QueueDetails
 }
 
+// QueueDetails describes the Flow Control queue of a request that was rejected or evicted from it.
+type QueueDetails struct {
+	// Reason is why the request left the queue, QueueAtCapacity, QueueTimeout or QueuePreempted.
+	Reason string `json:"reason"`
+	// PriorityBand is the name of the priority band of the request.
+	PriorityBand string `json:"priorityBand"`
+	// Priority is the priority level of the band.
+	Priority int `json:"priority"`
+	// QueueDepth is the number of requests queued in the band.
+	QueueDepth uint64 `json:"queueDepth"`
+}
+
+// Reasons of the QueueDetails.
+const (
+	// QueueAtCapacity is the reason of a request rejected because its queue was full.
+	QueueAtCapacity = "QueueAtCapacity"
+	// QueueTimeout is the reason of a request evicted because it waited in its queue for longer than its TTL.
+	QueueTimeout = "QueueTimeout"
+	// QueuePreempted is the reason of a request evicted from its queue to make room for a request of higher priority.
+	QueuePreempted = "QueuePreempted"
+)
+
 const (
 	Unknown                        = "Unknown"
 	BadRequest                     = "BadRequest"
@@ -34,6 +63,8 @@ const (
 	ModelServerError               = "ModelServerError"
 	BadConfiguration               = "BadConfiguration"
 	InferencePoolResourceExhausted = "InferencePoolResourceExhausted"
+	// RateLimited is returned when the request exceeds the rate limits configured for its fairness ID.
+	RateLimited = "RateLimited"
 )
 
 // Error returns a string version of the error.
@@ -0,0 +1,133 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package error
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// Canonical codes of the errors returned by model servers.
+const (
+	// ContextLengthExceeded is returned when the prompt and the requested output do not fit in the model context.
+	ContextLengthExceeded = "ContextLengthExceeded"
+	// ModelServerRateLimited is returned when the model server rejects the request because of its own rate limits.
+	ModelServerRateLimited = "ModelServerRateLimited"
+	// ModelServerOutOfMemory is returned when the model server runs out of (accelerator) memory serving the request.
+	ModelServerOutOfMemory = "ModelServerOutOfMemory"
+	// AdapterNotFound is returned when the model server does not serve the requested model or LoRA adapter.
+	AdapterNotFound = "AdapterNotFound"
+	// ModelServerBadRequest is returned for the other client errors (4xx) of the model server.
+	ModelServerBadRequest = "ModelServerBadRequest"
+)
+
+// modelServerError holds the fields of the error bodies of the OpenAI-compatible model servers, which nest them in
+// an error object ({"error": {"message": ..., "type": ..., "code": ...}}), of older vLLM versions, which send them at
+// the top level ({"object": "error", "message": ..., "type": "BadRequestError", "code": 400}), and of the Anthropic
+// Messages API ({"type": "error", "error": {"type": "rate_limit_error", "message": ...}}).
+type modelServerError struct {
+	Message string          `json:"message"`
+	Type    string          `json:"type"`
+	Code    json.RawMessage `json:"code"`
+	Error   json.RawMessage `json:"error"`
+}
+
+// ClassifyModelServerError returns the canonical code of an error response of a model server, from its HTTP status
+// and its body. The body may be JSON or plain text. A status of 0 or 200 stands for an error event sent in a
+// successful streamed response.
+func ClassifyModelServerError(status int, body []byte) string {
+	errType, code, message := parseModelServerError(body)
+	message = strings.ToLower(message)
+
+	switch {
+	case status == http.StatusTooManyRequests || errType == "rate_limit_error" || code == "rate_limit_exceeded" ||
+		code == "429":
+		return ModelServerRateLimited
+	case code == "context_length_exceeded" || containsAny(message, contextLengthMessages):
+		return ContextLengthExceeded
+	case errType == "outofmemoryerror" || containsAny(message, outOfMemoryMessages):
+		return ModelServerOutOfMemory
+	case code == "model_not_found" || ((status == http.StatusNotFound || errType == "notfounderror" || code == "404") &&
+		strings.Contains(message, "model")) || containsAny(message, adapterNotFoundMessages):
+		return AdapterNotFound
+	case status >= http.StatusBadRequest && status < http.StatusInternalServerError:
+		return ModelServerBadRequest
+	}
+	return ModelServerError
+}
+
+// Substrings of the lower-cased messages of the model server errors, for the servers that report them with a
+// generic type or as plain text.
+var (
+	contextLengthMessages = []string{
+		// OpenAI and vLLM: "This model's maximum context length is 4096 tokens. However, you requested..."
+		"maximum context length",
+		// vLLM: "The prompt (total length 5000) is too long to fit into the model (context length 4096)..." and
+		// "...is longer than the maximum model length of 4096."
+		"maximum model length",
+		"is too long to fit into the model",
+		// Anthropic: "prompt is too long: 210000 tokens > 200000 maximum"
+		"prompt is too long",
+		// TGI: "Input validation error: `inputs` tokens + `max_new_tokens` must be <= 4096."
+		"`inputs` tokens + `max_new_tokens`",
+	}
+	outOfMemoryMessages = []string{
+		// PyTorch: "CUDA out of memory. Tried to allocate..."
+		"out of memory",
+		"outofmemoryerror",
+	}
+	adapterNotFoundMessages = []string{
+		// vLLM: "Loading lora sql-lora failed: No adapter found for /adapters/sql-lora"
+		"no adapter found",
+		"lora adapter not found",
+	}
+)
+
+// parseModelServerError returns the lower-cased type and code and the message of an error body. A body that is
+// not a JSON error is returned as the message.
+func parseModelServerError(body []byte) (string, string, string) {
+	var parsed modelServerError
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return "", "", string(body)
+	}
+	if len(parsed.Error) > 0 {
+		var nested modelServerError
+		if json.Unmarshal(parsed.Error, &nested) == nil {
+			parsed = nested
+		} else {
+			// e.g. TGI and KServe: {"error": "...", "error_type": "..."}
+			var message string
+			_ = json.Unmarshal(parsed.Error, &message)
+			parsed = modelServerError{Message: message}
+		}
+	}
+	code := strings.Trim(string(parsed.Code), `"`)
+	if code == "null" {
+		code = ""
+	}
+	return strings.ToLower(parsed.Type), strings.ToLower(code), parsed.Message
+}
+
+func containsAny(s string, substrings []string) bool {
+	for _, substring := range substrings {
+		if strings.Contains(s, substring) {
+			return true
+		}
+	}
+	return false
+}
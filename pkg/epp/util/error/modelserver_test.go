@@ -0,0 +1,120 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package error
+
+import "testing"
+
+func TestClassifyModelServerError(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   string
+	}{
+		{
+			name:   "vLLM context length overflow",
+			status: 400,
+			body:   `{"object":"error","message":"This model's maximum context length is 4096 tokens. However, you requested 5000 tokens (4900 in the messages, 100 in the completion). Please reduce the length of the messages or completion.","type":"BadRequestError","param":null,"code":400}`,
+			want:   ContextLengthExceeded,
+		},
+		{
+			name:   "vLLM nested error prompt too long",
+			status: 400,
+			body:   `{"error":{"message":"The prompt (total length 5000) is too long to fit into the model (context length 4096).","type":"BadRequestError","param":null,"code":400}}`,
+			want:   ContextLengthExceeded,
+		},
+		{
+			name:   "OpenAI context length code",
+			status: 400,
+			body:   `{"error":{"message":"Too many tokens.","type":"invalid_request_error","param":"messages","code":"context_length_exceeded"}}`,
+			want:   ContextLengthExceeded,
+		},
+		{
+			name:   "Anthropic prompt too long",
+			status: 400,
+			body:   `{"type":"error","error":{"type":"invalid_request_error","message":"prompt is too long: 210000 tokens > 200000 maximum"}}`,
+			want:   ContextLengthExceeded,
+		},
+		{
+			name:   "TGI validation error",
+			status: 422,
+			body:   "{\"error\":\"Input validation error: `inputs` tokens + `max_new_tokens` must be <= 4096. Given: 4000 `inputs` tokens and 200 `max_new_tokens`\",\"error_type\":\"validation\"}",
+			want:   ContextLengthExceeded,
+		},
+		{
+			name:   "too many requests status",
+			status: 429,
+			body:   `Too Many Requests`,
+			want:   ModelServerRateLimited,
+		},
+		{
+			name:   "Anthropic rate limit error",
+			status: 200,
+			body:   `{"type":"error","error":{"type":"rate_limit_error","message":"Number of request tokens has exceeded your per-minute rate limit"}}`,
+			want:   ModelServerRateLimited,
+		},
+		{
+			name:   "plain text CUDA out of memory",
+			status: 500,
+			body:   `torch.OutOfMemoryError: CUDA out of memory. Tried to allocate 2.00 GiB.`,
+			want:   ModelServerOutOfMemory,
+		},
+		{
+			name:   "vLLM model does not exist",
+			status: 404,
+			body:   "{\"object\":\"error\",\"message\":\"The model `sql-lora` does not exist.\",\"type\":\"NotFoundError\",\"param\":null,\"code\":404}",
+			want:   AdapterNotFound,
+		},
+		{
+			name:   "vLLM LoRA load failure",
+			status: 400,
+			body:   `{"object":"error","message":"Loading lora sql-lora failed: No adapter found for /adapters/sql-lora","type":"BadRequestError","param":null,"code":400}`,
+			want:   AdapterNotFound,
+		},
+		{
+			name:   "other client error",
+			status: 400,
+			body:   `{"object":"error","message":"temperature must be non-negative","type":"BadRequestError","param":null,"code":400}`,
+			want:   ModelServerBadRequest,
+		},
+		{
+			name:   "other server error",
+			status: 500,
+			body:   `{"object":"error","message":"EngineCore encountered an issue.","type":"InternalServerError","param":null,"code":500}`,
+			want:   ModelServerError,
+		},
+		{
+			name:   "empty body",
+			status: 503,
+			want:   ModelServerError,
+		},
+		{
+			name:   "upstream connection error",
+			status: 503,
+			body:   `upstream connect error or disconnect/reset before headers. reset reason: connection failure`,
+			want:   ModelServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ClassifyModelServerError(tt.status, []byte(tt.body)); got != tt.want {
+				t.Errorf("ClassifyModelServerError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -18,38 +18,202 @@ package request
 
 import (
 	"encoding/json"
+	"strings"
 
 	types "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/framework/interface/scheduling"
 	errutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/error"
 )
 
-// ExtractRequestBody extracts the LLMRequestBody from the given request body map.
-func ExtractRequestBody(rawBody map[string]any) (*types.LLMRequestBody, error) {
-	// Convert map back to JSON bytes
-	jsonBytes, err := json.Marshal(rawBody)
+// responsesOnlyFields are fields of the responses API that the embeddings API doesn't have. They tell the two apart
+// when the request path is unknown, since both have an input field.
+var responsesOnlyFields = []string{"instructions", "previous_response_id", "tools", "tool_choice", "reasoning", "text",
+	"max_output_tokens", "store", "stream", "conversation"}
+
+// ExtractRequestBody extracts the LLMRequestBody from the given raw request body.
+// The API of the request is determined by the request path if it is a known endpoint, and by the body fields otherwise.
+// Only the fields of the LLMRequestBody are decoded, the rest of the body is skipped over.
+func ExtractRequestBody(rawBody []byte, path string) (*types.LLMRequestBody, error) {
+	switch kindFromPath(path) {
+	case types.RequestKindCompletions:
+		return extractCompletions(rawBody)
+	case types.RequestKindChatCompletions:
+		return extractChatCompletions(rawBody)
+	case types.RequestKindResponses:
+		return extractResponses(rawBody)
+	case types.RequestKindEmbeddings:
+		return extractEmbeddings(rawBody)
+	case types.RequestKindRerank:
+		return extractRerank(rawBody)
+	case types.RequestKindAnthropicMessages:
+		return extractAnthropicMessages(rawBody)
+	case types.RequestKindOpenInference:
+		return extractOpenInference(rawBody)
+	}
+
+	fields, err := topLevelFields(rawBody)
 	if err != nil {
 		return nil, errutil.Error{Code: errutil.BadRequest, Msg: "invalid request body"}
 	}
 
+	// Try open inference, which is identified by its input tensors or by the text input of the generate extension
+	_, hasInputs := fields["inputs"]
+	_, hasTextInput := fields["text_input"]
+	if hasInputs || hasTextInput {
+		return extractOpenInference(rawBody)
+	}
+
 	// Try completions request first
-	var completions types.CompletionsRequest
-	if err = json.Unmarshal(jsonBytes, &completions); err == nil && completions.Prompt != "" {
-		return &types.LLMRequestBody{Completions: &completions}, nil
+	if _, ok := fields["prompt"]; ok {
+		if completions, err := extractCompletions(rawBody); err == nil {
+			return completions, nil
+		}
+	}
+
+	// Try rerank, which is identified by its query and documents fields
+	_, hasQuery := fields["query"]
+	_, hasDocuments := fields["documents"]
+	if hasQuery && hasDocuments {
+		return extractRerank(rawBody)
+	}
+
+	// Try responses and embeddings, which are identified by their input field
+	if input, ok := fields["input"]; ok {
+		if isResponsesBody(fields, input) {
+			return extractResponses(rawBody)
+		}
+		return extractEmbeddings(rawBody)
+	}
+
+	// Try anthropic messages, which is identified by its top-level system prompt
+	if _, ok := fields["system"]; ok {
+		return extractAnthropicMessages(rawBody)
 	}
 
 	// Try chat completions
+	return extractChatCompletions(rawBody)
+}
+
+// kindFromPath returns the kind of request served by the given request path, or RequestKindUnknown.
+func kindFromPath(path string) types.RequestKind {
+	path, _, _ = strings.Cut(path, "?")
+	path = strings.TrimSuffix(path, "/")
+	switch {
+	case strings.Contains(path, openInferenceModelsPrefix):
+		if strings.HasSuffix(path, "/infer") || strings.HasSuffix(path, "/generate") || strings.HasSuffix(path, "/generate_stream") {
+			return types.RequestKindOpenInference
+		}
+		return types.RequestKindUnknown
+	case strings.HasSuffix(path, "/chat/completions"):
+		return types.RequestKindChatCompletions
+	case strings.HasSuffix(path, "/completions"):
+		return types.RequestKindCompletions
+	case strings.HasSuffix(path, "/responses"):
+		return types.RequestKindResponses
+	case strings.HasSuffix(path, "/embeddings"):
+		return types.RequestKindEmbeddings
+	case strings.HasSuffix(path, "/rerank"):
+		return types.RequestKindRerank
+	case strings.HasSuffix(path, "/messages"):
+		return types.RequestKindAnthropicMessages
+	}
+	return types.RequestKindUnknown
+}
+
+// isResponsesBody returns true if a body with an input field is a responses request rather than an embeddings request:
+// its input is a list of input items or it has responses specific fields.
+func isResponsesBody(fields map[string][]byte, input []byte) bool {
+	if len(input) > 0 && input[0] == '[' {
+		if i := skipSpace(input, 1); i < len(input) && input[i] == '{' {
+			return true
+		}
+	}
+	for _, field := range responsesOnlyFields {
+		if _, ok := fields[field]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func extractCompletions(jsonBytes []byte) (*types.LLMRequestBody, error) {
+	var completions types.CompletionsRequest
+	if err := json.Unmarshal(jsonBytes, &completions); err != nil {
+		return nil, errutil.Error{Code: errutil.BadRequest, Msg: "invalid completions request: " + err.Error()}
+	}
+	if completions.Prompt.IsEmpty() {
+		return nil, errutil.Error{Code: errutil.BadRequest, Msg: "invalid completions request: prompt must not be empty"}
+	}
+	return &types.LLMRequestBody{Completions: &completions}, nil
+}
+
+func extractChatCompletions(jsonBytes []byte) (*types.LLMRequestBody, error) {
 	var chatCompletions types.ChatCompletionsRequest
-	if err = json.Unmarshal(jsonBytes, &chatCompletions); err != nil {
+	if err := json.Unmarshal(jsonBytes, &chatCompletions); err != nil {
 		return nil, errutil.Error{Code: errutil.BadRequest, Msg: "invalid request format"}
 	}
 
-	if err = validateChatCompletionsMessages(chatCompletions.Messages); err != nil {
+	if err := validateChatCompletionsMessages(chatCompletions.Messages); err != nil {
 		return nil, errutil.Error{Code: errutil.BadRequest, Msg: "invalid chat-completions request: " + err.Error()}
 	}
 
 	return &types.LLMRequestBody{ChatCompletions: &chatCompletions}, nil
 }
 
+func extractResponses(jsonBytes []byte) (*types.LLMRequestBody, error) {
+	var responses types.ResponsesRequest
+	if err := json.Unmarshal(jsonBytes, &responses); err != nil {
+		return nil, errutil.Error{Code: errutil.BadRequest, Msg: "invalid responses request: " + err.Error()}
+	}
+	if responses.Input.IsEmpty() {
+		return nil, errutil.Error{Code: errutil.BadRequest, Msg: "invalid responses request: input must not be empty"}
+	}
+	return &types.LLMRequestBody{Responses: &responses}, nil
+}
+
+func extractEmbeddings(jsonBytes []byte) (*types.LLMRequestBody, error) {
+	var embeddings types.EmbeddingsRequest
+	if err := json.Unmarshal(jsonBytes, &embeddings); err != nil {
+		return nil, errutil.Error{Code: errutil.BadRequest, Msg: "invalid embeddings request: " + err.Error()}
+	}
+	if embeddings.Input.IsEmpty() {
+		return nil, errutil.Error{Code: errutil.BadRequest, Msg: "invalid embeddings request: input must not be empty"}
+	}
+	return &types.LLMRequestBody{Embeddings: &embeddings}, nil
+}
+
+func extractRerank(jsonBytes []byte) (*types.LLMRequestBody, error) {
+	var rerank types.RerankRequest
+	if err := json.Unmarshal(jsonBytes, &rerank); err != nil {
+		return nil, errutil.Error{Code: errutil.BadRequest, Msg: "invalid rerank request: " + err.Error()}
+	}
+	if rerank.Query == "" || len(rerank.Documents) == 0 {
+		return nil, errutil.Error{Code: errutil.BadRequest, Msg: "invalid rerank request: query and documents must not be empty"}
+	}
+	return &types.LLMRequestBody{Rerank: &rerank}, nil
+}
+
+func extractAnthropicMessages(jsonBytes []byte) (*types.LLMRequestBody, error) {
+	var messages types.AnthropicMessagesRequest
+	if err := json.Unmarshal(jsonBytes, &messages); err != nil {
+		return nil, errutil.Error{Code: errutil.BadRequest, Msg: "invalid anthropic messages request: " + err.Error()}
+	}
+	if len(messages.Messages) == 0 {
+		return nil, errutil.Error{Code: errutil.BadRequest, Msg: "invalid anthropic messages request: messages must not be empty"}
+	}
+	return &types.LLMRequestBody{AnthropicMessages: &messages}, nil
+}
+
+func extractOpenInference(jsonBytes []byte) (*types.LLMRequestBody, error) {
+	var openInference types.OpenInferenceRequest
+	if err := json.Unmarshal(jsonBytes, &openInference); err != nil {
+		return nil, errutil.Error{Code: errutil.BadRequest, Msg: "invalid open inference request: " + err.Error()}
+	}
+	if openInference.IsEmpty() {
+		return nil, errutil.Error{Code: errutil.BadRequest, Msg: "invalid open inference request: inputs or text_input must not be empty"}
+	}
+	return &types.LLMRequestBody{OpenInference: &openInference}, nil
+}
+
 func validateChatCompletionsMessages(messages []types.Message) error {
 	if len(messages) == 0 {
 		return errutil.Error{Code: errutil.BadRequest, Msg: "chat-completions request must have at least one message"}
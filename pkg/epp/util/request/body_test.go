@@ -17,6 +17,7 @@ limitations under the License.
 package request
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -27,6 +28,7 @@ func TestExtractRequestData(t *testing.T) {
 	tests := []struct {
 		name    string
 		body    map[string]any
+		path    string
 		want    *types.LLMRequestBody
 		wantErr bool
 	}{
@@ -38,7 +40,7 @@ func TestExtractRequestData(t *testing.T) {
 			},
 			want: &types.LLMRequestBody{
 				Completions: &types.CompletionsRequest{
-					Prompt: "test prompt",
+					Prompt: types.Prompt{Raw: "test prompt"},
 				},
 			},
 		},
@@ -328,7 +330,7 @@ func TestExtractRequestData(t *testing.T) {
 			},
 			want: &types.LLMRequestBody{
 				Completions: &types.CompletionsRequest{
-					Prompt:    "test prompt",
+					Prompt:    types.Prompt{Raw: "test prompt"},
 					CacheSalt: "Z3V2bmV3aGxza3ZubGFoZ3Zud3V3ZWZ2bmd0b3V2bnZmc2xpZ3RoZ2x2aQ==",
 				},
 			},
@@ -357,11 +359,369 @@ func TestExtractRequestData(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "responses request with text input",
+			body: map[string]any{
+				"model":        "test",
+				"instructions": "be concise",
+				"input":        "hello",
+			},
+			want: &types.LLMRequestBody{
+				Responses: &types.ResponsesRequest{
+					Instructions: "be concise",
+					Input:        types.ResponsesInput{Raw: "hello"},
+				},
+			},
+		},
+		{
+			name: "responses request with input items",
+			body: map[string]any{
+				"model": "test",
+				"input": []any{
+					map[string]any{"role": "user", "content": "what is the weather?"},
+					map[string]any{
+						"type": "function_call", "call_id": "call_1", "name": "get_weather", "arguments": `{"city":"Paris"}`,
+					},
+					map[string]any{"type": "function_call_output", "call_id": "call_1", "output": "sunny"},
+					map[string]any{
+						"role": "user",
+						"content": []any{
+							map[string]any{"type": "input_text", "text": "and this image?"},
+							map[string]any{"type": "input_image", "image_url": "https://example.com/image.png"},
+						},
+					},
+				},
+				"previous_response_id": "resp_123",
+				"cache_salt":           "salt",
+				"user":                 "user-1",
+			},
+			want: &types.LLMRequestBody{
+				Responses: &types.ResponsesRequest{
+					Input: types.ResponsesInput{Items: []types.ResponsesInputItem{
+						{Role: "user", Content: types.ResponsesContent{Raw: "what is the weather?"}},
+						{Type: "function_call", CallID: "call_1", Name: "get_weather", Arguments: `{"city":"Paris"}`},
+						{Type: "function_call_output", CallID: "call_1", Output: "sunny"},
+						{Role: "user", Content: types.ResponsesContent{Parts: []types.ResponsesContentPart{
+							{Type: "input_text", Text: "and this image?"},
+							{Type: "input_image", ImageURL: "https://example.com/image.png"},
+						}}},
+					}},
+					PreviousResponseID: "resp_123",
+					CacheSalt:          "salt",
+					User:               "user-1",
+				},
+			},
+		},
+		{
+			name: "responses request with empty input",
+			body: map[string]any{
+				"model": "test",
+				"input": []any{},
+			},
+			wantErr: true,
+		},
+		{
+			name: "responses request with text input identified by path",
+			body: map[string]any{
+				"model": "test",
+				"input": "hello",
+			},
+			path: "/v1/responses?stream=false",
+			want: &types.LLMRequestBody{
+				Responses: &types.ResponsesRequest{
+					Input: types.ResponsesInput{Raw: "hello"},
+				},
+			},
+		},
+		{
+			name: "embeddings request with text input",
+			body: map[string]any{
+				"model": "test",
+				"input": "hello",
+				"user":  "user-1",
+			},
+			want: &types.LLMRequestBody{
+				Embeddings: &types.EmbeddingsRequest{
+					Input: types.Prompt{Raw: "hello"},
+					User:  "user-1",
+				},
+			},
+		},
+		{
+			name: "embeddings request with text array input",
+			body: map[string]any{
+				"model": "test",
+				"input": []any{"hello", "world"},
+			},
+			path: "/v1/embeddings",
+			want: &types.LLMRequestBody{
+				Embeddings: &types.EmbeddingsRequest{
+					Input: types.Prompt{Texts: []string{"hello", "world"}},
+				},
+			},
+		},
+		{
+			name: "embeddings request with token input",
+			body: map[string]any{
+				"model": "test",
+				"input": []any{1, 2, 3},
+			},
+			want: &types.LLMRequestBody{
+				Embeddings: &types.EmbeddingsRequest{
+					Input: types.Prompt{Tokens: [][]uint32{{1, 2, 3}}},
+				},
+			},
+		},
+		{
+			name: "embeddings request with token arrays input",
+			body: map[string]any{
+				"model": "test",
+				"input": []any{[]any{1, 2}, []any{3}},
+			},
+			path: "/v1/embeddings",
+			want: &types.LLMRequestBody{
+				Embeddings: &types.EmbeddingsRequest{
+					Input: types.Prompt{Tokens: [][]uint32{{1, 2}, {3}}},
+				},
+			},
+		},
+		{
+			name: "embeddings request with empty input",
+			body: map[string]any{
+				"model": "test",
+				"input": "",
+			},
+			path:    "/v1/embeddings",
+			wantErr: true,
+		},
+		{
+			name: "rerank request",
+			body: map[string]any{
+				"model":     "test",
+				"query":     "what is a cat?",
+				"documents": []any{"a cat is an animal", map[string]any{"text": "a car is a vehicle"}},
+				"top_n":     1,
+			},
+			want: &types.LLMRequestBody{
+				Rerank: &types.RerankRequest{
+					Query:     "what is a cat?",
+					Documents: []types.RerankDocument{{Text: "a cat is an animal"}, {Text: "a car is a vehicle"}},
+				},
+			},
+		},
+		{
+			name: "rerank request without documents",
+			body: map[string]any{
+				"model": "test",
+				"query": "what is a cat?",
+			},
+			path:    "/v2/rerank",
+			wantErr: true,
+		},
+		{
+			name: "chat completions body on the completions path",
+			body: map[string]any{
+				"model":    "test",
+				"messages": []any{map[string]any{"role": "user", "content": "hello"}},
+			},
+			path:    "/v1/completions",
+			wantErr: true,
+		},
+		{
+			name: "unknown path falls back to the body fields",
+			body: map[string]any{
+				"model":  "test",
+				"prompt": "test prompt",
+			},
+			path: "/generate",
+			want: &types.LLMRequestBody{
+				Completions: &types.CompletionsRequest{
+					Prompt: types.Prompt{Raw: "test prompt"},
+				},
+			},
+		},
+		{
+			name: "anthropic messages request",
+			body: map[string]any{
+				"model":      "test",
+				"max_tokens": 1024,
+				"system":     []any{map[string]any{"type": "text", "text": "be concise"}},
+				"messages": []any{
+					map[string]any{
+						"role": "user",
+						"content": []any{
+							map[string]any{"type": "text", "text": "what is in this image?"},
+							map[string]any{"type": "image", "source": map[string]any{"type": "base64", "media_type": "image/png", "data": "iVBORw0KGgo="}},
+						},
+					},
+					map[string]any{
+						"role":    "assistant",
+						"content": []any{map[string]any{"type": "tool_use", "id": "toolu_1", "name": "describe", "input": map[string]any{"detail": "high"}}},
+					},
+					map[string]any{
+						"role":    "user",
+						"content": []any{map[string]any{"type": "tool_result", "tool_use_id": "toolu_1", "content": "a cat"}},
+					},
+				},
+				"metadata": map[string]any{"user_id": "user-1"},
+			},
+			want: &types.LLMRequestBody{
+				AnthropicMessages: &types.AnthropicMessagesRequest{
+					System: types.Content{Structured: []types.ContentBlock{{Type: "text", Text: "be concise"}}},
+					Messages: []types.Message{
+						{Role: "user", Content: types.Content{Structured: []types.ContentBlock{
+							{Type: "text", Text: "what is in this image?"},
+							{Type: "image", Source: &types.MediaSource{Type: "base64", MediaType: "image/png", Data: "iVBORw0KGgo="}},
+						}}},
+						{Role: "assistant", Content: types.Content{Structured: []types.ContentBlock{
+							{Type: "tool_use", ID: "toolu_1", Name: "describe", Input: map[string]any{"detail": "high"}},
+						}}},
+						{Role: "user", Content: types.Content{Structured: []types.ContentBlock{
+							{Type: "tool_result", ToolUseID: "toolu_1", Content: &types.Content{Raw: "a cat"}},
+						}}},
+					},
+					MaxTokens: 1024,
+					Metadata:  types.AnthropicMetadata{UserID: "user-1"},
+				},
+			},
+		},
+		{
+			name: "anthropic messages request identified by path",
+			body: map[string]any{
+				"model":      "test",
+				"max_tokens": 1024,
+				"messages":   []any{map[string]any{"role": "user", "content": "hello"}},
+			},
+			path: "/v1/messages",
+			want: &types.LLMRequestBody{
+				AnthropicMessages: &types.AnthropicMessagesRequest{
+					Messages:  []types.Message{{Role: "user", Content: types.Content{Raw: "hello"}}},
+					MaxTokens: 1024,
+				},
+			},
+		},
+		{
+			name: "anthropic messages request without messages",
+			body: map[string]any{
+				"model":  "test",
+				"system": "be concise",
+			},
+			wantErr: true,
+		},
+		{
+			name: "completions request with an array of prompts",
+			body: map[string]any{
+				"model":  "test",
+				"prompt": []any{"first prompt", "second prompt"},
+			},
+			path: "/v1/completions",
+			want: &types.LLMRequestBody{
+				Completions: &types.CompletionsRequest{
+					Prompt: types.Prompt{Texts: []string{"first prompt", "second prompt"}},
+				},
+			},
+		},
+		{
+			name: "completions request with token IDs",
+			body: map[string]any{
+				"model":  "test",
+				"prompt": []any{1, 2, 3},
+			},
+			want: &types.LLMRequestBody{
+				Completions: &types.CompletionsRequest{
+					Prompt: types.Prompt{Tokens: [][]uint32{{1, 2, 3}}},
+				},
+			},
+		},
+		{
+			name: "completions request with a batch of token IDs",
+			body: map[string]any{
+				"model":  "test",
+				"prompt": []any{[]any{1, 2}, []any{3}},
+			},
+			path: "/v1/completions",
+			want: &types.LLMRequestBody{
+				Completions: &types.CompletionsRequest{
+					Prompt: types.Prompt{Tokens: [][]uint32{{1, 2}, {3}}},
+				},
+			},
+		},
+		{
+			name: "completions request with an invalid prompt",
+			body: map[string]any{
+				"model":  "test",
+				"prompt": map[string]any{"text": "hello"},
+			},
+			path:    "/v1/completions",
+			wantErr: true,
+		},
+		{
+			name: "open inference infer request",
+			body: map[string]any{
+				"id": "req-1",
+				"inputs": []any{
+					map[string]any{"name": "text_input", "shape": []any{1, 1}, "datatype": "BYTES", "data": []any{[]any{"what is a cat?"}}},
+					map[string]any{"name": "max_tokens", "shape": []any{1}, "datatype": "INT32", "data": []any{64}},
+				},
+			},
+			path: "/v2/models/llama/infer",
+			want: &types.LLMRequestBody{
+				OpenInference: &types.OpenInferenceRequest{
+					ID: "req-1",
+					Inputs: []types.InferTensor{
+						{Name: "text_input", Shape: []int64{1, 1}, Datatype: "BYTES", Data: []any{[]any{"what is a cat?"}}},
+						{Name: "max_tokens", Shape: []int64{1}, Datatype: "INT32", Data: []any{float64(64)}},
+					},
+				},
+			},
+		},
+		{
+			name: "open inference generate request",
+			body: map[string]any{
+				"text_input": "what is a cat?",
+				"parameters": map[string]any{"stream": false, "temperature": 0},
+			},
+			path: "/v2/models/llama/versions/1/generate_stream",
+			want: &types.LLMRequestBody{
+				OpenInference: &types.OpenInferenceRequest{TextInput: "what is a cat?"},
+			},
+		},
+		{
+			name: "open inference request identified by its input tensors",
+			body: map[string]any{
+				"inputs": []any{map[string]any{"name": "prompt", "shape": []any{1}, "datatype": "BYTES", "data": []any{"hello"}}},
+			},
+			want: &types.LLMRequestBody{
+				OpenInference: &types.OpenInferenceRequest{
+					Inputs: []types.InferTensor{{Name: "prompt", Shape: []int64{1}, Datatype: "BYTES", Data: []any{"hello"}}},
+				},
+			},
+		},
+		{
+			name: "open inference request without inputs",
+			body: map[string]any{
+				"parameters": map[string]any{"max_tokens": 64},
+			},
+			path:    "/v2/models/llama/infer",
+			wantErr: true,
+		},
+		{
+			name: "responses request with invalid input format",
+			body: map[string]any{
+				"model": "test",
+				"input": 123,
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := ExtractRequestBody(tt.body)
+			rawBody, err := json.Marshal(tt.body)
+			if err != nil {
+				t.Fatalf("failed to marshal the request body: %v", err)
+			}
+			got, err := ExtractRequestBody(rawBody, tt.path)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("ExtractRequestBody() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -384,9 +744,14 @@ func BenchmarkExtractRequestData_Completions(b *testing.B) {
 		"prompt": "test prompt",
 	}
 
+	rawBody, err := json.Marshal(body)
+	if err != nil {
+		b.Fatal(err)
+	}
+
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_, err := ExtractRequestBody(body)
+		_, err := ExtractRequestBody(rawBody, "")
 		if err != nil {
 			b.Fatal(err)
 		}
@@ -401,9 +766,14 @@ func BenchmarkExtractRequestData_ChatCompletions(b *testing.B) {
 		},
 	}
 
+	rawBody, err := json.Marshal(body)
+	if err != nil {
+		b.Fatal(err)
+	}
+
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_, err := ExtractRequestBody(body)
+		_, err := ExtractRequestBody(rawBody, "")
 		if err != nil {
 			b.Fatal(err)
 		}
@@ -425,9 +795,14 @@ func BenchmarkExtractRequestData_ChatCompletionsWithOptionals(b *testing.B) {
 		"chat_template_kwargs":         map[string]any{"key": "value"},
 	}
 
+	rawBody, err := json.Marshal(body)
+	if err != nil {
+		b.Fatal(err)
+	}
+
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_, err := ExtractRequestBody(body)
+		_, err := ExtractRequestBody(rawBody, "")
 		if err != nil {
 			b.Fatal(err)
 		}
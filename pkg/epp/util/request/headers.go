@@ -28,6 +28,8 @@ import (
 
 const (
 	RequestIdHeaderKey = "x-request-id"
+	// PathHeaderKey is the pseudo-header carrying the request path.
+	PathHeaderKey = ":path"
 )
 
 var (
@@ -0,0 +1,67 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package request
+
+import (
+	"net/url"
+	"strings"
+)
+
+// openInferenceModelsPrefix is the path prefix of the Open Inference Protocol (KServe v2) model endpoints, e.g.
+// /v2/models/{name}/infer or /v2/models/{name}/versions/{version}/generate.
+const openInferenceModelsPrefix = "/v2/models/"
+
+// ModelFromPath returns the model name carried by an Open Inference Protocol request path, or an empty string if the
+// path is not an Open Inference Protocol model endpoint.
+func ModelFromPath(path string) string {
+	escaped, _, ok := cutOpenInferenceModel(path)
+	if !ok {
+		return ""
+	}
+	model, err := url.PathUnescape(escaped)
+	if err != nil {
+		return ""
+	}
+	return model
+}
+
+// ReplaceModelInPath returns the given Open Inference Protocol request path with its model name replaced by model.
+// Paths that do not carry a model name are returned unchanged.
+func ReplaceModelInPath(path string, model string) string {
+	escaped, rest, ok := cutOpenInferenceModel(path)
+	if !ok {
+		return path
+	}
+	prefix := path[:len(path)-len(rest)-len(escaped)]
+	return prefix + url.PathEscape(model) + rest
+}
+
+// cutOpenInferenceModel splits an Open Inference Protocol request path around its (escaped) model name segment.
+func cutOpenInferenceModel(path string) (model string, rest string, ok bool) {
+	_, afterPrefix, found := strings.Cut(path, openInferenceModelsPrefix)
+	if !found {
+		return "", "", false
+	}
+	end := strings.IndexAny(afterPrefix, "/?")
+	if end < 0 {
+		end = len(afterPrefix)
+	}
+	if end == 0 {
+		return "", "", false
+	}
+	return afterPrefix[:end], afterPrefix[end:], true
+}
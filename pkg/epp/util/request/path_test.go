@@ -0,0 +1,65 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package request
+
+import "testing"
+
+func TestModelFromPath(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{name: "infer", path: "/v2/models/llama/infer", want: "llama"},
+		{name: "versioned generate", path: "/v2/models/llama/versions/2/generate", want: "llama"},
+		{name: "query string", path: "/v2/models/llama?verbose=true", want: "llama"},
+		{name: "escaped model name", path: "/v2/models/meta-llama%2FLlama-3.1-8B/infer", want: "meta-llama/Llama-3.1-8B"},
+		{name: "openai path", path: "/v1/chat/completions", want: ""},
+		{name: "models list", path: "/v2/models/", want: ""},
+		{name: "empty path", path: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ModelFromPath(tt.path); got != tt.want {
+				t.Errorf("ModelFromPath(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReplaceModelInPath(t *testing.T) {
+	tests := []struct {
+		name  string
+		path  string
+		model string
+		want  string
+	}{
+		{name: "infer", path: "/v2/models/llama/infer", model: "llama-lora", want: "/v2/models/llama-lora/infer"},
+		{name: "versioned generate", path: "/v2/models/llama/versions/2/generate_stream", model: "mistral", want: "/v2/models/mistral/versions/2/generate_stream"},
+		{name: "model name to escape", path: "/v2/models/llama/infer", model: "meta-llama/Llama-3.1-8B", want: "/v2/models/meta-llama%2FLlama-3.1-8B/infer"},
+		{name: "openai path is unchanged", path: "/v1/completions", model: "mistral", want: "/v1/completions"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ReplaceModelInPath(tt.path, tt.model); got != tt.want {
+				t.Errorf("ReplaceModelInPath(%q, %q) = %q, want %q", tt.path, tt.model, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,231 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package request
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+)
+
+// The functions in this file work on the raw bytes of a JSON request body, so that the body can be forwarded to the
+// model server as it was received: without reordering its keys, losing the precision of its numbers or paying for a
+// full decode and re-encode of large prompts.
+
+var (
+	errNotObject   = errors.New("request body is not a JSON object")
+	errInvalidJSON = errors.New("request body is not valid JSON")
+	errNoModel     = errors.New("request body has no model field")
+)
+
+// IsJSONObject returns true if the body is a valid JSON document holding an object.
+func IsJSONObject(body []byte) bool {
+	i := skipSpace(body, 0)
+	return i < len(body) && body[i] == '{' && json.Valid(body)
+}
+
+// BodyModel returns the value of the top-level model field of a JSON object body. If the field is repeated, the last
+// value is returned, like encoding/json and the model servers do.
+func BodyModel(body []byte) (string, bool) {
+	start, end, err := modelValue(body)
+	if err != nil {
+		return "", false
+	}
+	var model string
+	if err := json.Unmarshal(body[start:end], &model); err != nil {
+		return "", false
+	}
+	return model, true
+}
+
+// ReplaceBodyModel returns a copy of the JSON object body with the value of its top-level model field replaced by
+// model. All other bytes of the body are left untouched.
+func ReplaceBodyModel(body []byte, model string) ([]byte, error) {
+	start, end, err := modelValue(body)
+	if err != nil {
+		return nil, err
+	}
+	value, err := json.Marshal(model)
+	if err != nil {
+		return nil, err
+	}
+	replaced := make([]byte, 0, len(body)-(end-start)+len(value))
+	replaced = append(replaced, body[:start]...)
+	replaced = append(replaced, value...)
+	return append(replaced, body[end:]...), nil
+}
+
+// modelValue returns the offsets of the value of the last top-level model field of a JSON object body.
+func modelValue(body []byte) (start int, end int, err error) {
+	start, end = -1, -1
+	err = walkTopLevelFields(body, func(key string, valueStart, valueEnd int) {
+		if key == "model" {
+			start, end = valueStart, valueEnd
+		}
+	})
+	if err == nil && start < 0 {
+		err = errNoModel
+	}
+	return start, end, err
+}
+
+// topLevelFields returns the raw values of the top-level fields of a JSON object body, keyed by field name.
+// The values share the memory of the body.
+func topLevelFields(body []byte) (map[string][]byte, error) {
+	fields := map[string][]byte{}
+	err := walkTopLevelFields(body, func(key string, start, end int) {
+		fields[key] = body[start:end]
+	})
+	return fields, err
+}
+
+// walkTopLevelFields calls fn with the name and the value offsets of every top-level field of a JSON object body,
+// in the order of the body. Nested values are skipped over without being decoded.
+func walkTopLevelFields(body []byte, fn func(key string, start, end int)) error {
+	i := skipSpace(body, 0)
+	if i >= len(body) || body[i] != '{' {
+		return errNotObject
+	}
+	i = skipSpace(body, i+1)
+	if i < len(body) && body[i] == '}' {
+		return nil
+	}
+	for {
+		if i >= len(body) || body[i] != '"' {
+			return errInvalidJSON
+		}
+		keyEnd, err := skipString(body, i)
+		if err != nil {
+			return err
+		}
+		key, err := decodeKey(body[i:keyEnd])
+		if err != nil {
+			return err
+		}
+		i = skipSpace(body, keyEnd)
+		if i >= len(body) || body[i] != ':' {
+			return errInvalidJSON
+		}
+		start := skipSpace(body, i+1)
+		end, err := skipValue(body, start)
+		if err != nil {
+			return err
+		}
+		fn(key, start, end)
+
+		i = skipSpace(body, end)
+		if i >= len(body) {
+			return errInvalidJSON
+		}
+		switch body[i] {
+		case ',':
+			i = skipSpace(body, i+1)
+		case '}':
+			return nil
+		default:
+			return errInvalidJSON
+		}
+	}
+}
+
+// decodeKey returns the name of a quoted object key, only unescaping it when it holds escape sequences.
+func decodeKey(quoted []byte) (string, error) {
+	if bytes.IndexByte(quoted, '\\') < 0 {
+		return string(quoted[1 : len(quoted)-1]), nil
+	}
+	var key string
+	err := json.Unmarshal(quoted, &key)
+	return key, err
+}
+
+// skipSpace returns the offset of the first non-whitespace byte at or after i.
+func skipSpace(body []byte, i int) int {
+	for i < len(body) {
+		switch body[i] {
+		case ' ', '\t', '\n', '\r':
+			i++
+		default:
+			return i
+		}
+	}
+	return i
+}
+
+// skipString returns the offset following the string starting with the quote at i.
+func skipString(body []byte, i int) (int, error) {
+	j := i + 1
+	for {
+		k := bytes.IndexByte(body[j:], '"')
+		if k < 0 {
+			return 0, errInvalidJSON
+		}
+		j += k
+		// The quote ends the string unless it is escaped, i.e. preceded by an odd number of backslashes.
+		backslashes := 0
+		for b := j - 1; b > i && body[b] == '\\'; b-- {
+			backslashes++
+		}
+		if backslashes%2 == 0 {
+			return j + 1, nil
+		}
+		j++
+	}
+}
+
+// skipValue returns the offset following the value starting at i.
+func skipValue(body []byte, i int) (int, error) {
+	if i >= len(body) {
+		return 0, errInvalidJSON
+	}
+	switch body[i] {
+	case '"':
+		return skipString(body, i)
+	case '{', '[':
+		depth := 0
+		for j := i; j < len(body); j++ {
+			switch body[j] {
+			case '"':
+				end, err := skipString(body, j)
+				if err != nil {
+					return 0, err
+				}
+				j = end - 1
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+				if depth == 0 {
+					return j + 1, nil
+				}
+			}
+		}
+		return 0, errInvalidJSON
+	default: // number, true, false or null
+		j := i
+		for j < len(body) {
+			switch body[j] {
+			case ',', '}', ']', ' ', '\t', '\n', '\r':
+				if j == i {
+					return 0, errInvalidJSON
+				}
+				return j, nil
+			}
+			j++
+		}
+		return 0, errInvalidJSON
+	}
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package request
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestBodyModel(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    string
+		wantOK  bool
+		wantObj bool
+	}{
+		{name: "compact", body: `{"model":"llama","prompt":"hi"}`, want: "llama", wantOK: true, wantObj: true},
+		{name: "whitespace", body: " {\n\t\"prompt\" : \"hi\" ,\r\n \"model\" : \"llama\"\n}\n", want: "llama", wantOK: true, wantObj: true},
+		{name: "escaped value", body: `{"model":"llama\"/\\x"}`, want: `llama"/\x`, wantOK: true, wantObj: true},
+		{name: "escaped key", body: `{"mod\u0065l":"llama"}`, want: "llama", wantOK: true, wantObj: true},
+		{name: "nested model is ignored", body: `{"metadata":{"model":"nested"},"messages":[{"model":"x"}],"model":"llama"}`, want: "llama", wantOK: true, wantObj: true},
+		{name: "quotes and brackets in strings", body: `{"prompt":"a \"}\" ] {","model":"llama"}`, want: "llama", wantOK: true, wantObj: true},
+		{name: "last of repeated fields", body: `{"model":"first","model":"last"}`, want: "last", wantOK: true, wantObj: true},
+		{name: "missing", body: `{"prompt":"hi"}`, wantObj: true},
+		{name: "not a string", body: `{"model":42}`, wantObj: true},
+		{name: "empty object", body: `{}`, wantObj: true},
+		{name: "array", body: `[{"model":"llama"}]`},
+		{name: "truncated", body: `{"model":"llama","prompt":"hi`},
+		{name: "empty", body: ``},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := BodyModel([]byte(tt.body))
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("BodyModel() = (%q, %v), want (%q, %v)", got, ok, tt.want, tt.wantOK)
+			}
+			if obj := IsJSONObject([]byte(tt.body)); obj != tt.wantObj {
+				t.Errorf("IsJSONObject() = %v, want %v", obj, tt.wantObj)
+			}
+		})
+	}
+}
+
+func TestReplaceBodyModel(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		model   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "keeps the order, the formatting and the numbers of the body",
+			body:  `{"prompt": "hi", "model": "llama", "seed": 12345678901234567890, "temperature": 1e-3}`,
+			model: "llama-lora",
+			want:  `{"prompt": "hi", "model": "llama-lora", "seed": 12345678901234567890, "temperature": 1e-3}`,
+		},
+		{
+			name:  "escapes the model",
+			body:  `{"model":"llama"}`,
+			model: `a"b`,
+			want:  `{"model":"a\"b"}`,
+		},
+		{
+			name:  "replaces the last of repeated fields",
+			body:  `{"model":"first","model":"last"}`,
+			model: "target",
+			want:  `{"model":"first","model":"target"}`,
+		},
+		{
+			name:    "missing model",
+			body:    `{"prompt":"hi"}`,
+			model:   "target",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReplaceBodyModel([]byte(tt.body), tt.model)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReplaceBodyModel() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if string(got) != tt.want {
+				t.Errorf("ReplaceBodyModel() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// largeCompletionsBody returns a completions request body with a prompt of roughly the given number of tokens.
+func largeCompletionsBody(tokens int) []byte {
+	body, _ := json.Marshal(map[string]any{
+		"model":       "food-review",
+		"prompt":      strings.Repeat("tell me about ", tokens/3),
+		"max_tokens":  100,
+		"temperature": 0,
+		"seed":        12345678901234567,
+	})
+	return body
+}
+
+// BenchmarkRequestBody_DecodedMap measures the previous request body path: decoding the body into a map, setting the
+// model, re-encoding the map to extract the typed request body and re-encoding it again to forward it.
+func BenchmarkRequestBody_DecodedMap(b *testing.B) {
+	body := largeCompletionsBody(100000)
+	b.SetBytes(int64(len(body)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var bodyMap map[string]any
+		if err := json.Unmarshal(body, &bodyMap); err != nil {
+			b.Fatal(err)
+		}
+		bodyMap["model"] = "food-review-1"
+		extracted, err := json.Marshal(bodyMap)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := extractCompletions(extracted); err != nil {
+			b.Fatal(err)
+		}
+		if _, err := json.Marshal(bodyMap); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkRequestBody_Raw measures the raw request body path: validating the body, reading and replacing the model
+// in place and extracting the typed request body from the raw bytes.
+func BenchmarkRequestBody_Raw(b *testing.B) {
+	body := largeCompletionsBody(100000)
+	b.SetBytes(int64(len(body)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if !IsJSONObject(body) {
+			b.Fatal("invalid body")
+		}
+		if _, ok := BodyModel(body); !ok {
+			b.Fatal("model not found")
+		}
+		forwarded, err := ReplaceBodyModel(body, "food-review-1")
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := ExtractRequestBody(forwarded, "/v1/completions"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package window provides measures over a sliding window of time.
+package window
+
+import "time"
+
+// Counter counts values and sums them up over a sliding window of time. The values are counted in buckets covering a
+// fraction of the window each, so that its memory does not grow with the rate of the values. The window slides by
+// one bucket at a time. It is not safe for concurrent use.
+type Counter struct {
+	window  time.Duration
+	buckets []bucket
+}
+
+type bucket struct {
+	start time.Time
+	count int
+	sum   float64
+}
+
+// NewCounter returns a Counter over a window of the given duration, divided into the given number of buckets.
+func NewCounter(window time.Duration, buckets int) *Counter {
+	return &Counter{window: window, buckets: make([]bucket, buckets)}
+}
+
+// BucketWidth returns the duration covered by each bucket of the window.
+func (c *Counter) BucketWidth() time.Duration {
+	return c.window / time.Duration(len(c.buckets))
+}
+
+// Add counts a value at the given time.
+func (c *Counter) Add(now time.Time, value float64) {
+	width := c.BucketWidth()
+	start := now.Truncate(width)
+	b := &c.buckets[(start.UnixNano()/int64(width))%int64(len(c.buckets))]
+	if !b.start.Equal(start) {
+		*b = bucket{start: start}
+	}
+	b.count++
+	b.sum += value
+}
+
+// Total returns the number of values and their sum over the window ending at the given time.
+func (c *Counter) Total(now time.Time) (int, float64) {
+	count, sum := 0, 0.0
+	for _, b := range c.buckets {
+		if b.count > 0 && now.Sub(b.start) < c.window {
+			count += b.count
+			sum += b.sum
+		}
+	}
+	return count, sum
+}
+
+// Reset forgets all of the values counted.
+func (c *Counter) Reset() {
+	clear(c.buckets)
+}
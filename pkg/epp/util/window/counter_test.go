@@ -0,0 +1,55 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package window
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCounter(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	counter := NewCounter(10*time.Second, 10)
+	assert.Equal(t, time.Second, counter.BucketWidth())
+
+	count, sum := counter.Total(start)
+	assert.Zero(t, count)
+	assert.Zero(t, sum)
+
+	// One value per 500ms during 10s.
+	for i := range 20 {
+		counter.Add(start.Add(time.Duration(i)*500*time.Millisecond), float64(i))
+	}
+	count, sum = counter.Total(start.Add(10 * time.Second))
+	assert.Equal(t, 18, count, "The values of the first bucket should have left the window")
+	assert.Equal(t, 189.0, sum)
+
+	// The buckets are reused once the window slid past them: only the values 12 to 19 are left in the window.
+	counter.Add(start.Add(15*time.Second), 100)
+	count, sum = counter.Total(start.Add(15 * time.Second))
+	assert.Equal(t, 9, count)
+	assert.Equal(t, 224.0, sum)
+
+	count, _ = counter.Total(start.Add(time.Minute))
+	assert.Zero(t, count)
+
+	counter.Reset()
+	count, _ = counter.Total(start.Add(15 * time.Second))
+	assert.Zero(t, count)
+}
@@ -340,16 +340,16 @@ func TestFullDuplexStreamed_KubeInferenceObjectiveRequest(t *testing.T) {
 			requests: ReqResponseOnly(
 				map[string]string{"content-type": "text/event-stream", "status": "200"},
 				// Chunk 1: Simulate a standard data chunk.
-				`data: {}`,
+				`data: {}`+"\n\n",
 				// Chunk 2: Usage data + DONE signal.
-				`data: {"usage":{"prompt_tokens":7,"total_tokens":17,"completion_tokens":10}}`+"\n"+`data: [DONE]`,
+				`data: {"usage":{"prompt_tokens":7,"total_tokens":17,"completion_tokens":10}}`+"\n\n"+`data: [DONE]`+"\n\n",
 				"", // EndOfStream
 			),
 			pods:         []podState{P(0, 4, 0.2, modelSheddableTarget)},
 			waitForModel: modelSheddable,
 			wantResponses: ExpectStreamResp(
-				`data: {}`,
-				`data: {"usage":{"prompt_tokens":7,"total_tokens":17,"completion_tokens":10}}`+"\n"+`data: [DONE]`,
+				`data: {}`+"\n\n",
+				`data: {"usage":{"prompt_tokens":7,"total_tokens":17,"completion_tokens":10}}`+"\n\n"+`data: [DONE]`+"\n\n",
 				"",
 			),
 			// Labels are empty because we skipped the Request phase.